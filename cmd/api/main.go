@@ -6,11 +6,14 @@ import (
 	"strconv"
 	"time"
 
+	"insider-go-backend/internal/config"
 	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/jobs"
 	"insider-go-backend/internal/logging"
 	mw "insider-go-backend/internal/middleware"
 	"insider-go-backend/internal/processor"
 	"insider-go-backend/internal/routes"
+	"insider-go-backend/internal/services"
 
 	"context"
 	"log"
@@ -49,8 +52,8 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", mw.IdempotencyHeader},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -74,10 +77,63 @@ func main() {
 	if getenv("TXPROC_ENABLED", "true") == "true" {
 		workers := getenvInt("TXPROC_WORKERS", 4)
 		qcap := getenvInt("TXPROC_QUEUE", 256)
-		processor.StartDefault(workers, qcap)
+		p := processor.StartDefault(workers, qcap)
 		log.Printf("Transaction processor started (workers=%d, queue=%d)", workers, qcap)
+		// yarım kalan pain.001 talimatları
+		if n, err := p.ResumePaymentInitiations(); err != nil {
+			log.Printf("Resuming payment initiations failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Resumed %d payment initiations", n)
+		}
 	}
 
+	// Arka plan işleri: shutdown'da jobCtx iptal edilir
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	jobs.Every(jobCtx, "idempotency.purge", config.GetIdempotency().PurgeInterval, func(context.Context) error {
+		_, err := services.PurgeExpiredIdempotencyKeys()
+		return err
+	})
+	jobs.Every(jobCtx, "holds.expire", config.GetHolds().SweepInterval, func(context.Context) error {
+		_, err := services.ExpireHolds()
+		return err
+	})
+	jobs.Every(jobCtx, "scheduled_transfers.run", config.GetScheduler().Interval, func(ctx context.Context) error {
+		_, err := services.RunDueScheduledTransfers(ctx)
+		return err
+	})
+	jobs.Every(jobCtx, "standing_orders.run", config.GetScheduler().Interval, func(ctx context.Context) error {
+		_, err := services.RunDueStandingOrders(ctx)
+		return err
+	})
+	jobs.Every(jobCtx, "overdraft.interest", config.GetOverdraft().Interval, func(context.Context) error {
+		_, err := services.AccrueOverdraftInterest(time.Now())
+		return err
+	})
+	jobs.Every(jobCtx, "interest.accrue", config.GetInterest().Interval, func(context.Context) error {
+		_, err := services.AccrueInterest(time.Now())
+		return err
+	})
+	jobs.Every(jobCtx, "balances.checkpoint", config.GetCheckpoints().Interval, func(context.Context) error {
+		_, err := services.WriteBalanceCheckpoints(time.Now(), config.GetCheckpoints().Lag)
+		return err
+	})
+	jobs.Every(jobCtx, "payment_requests.expire", config.GetPaymentRequests().SweepInterval, func(context.Context) error {
+		_, err := services.ExpirePaymentRequests()
+		return err
+	})
+	jobs.Every(jobCtx, "escrows.timeout", config.GetEscrow().SweepInterval, func(context.Context) error {
+		_, err := services.SettleExpiredEscrows()
+		return err
+	})
+	jobs.Every(jobCtx, "statements.monthly", config.GetStatements().Interval, func(context.Context) error {
+		_, err := services.GenerateMonthlyStatements(time.Now())
+		return err
+	})
+	jobs.Every(jobCtx, "ledger.reconcile", config.GetReconcileInterval(), func(context.Context) error {
+		_, err := services.Reconcile(false, 0)
+		return err
+	})
+
 	// Server başlat
 	go func() {
 		fmt.Printf("Server running at http://localhost:%s\n", port)
@@ -98,6 +154,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	// arka plan işlerini durdur
+	stopJobs()
+	jobs.Wait()
 	// işlemciyi durdur
 	processor.StopDefault()
 	// log dosyasını kapat
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    account TEXT NOT NULL,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(18,2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account ON ledger_entries (account);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_id ON ledger_entries (user_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_created_at ON ledger_entries (created_at);
+
+-- Mevcut transaction'lar için geriye dönük kayıtlar (credit/debit karşı tarafı: settlement hesabı)
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT id, 'user:' || to_user_id, to_user_id, amount, created_at FROM transactions WHERE type = 'credit'
+UNION ALL
+SELECT id, 'system:settlement', NULL, -amount, created_at FROM transactions WHERE type = 'credit'
+UNION ALL
+SELECT id, 'user:' || from_user_id, from_user_id, -amount, created_at FROM transactions WHERE type = 'debit'
+UNION ALL
+SELECT id, 'system:settlement', NULL, amount, created_at FROM transactions WHERE type = 'debit'
+UNION ALL
+SELECT id, 'user:' || from_user_id, from_user_id, -amount, created_at FROM transactions WHERE type = 'transfer'
+UNION ALL
+SELECT id, 'user:' || to_user_id, to_user_id, amount, created_at FROM transactions WHERE type = 'transfer';
+
+-- balances ile kayıtlar arasındaki fark (SetBalance / açılış bakiyesi) düzeltme olarak yazılır,
+-- böylece balances tablosu ledger'ın birebir projeksiyonu olur
+WITH diff AS (
+    SELECT b.user_id, b.amount - COALESCE(SUM(e.amount), 0) AS delta
+    FROM balances b
+    LEFT JOIN ledger_entries e ON e.user_id = b.user_id
+    GROUP BY b.user_id, b.amount
+    HAVING b.amount - COALESCE(SUM(e.amount), 0) <> 0
+), adj AS (
+    INSERT INTO transactions (from_user_id, to_user_id, amount, type, status, created_at)
+    SELECT user_id, user_id, delta, 'adjustment', 'completed', NOW() FROM diff
+    RETURNING id, to_user_id, amount, created_at
+)
+INSERT INTO ledger_entries (transaction_id, account, user_id, amount, created_at)
+SELECT id, 'user:' || to_user_id, to_user_id, amount, created_at FROM adj
+UNION ALL
+SELECT id, 'system:adjustment', NULL, -amount, created_at FROM adj;
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_ledger_entries_currency;
+ALTER TABLE ledger_entries ALTER COLUMN amount TYPE NUMERIC(18,2);
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS currency;
+
+DROP INDEX IF EXISTS idx_transactions_currency;
+ALTER TABLE transactions ALTER COLUMN amount TYPE NUMERIC(18,2);
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+
+-- Not: birden fazla cüzdanı olan kullanıcılar varsa varsayılan dışındaki cüzdanlar silinir
+DELETE FROM balances WHERE currency <> 'USD';
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_pkey;
+ALTER TABLE balances ADD PRIMARY KEY (user_id);
+ALTER TABLE balances ALTER COLUMN amount TYPE NUMERIC(18,2);
+ALTER TABLE balances DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,14 @@
+-- Para birimi başına cüzdan: balances birincil anahtarı (user_id, currency) olur
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE balances DROP CONSTRAINT IF EXISTS balances_pkey;
+ALTER TABLE balances ADD PRIMARY KEY (user_id, currency);
+ALTER TABLE balances ALTER COLUMN amount TYPE NUMERIC(20,4);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE transactions ALTER COLUMN amount TYPE NUMERIC(20,4);
+CREATE INDEX IF NOT EXISTS idx_transactions_currency ON transactions (currency);
+
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE ledger_entries ALTER COLUMN currency DROP DEFAULT;
+ALTER TABLE ledger_entries ALTER COLUMN amount TYPE NUMERIC(20,4);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_currency ON ledger_entries (currency);
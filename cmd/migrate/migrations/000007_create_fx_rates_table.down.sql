@@ -0,0 +1,9 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS fx_rate_id,
+    DROP COLUMN IF EXISTS fx_spread_bps,
+    DROP COLUMN IF EXISTS fx_mid_rate,
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS counter_currency,
+    DROP COLUMN IF EXISTS counter_amount;
+
+DROP TABLE IF EXISTS fx_rates;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id BIGSERIAL PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(20,8) NOT NULL CHECK (rate > 0),
+    spread_bps INT CHECK (spread_bps >= 0 AND spread_bps < 10000),
+    valid_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_to TIMESTAMPTZ,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_rates_pair ON fx_rates (base_currency, quote_currency, valid_from DESC);
+
+-- Çapraz kur transferi snapshot'ı
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS counter_amount NUMERIC(20,4),
+    ADD COLUMN IF NOT EXISTS counter_currency CHAR(3),
+    ADD COLUMN IF NOT EXISTS fx_rate NUMERIC(20,8),
+    ADD COLUMN IF NOT EXISTS fx_mid_rate NUMERIC(20,8),
+    ADD COLUMN IF NOT EXISTS fx_spread_bps INT,
+    ADD COLUMN IF NOT EXISTS fx_rate_id BIGINT REFERENCES fx_rates(id) ON DELETE SET NULL;
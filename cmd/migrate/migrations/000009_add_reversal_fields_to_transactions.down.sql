@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_parent_id;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS refunded_amount,
+    DROP COLUMN IF EXISTS parent_id;
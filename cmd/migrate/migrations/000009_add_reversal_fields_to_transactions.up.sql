@@ -0,0 +1,6 @@
+-- Telafi işlemleri (reversal/refund) orijinal işleme parent_id ile bağlanır
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES transactions(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS refunded_amount NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_parent_id ON transactions (parent_id);
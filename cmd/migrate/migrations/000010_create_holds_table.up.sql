@@ -0,0 +1,23 @@
+-- Aktif hold'ların toplamı; kullanılabilir bakiye = amount - held
+ALTER TABLE balances
+    ADD COLUMN IF NOT EXISTS held NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (held >= 0);
+
+CREATE TABLE IF NOT EXISTS holds (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    captured_amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'active',
+    reference TEXT NOT NULL DEFAULT '',
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    released_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_user_id ON holds (user_id);
+CREATE INDEX IF NOT EXISTS idx_holds_to_user_id ON holds (to_user_id);
+-- sweeper: yalnızca aktif hold'lar taranır
+CREATE INDEX IF NOT EXISTS idx_holds_active_expires_at ON holds (expires_at) WHERE status = 'active';
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS scheduled_transfer_runs;
+DROP TABLE IF EXISTS scheduled_transfers;
+
+DROP INDEX IF EXISTS uq_transactions_origin;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS origin_seq,
+    DROP COLUMN IF EXISTS origin_id,
+    DROP COLUMN IF EXISTS origin_type;
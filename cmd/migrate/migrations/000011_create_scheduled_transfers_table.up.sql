@@ -0,0 +1,40 @@
+-- İşlemi başlatan kaynak (zamanlanmış transfer vb.); aynı kaynak aynı seq ile ikinci işlem üretemez
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS origin_type TEXT,
+    ADD COLUMN IF NOT EXISTS origin_id BIGINT,
+    ADD COLUMN IF NOT EXISTS origin_seq INT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_transactions_origin ON transactions (origin_type, origin_id, origin_seq) WHERE origin_type IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    execute_at TIMESTAMPTZ NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_user_id ON scheduled_transfers (user_id);
+-- scheduler taraması: bekleyen ve sahiplenilmiş kayıtlar
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due ON scheduled_transfers (next_run_at) WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+    id BIGSERIAL PRIMARY KEY,
+    scheduled_transfer_id BIGINT NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    attempt INT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfer_runs_parent ON scheduled_transfer_runs (scheduled_transfer_id);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    frequency TEXT NOT NULL CHECK (frequency IN ('weekly', 'monthly', 'last_business_day')),
+    day_of_month INT NOT NULL DEFAULT 0,
+    start_at TIMESTAMPTZ NOT NULL,
+    end_at TIMESTAMPTZ,
+    max_occurrences INT CHECK (max_occurrences > 0),
+    occurrences INT NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMPTZ,
+    status TEXT NOT NULL DEFAULT 'active',
+    last_error TEXT NOT NULL DEFAULT '',
+    locked_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_standing_orders_user_id ON standing_orders (user_id);
+CREATE INDEX IF NOT EXISTS idx_standing_orders_due ON standing_orders (next_run_at) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS standing_order_runs (
+    id BIGSERIAL PRIMARY KEY,
+    standing_order_id BIGINT NOT NULL REFERENCES standing_orders(id) ON DELETE CASCADE,
+    seq INT NOT NULL,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    skipped INT NOT NULL DEFAULT 0,
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (standing_order_id, seq)
+);
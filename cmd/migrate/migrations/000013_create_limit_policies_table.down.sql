@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_from_user_created_at;
+DROP TABLE IF EXISTS limit_policies;
@@ -0,0 +1,26 @@
+-- Giden işlem limitleri: role doluysa rol varsayılanı, user_id doluysa kullanıcı override'ı
+CREATE TABLE IF NOT EXISTS limit_policies (
+    id BIGSERIAL PRIMARY KEY,
+    role TEXT,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    per_transaction_max NUMERIC(20,4) CHECK (per_transaction_max >= 0),
+    daily_max NUMERIC(20,4) CHECK (daily_max >= 0),
+    monthly_max NUMERIC(20,4) CHECK (monthly_max >= 0),
+    hourly_transfer_max INT CHECK (hourly_transfer_max >= 0),
+    updated_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((role IS NULL) <> (user_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_limit_policies_role ON limit_policies (role, currency) WHERE role IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_limit_policies_user ON limit_policies (user_id, currency) WHERE user_id IS NOT NULL;
+
+-- Limit pencereleri (günlük/aylık toplam, saatlik transfer sayısı) gönderen + zaman üzerinden taranır
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_created_at ON transactions (from_user_id, created_at);
+
+-- Varsayılan: standart kullanıcılar için USD limitleri (admin rolü limitsiz)
+INSERT INTO limit_policies (role, currency, per_transaction_max, daily_max, monthly_max, hourly_transfer_max)
+VALUES ('user', 'USD', 10000, 25000, 100000, 30)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_balances_overdrawn;
+DROP TABLE IF EXISTS overdraft_accruals;
+ALTER TABLE balances DROP COLUMN IF EXISTS overdraft_rate_bps;
+ALTER TABLE balances DROP COLUMN IF EXISTS credit_limit;
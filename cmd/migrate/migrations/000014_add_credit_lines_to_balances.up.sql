@@ -0,0 +1,18 @@
+-- Kredili hesap: cüzdan bazında overdraft limiti ve opsiyonel yıllık faiz oranı (bps, NULL = OVERDRAFT_RATE_BPS)
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS credit_limit NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+ALTER TABLE balances ADD COLUMN IF NOT EXISTS overdraft_rate_bps INT CHECK (overdraft_rate_bps >= 0);
+
+-- Günlük faiz tahakkukları; PK aynı gün için ikinci tahakkuku engeller
+CREATE TABLE IF NOT EXISTS overdraft_accruals (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    accrual_date DATE NOT NULL,
+    balance NUMERIC(20,4) NOT NULL,
+    rate_bps INT NOT NULL,
+    interest NUMERIC(20,4) NOT NULL,
+    transaction_id BIGINT REFERENCES transactions(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_balances_overdrawn ON balances (user_id, currency) WHERE amount < 0;
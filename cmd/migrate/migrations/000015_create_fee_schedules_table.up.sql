@@ -0,0 +1,26 @@
+-- Ücret tarifeleri: tx_type + (opsiyonel) rol + tutar bandı, geçerlilik aralığı ile
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    tx_type TEXT NOT NULL CHECK (tx_type IN ('debit', 'transfer')),
+    role TEXT,
+    currency CHAR(3) NOT NULL,
+    min_amount NUMERIC(20,4),
+    max_amount NUMERIC(20,4),
+    kind TEXT NOT NULL CHECK (kind IN ('flat', 'percent', 'tiered')),
+    flat_amount NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (flat_amount >= 0),
+    rate_bps INT NOT NULL DEFAULT 0 CHECK (rate_bps BETWEEN 0 AND 10000),
+    tiers TEXT,
+    min_fee NUMERIC(20,4) CHECK (min_fee >= 0),
+    max_fee NUMERIC(20,4) CHECK (max_fee >= 0),
+    priority INT NOT NULL DEFAULT 0,
+    effective_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    effective_to TIMESTAMPTZ,
+    updated_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fee_schedules_lookup ON fee_schedules (tx_type, currency, effective_from);
+
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ledger_entries_user_currency_created_at;
+DROP TABLE IF EXISTS balance_checkpoints;
@@ -0,0 +1,12 @@
+-- Günlük kapanış bakiyeleri: as_of öncesindeki (created_at < as_of) tüm ledger kayıtlarının toplamı
+CREATE TABLE IF NOT EXISTS balance_checkpoints (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    as_of TIMESTAMPTZ NOT NULL,
+    balance NUMERIC(20,4) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency, as_of)
+);
+
+-- Checkpoint sonrası delta toplamı cüzdan + zaman üzerinden taranır
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_currency_created_at ON ledger_entries (user_id, currency, created_at) WHERE user_id IS NOT NULL;
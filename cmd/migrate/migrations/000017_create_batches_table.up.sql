@@ -0,0 +1,28 @@
+-- Toplu işlemler: all_or_nothing (tek DB transaction'ı) ya da best_effort (kalem başına)
+CREATE TABLE IF NOT EXISTS batches (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mode TEXT NOT NULL CHECK (mode IN ('all_or_nothing', 'best_effort')),
+    status TEXT NOT NULL,
+    item_count INT NOT NULL,
+    succeeded_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_batches_user_id ON batches (user_id, id DESC);
+
+CREATE TABLE IF NOT EXISTS batch_items (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id BIGINT NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    seq INT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('credit', 'debit', 'transfer')),
+    to_user_id BIGINT,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    status TEXT NOT NULL,
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    error TEXT,
+    UNIQUE (batch_id, seq)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id BIGSERIAL PRIMARY KEY,
+    requester_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepting', 'accepted', 'declined', 'cancelled', 'expired')),
+    transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (requester_id <> payer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_payer_id ON payment_requests (payer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_requester_id ON payment_requests (requester_id, created_at);
+-- Süre dolumu taraması yalnızca bekleyen istekleri okur
+CREATE INDEX IF NOT EXISTS idx_payment_requests_expiry ON payment_requests (expires_at) WHERE status = 'pending';
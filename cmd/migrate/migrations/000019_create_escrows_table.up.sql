@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS escrows (
+    id BIGSERIAL PRIMARY KEY,
+    buyer_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(20,4) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'funded' CHECK (status IN ('funded', 'disputed', 'released', 'refunded')),
+    buyer_decision TEXT NOT NULL DEFAULT '' CHECK (buyer_decision IN ('', 'release', 'refund')),
+    seller_decision TEXT NOT NULL DEFAULT '' CHECK (seller_decision IN ('', 'release', 'refund')),
+    timeout_outcome TEXT NOT NULL CHECK (timeout_outcome IN ('release', 'refund')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    fund_transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    settle_transaction_id BIGINT REFERENCES transactions(id) ON DELETE SET NULL,
+    resolution TEXT NOT NULL DEFAULT '',
+    resolved_by BIGINT,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (buyer_id <> seller_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_escrows_buyer_id ON escrows (buyer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_escrows_seller_id ON escrows (seller_id, created_at);
+-- Timeout taraması yalnızca ihtilafsız açık escrow'ları okur
+CREATE INDEX IF NOT EXISTS idx_escrows_expiry ON escrows (expires_at) WHERE status = 'funded';
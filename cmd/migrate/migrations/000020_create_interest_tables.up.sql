@@ -0,0 +1,34 @@
+-- Mevduat faizi tarifeleri: para birimi + bakiye kademesi (min_balance) + geçerlilik aralığı
+CREATE TABLE IF NOT EXISTS interest_rates (
+    id BIGSERIAL PRIMARY KEY,
+    currency CHAR(3) NOT NULL,
+    min_balance NUMERIC(20,4) NOT NULL DEFAULT 0 CHECK (min_balance >= 0),
+    rate_bps INT NOT NULL CHECK (rate_bps >= 0),
+    day_count TEXT NOT NULL DEFAULT 'ACT/365' CHECK (day_count IN ('ACT/365', 'ACT/360', 'ACT/ACT', '30/360')),
+    effective_from TIMESTAMPTZ NOT NULL,
+    effective_to TIMESTAMPTZ,
+    updated_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_rates_currency ON interest_rates (currency, effective_from);
+
+-- Günlük tahakkuklar; PK aynı gün için ikinci tahakkuku engeller, posted_at aylık ödemede dolar
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    accrual_date DATE NOT NULL,
+    balance NUMERIC(20,4) NOT NULL,
+    rate_id BIGINT REFERENCES interest_rates(id) ON DELETE SET NULL,
+    rate_bps INT NOT NULL,
+    day_count TEXT NOT NULL,
+    interest NUMERIC(20,4) NOT NULL,
+    transaction_id BIGINT REFERENCES transactions(id),
+    posted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, currency, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_unposted ON interest_accruals (accrual_date) WHERE posted_at IS NULL;
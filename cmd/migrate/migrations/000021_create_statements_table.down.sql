@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS statements;
+DROP FUNCTION IF EXISTS statements_immutable();
@@ -0,0 +1,27 @@
+-- Dönem ekstreleri: içerik üretildiği anki JSON'dur, content_hash onun SHA-256'sıdır
+CREATE TABLE IF NOT EXISTS statements (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    opening_balance NUMERIC(20,4) NOT NULL,
+    closing_balance NUMERIC(20,4) NOT NULL,
+    line_count INT NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, currency, period_start),
+    CHECK (period_end > period_start)
+);
+
+-- Ekstreler değiştirilemez: UPDATE reddedilir (kullanıcı silinince CASCADE ile silinebilir)
+CREATE OR REPLACE FUNCTION statements_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'statements are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_statements_immutable ON statements;
+CREATE TRIGGER trg_statements_immutable BEFORE UPDATE ON statements
+    FOR EACH ROW EXECUTE FUNCTION statements_immutable();
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_transactions_memo_fts;
+DROP INDEX IF EXISTS idx_transactions_tags;
+DROP INDEX IF EXISTS idx_transactions_category;
+DROP INDEX IF EXISTS idx_transactions_from_user_external_ref;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS external_ref,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS category,
+    DROP COLUMN IF EXISTS memo;
@@ -0,0 +1,14 @@
+-- Kullanıcı metadata'sı: memo, kategori, etiketler ve istemci referansı
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS memo TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS category VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tags JSONB,
+    ADD COLUMN IF NOT EXISTS external_ref VARCHAR(128);
+
+-- external_ref işlemi başlatan kullanıcı için benzersizdir
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_from_user_external_ref ON transactions (from_user_id, external_ref) WHERE external_ref IS NOT NULL;
+
+-- Geçmiş aramaları: kategori, etiket içerme (@>) ve memo üzerinde tam metin
+CREATE INDEX IF NOT EXISTS idx_transactions_category ON transactions (category) WHERE category <> '';
+CREATE INDEX IF NOT EXISTS idx_transactions_tags ON transactions USING GIN (tags);
+CREATE INDEX IF NOT EXISTS idx_transactions_memo_fts ON transactions USING GIN (to_tsvector('simple', memo));
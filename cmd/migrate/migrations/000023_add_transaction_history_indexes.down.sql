@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_created_at ON transactions (from_user_id, created_at);
+
+DROP INDEX IF EXISTS idx_transactions_to_user_created_at_id;
+DROP INDEX IF EXISTS idx_transactions_from_user_created_at_id;
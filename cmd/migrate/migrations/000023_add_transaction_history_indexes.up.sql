@@ -0,0 +1,6 @@
+-- İşlem geçmişi keyset sayfalaması: gönderen ve alıcı dalları kendi indeksinden (created_at, id) sırasıyla okunur
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_created_at_id ON transactions (from_user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_transactions_to_user_created_at_id ON transactions (to_user_id, created_at, id);
+
+-- (from_user_id, created_at) yeni indeksin önekidir; limit pencereleri de onu kullanır
+DROP INDEX IF EXISTS idx_transactions_from_user_created_at;
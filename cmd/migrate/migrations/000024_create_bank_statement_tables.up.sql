@@ -0,0 +1,41 @@
+-- İçe aktarılan banka ekstreleri; aynı dosya (file_hash) ikinci kez yüklenemez
+CREATE TABLE IF NOT EXISTS bank_statements (
+    id BIGSERIAL PRIMARY KEY,
+    source TEXT NOT NULL CHECK (source IN ('camt053', 'csv')),
+    filename TEXT NOT NULL DEFAULT '',
+    file_hash CHAR(64) NOT NULL UNIQUE,
+    account_id TEXT NOT NULL DEFAULT '',
+    line_count INT NOT NULL DEFAULT 0,
+    uploaded_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Ekstre kalemleri; amount işaretlidir (yatırım pozitif)
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+    id BIGSERIAL PRIMARY KEY,
+    statement_id BIGINT NOT NULL REFERENCES bank_statements(id) ON DELETE CASCADE,
+    line_no INT NOT NULL,
+    booking_date DATE NOT NULL,
+    amount NUMERIC(20,4) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    counterparty_name TEXT NOT NULL DEFAULT '',
+    counterparty_account TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('matched', 'unmatched', 'exception', 'crediting', 'credited')),
+    reason TEXT NOT NULL DEFAULT '',
+    matched_by TEXT NOT NULL DEFAULT '',
+    suggested_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    transaction_id BIGINT REFERENCES transactions(id),
+    resolved_by BIGINT REFERENCES users(id),
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_statement_lines_statement_line_no ON bank_statement_lines (statement_id, line_no);
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_status ON bank_statement_lines (statement_id, status);
+-- Bir credit işlemi en fazla bir banka kalemine bağlanır
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_statement_lines_transaction_id ON bank_statement_lines (transaction_id) WHERE transaction_id IS NOT NULL;
+-- Ekstreler arası mükerrer kalem araması
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_dedup ON bank_statement_lines (booking_date, amount, currency);
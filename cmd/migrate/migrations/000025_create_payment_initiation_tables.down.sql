@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_initiation_txs;
+DROP TABLE IF EXISTS payment_initiations;
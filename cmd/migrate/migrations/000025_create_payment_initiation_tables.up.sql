@@ -0,0 +1,43 @@
+-- pain.001 toplu ödeme talimatları; aynı gönderenin aynı MsgId'li ikinci dosyası reddedilir
+CREATE TABLE IF NOT EXISTS payment_initiations (
+    id BIGSERIAL PRIMARY KEY,
+    submitted_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    msg_id VARCHAR(35) NOT NULL,
+    filename TEXT NOT NULL DEFAULT '',
+    initiated_by TEXT NOT NULL DEFAULT '',
+    tx_count INT NOT NULL CHECK (tx_count > 0),
+    control_sum NUMERIC(20,4) NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('processing', 'completed')),
+    group_status CHAR(4) NOT NULL CHECK (group_status IN ('ACTC', 'ACSC', 'PART', 'RJCT')),
+    settled_count INT NOT NULL DEFAULT 0,
+    rejected_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    UNIQUE (submitted_by, msg_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_initiations_processing ON payment_initiations (id) WHERE status = 'processing';
+
+-- Talimat kalemleri (CdtTrfTxInf); PDNG kalemler işlemcide transfere dönüşür
+CREATE TABLE IF NOT EXISTS payment_initiation_txs (
+    id BIGSERIAL PRIMARY KEY,
+    initiation_id BIGINT NOT NULL REFERENCES payment_initiations(id) ON DELETE CASCADE,
+    seq INT NOT NULL,
+    pmt_inf_id VARCHAR(35) NOT NULL,
+    instr_id TEXT NOT NULL DEFAULT '',
+    end_to_end_id TEXT NOT NULL,
+    from_user_id BIGINT NOT NULL REFERENCES users(id),
+    to_user_id BIGINT REFERENCES users(id),
+    creditor_name TEXT NOT NULL DEFAULT '',
+    creditor_account TEXT NOT NULL DEFAULT '',
+    amount NUMERIC(20,4) NOT NULL CHECK (amount >= 0),
+    currency CHAR(3) NOT NULL,
+    remittance TEXT NOT NULL DEFAULT '',
+    status CHAR(4) NOT NULL CHECK (status IN ('PDNG', 'ACSC', 'RJCT')),
+    reason_code VARCHAR(4) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    transaction_id BIGINT REFERENCES transactions(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (initiation_id, seq)
+);
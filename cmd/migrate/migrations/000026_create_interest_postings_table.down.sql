@@ -0,0 +1 @@
+DROP TABLE IF EXISTS interest_postings;
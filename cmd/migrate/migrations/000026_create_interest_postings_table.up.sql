@@ -0,0 +1,22 @@
+-- Aylık faiz ödemeleri; (user, currency, month) benzersizliği bir ayın ikinci kez ödenmesini engeller.
+-- Ödenmiş aya geç yazılan tahakkuklar cüzdanın bir sonraki ödemesine eklenir.
+CREATE TABLE IF NOT EXISTS interest_postings (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL,
+    month DATE NOT NULL,
+    amount NUMERIC(20,4) NOT NULL DEFAULT 0,
+    transaction_id BIGINT REFERENCES transactions(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_interest_postings_wallet_month ON interest_postings (user_id, currency, month);
+
+-- daha önce ödenmiş aylar
+INSERT INTO interest_postings (user_id, currency, month, amount, transaction_id)
+SELECT a.user_id, a.currency, date_trunc('month', a.accrual_date)::date, COALESCE(MAX(t.amount), 0), MAX(a.transaction_id)
+FROM interest_accruals a
+LEFT JOIN transactions t ON t.id = a.transaction_id
+WHERE a.posted_at IS NOT NULL
+GROUP BY 1, 2, 3
+ON CONFLICT (user_id, currency, month) DO NOTHING;
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// reconcile: tüm cüzdanların bakiyesini ledger geçmişinden yeniden hesaplar, işlemleri ledger bacaklarıyla karşılaştırır
+// ve uyuşmazlıkları raporlar (işlem uyuşmazlıkları yalnızca json raporunda yer alır).
+//
+//	go run ./cmd/reconcile -format csv -out drift.csv
+//	go run ./cmd/reconcile -confirm                      # uyuşmazlıklar için düzeltme kaydı açar
+//	go run ./cmd/reconcile -metrics-file /var/lib/node_exporter/reconcile.prom
+//
+// Düzeltilmemiş cüzdan uyuşmazlığı ya da işlem uyuşmazlığı kalırsa çıkış kodu 2'dir (cron/alarm için).
+func main() {
+	format := flag.String("format", "json", "report format: json or csv")
+	out := flag.String("out", "", "write the report to this file instead of stdout")
+	confirm := flag.Bool("confirm", false, "open correction adjustments for every mismatch")
+	metricsFile := flag.String("metrics-file", "", "write drift gauges in Prometheus text format (node_exporter textfile collector)")
+	flag.Parse()
+
+	if *format != "json" && *format != "csv" {
+		log.Fatal("Invalid format. Use 'json' or 'csv'.")
+	}
+
+	_ = godotenv.Load()
+	database.ConnectDB(os.Getenv("DB_DSN"))
+
+	report, err := services.Reconcile(*confirm, 0)
+	if err != nil {
+		log.Fatalf("reconcile failed: %v", err)
+	}
+
+	if err := writeReport(*out, *format, report); err != nil {
+		log.Fatal(err)
+	}
+
+	if *metricsFile != "" {
+		if err := prometheus.WriteToTextfile(*metricsFile, prometheus.DefaultGatherer); err != nil {
+			log.Fatalf("writing metrics failed: %v", err)
+		}
+	}
+
+	pending := 0
+	for _, m := range report.Mismatches {
+		if !m.Corrected {
+			pending++
+		}
+	}
+	log.Printf("Reconciled %d wallets: %d mismatches, %d uncorrected (confirm=%v); %d transactions: %d mismatches.",
+		report.Wallets, len(report.Mismatches), pending, *confirm, report.Transactions, len(report.TxMismatches))
+	if pending > 0 || len(report.TxMismatches) > 0 {
+		os.Exit(2)
+	}
+}
+
+// writeReport: raporu path'e (boşsa stdout) json ya da csv olarak yazar
+func writeReport(path, format string, report *models.ReconcileReport) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if format == "csv" {
+		return report.WriteCSV(w)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -34,3 +36,218 @@ func mustParseDuration(s string) time.Duration {
 	}
 	return d
 }
+
+// DefaultCurrency: istekte para birimi verilmezse kullanılan ISO 4217 kodu
+func DefaultCurrency() string {
+	return strings.ToUpper(getenv("DEFAULT_CURRENCY", "USD"))
+}
+
+type fxCfg struct {
+	SpreadBps int // kur kaydında spread yoksa uygulanan varsayılan (baz puan)
+}
+
+// FX konfigürasyonu
+func GetFX() fxCfg {
+	return fxCfg{SpreadBps: getenvInt("FX_SPREAD_BPS", 50)}
+}
+
+func getenvInt(k string, def int) int {
+	if v := os.Getenv(k); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+type idempotencyCfg struct {
+	TTL           time.Duration
+	PurgeInterval time.Duration
+}
+
+// Idempotency-Key konfigürasyonu
+func GetIdempotency() idempotencyCfg {
+	return idempotencyCfg{
+		TTL:           mustParseDuration(getenv("IDEMPOTENCY_TTL", "24h")),
+		PurgeInterval: mustParseDuration(getenv("IDEMPOTENCY_PURGE_INTERVAL", "1h")),
+	}
+}
+
+type holdCfg struct {
+	TTL           time.Duration // istekte süre verilmezse hold ömrü
+	MaxTTL        time.Duration
+	SweepInterval time.Duration // süresi dolan hold'ları serbest bırakan job aralığı
+}
+
+// Hold (authorization) konfigürasyonu
+func GetHolds() holdCfg {
+	return holdCfg{
+		TTL:           mustParseDuration(getenv("HOLD_TTL", "168h")),
+		MaxTTL:        mustParseDuration(getenv("HOLD_MAX_TTL", "720h")),
+		SweepInterval: mustParseDuration(getenv("HOLD_SWEEP_INTERVAL", "1m")),
+	}
+}
+
+type schedulerCfg struct {
+	Interval     time.Duration // vadesi gelen transferlerin tarama aralığı
+	Lease        time.Duration // sahiplenilen kaydın başka replikaya kapalı kalma süresi
+	BatchSize    int
+	RetryMax     int           // yetersiz bakiyede toplam deneme sayısı
+	RetryBackoff time.Duration // denemeler arası bekleme (deneme sayısıyla çarpılır)
+}
+
+// Scheduler (zamanlanmış transfer) konfigürasyonu
+func GetScheduler() schedulerCfg {
+	return schedulerCfg{
+		Interval:     mustParseDuration(getenv("SCHEDULER_INTERVAL", "30s")),
+		Lease:        mustParseDuration(getenv("SCHEDULER_LEASE", "5m")),
+		BatchSize:    getenvInt("SCHEDULER_BATCH_SIZE", 100),
+		RetryMax:     getenvInt("SCHEDULED_RETRY_MAX", 3),
+		RetryBackoff: mustParseDuration(getenv("SCHEDULED_RETRY_BACKOFF", "1h")),
+	}
+}
+
+type overdraftCfg struct {
+	RateBps     int           // cüzdanda oran yoksa uygulanan yıllık overdraft faizi (baz puan)
+	Interval    time.Duration // faiz job'unun çalışma aralığı; gün başına tek tahakkuk yazılır
+	Lag         time.Duration // gün sonundan sonra tahakkuk etmeden önce beklenen süre (geç commit'ler için)
+	CatchUpDays int           // kesinti sonrası geriye dönük tahakkuk edilecek en fazla gün
+}
+
+// Overdraft konfigürasyonu
+func GetOverdraft() overdraftCfg {
+	return overdraftCfg{
+		RateBps:     getenvInt("OVERDRAFT_RATE_BPS", 1500),
+		Interval:    mustParseDuration(getenv("OVERDRAFT_INTEREST_INTERVAL", "1h")),
+		Lag:         mustParseDuration(getenv("OVERDRAFT_LAG", "5m")),
+		CatchUpDays: getenvInt("OVERDRAFT_CATCHUP_DAYS", 7),
+	}
+}
+
+type checkpointCfg struct {
+	Interval time.Duration // checkpoint job'unun çalışma aralığı
+	Lag      time.Duration // gün sonundan sonra checkpoint yazmadan önce beklenen süre (geç commit'ler için)
+}
+
+// Bakiye checkpoint konfigürasyonu
+func GetCheckpoints() checkpointCfg {
+	return checkpointCfg{
+		Interval: mustParseDuration(getenv("CHECKPOINT_INTERVAL", "1h")),
+		Lag:      mustParseDuration(getenv("CHECKPOINT_LAG", "5m")),
+	}
+}
+
+// Ledger mutabakat job'u aralığı (yalnızca rapor + drift gauge'ları; düzeltme yapmaz)
+func GetReconcileInterval() time.Duration {
+	return mustParseDuration(getenv("RECONCILE_INTERVAL", "1h"))
+}
+
+// Toplu işlem isteğindeki azami kalem sayısı
+func GetBatchMaxItems() int {
+	return getenvInt("BATCH_MAX_ITEMS", 500)
+}
+
+type paymentRequestCfg struct {
+	TTL           time.Duration // istekte süre verilmezse ödeme isteğinin ömrü
+	MaxTTL        time.Duration
+	SweepInterval time.Duration // süresi dolan istekleri expired'a çeken job aralığı
+}
+
+// Ödeme isteği konfigürasyonu
+func GetPaymentRequests() paymentRequestCfg {
+	return paymentRequestCfg{
+		TTL:           mustParseDuration(getenv("PAYMENT_REQUEST_TTL", "168h")),
+		MaxTTL:        mustParseDuration(getenv("PAYMENT_REQUEST_MAX_TTL", "720h")),
+		SweepInterval: mustParseDuration(getenv("PAYMENT_REQUEST_SWEEP_INTERVAL", "5m")),
+	}
+}
+
+type escrowCfg struct {
+	TTL            time.Duration // istekte süre verilmezse escrow'un kapanma süresi
+	MaxTTL         time.Duration
+	TimeoutOutcome string        // süre dolunca uygulanan sonuç (release | refund); istekte verilmezse
+	SweepInterval  time.Duration // süresi dolan escrow'ları kapatan job aralığı
+}
+
+// Escrow konfigürasyonu
+func GetEscrow() escrowCfg {
+	return escrowCfg{
+		TTL:            mustParseDuration(getenv("ESCROW_TTL", "336h")),
+		MaxTTL:         mustParseDuration(getenv("ESCROW_MAX_TTL", "2160h")),
+		TimeoutOutcome: getenv("ESCROW_TIMEOUT_OUTCOME", "refund"),
+		SweepInterval:  mustParseDuration(getenv("ESCROW_SWEEP_INTERVAL", "5m")),
+	}
+}
+
+type interestCfg struct {
+	Interval     time.Duration // tahakkuk + aylık ödeme job'unun çalışma aralığı
+	Lag          time.Duration // gün sonundan sonra tahakkuk etmeden önce beklenen süre (geç commit'ler için)
+	CatchUpDays  int           // kesinti sonrası geriye dönük tahakkuk edilecek en fazla gün
+	MaxRangeDays int           // önizlemede izin verilen en uzun aralık
+}
+
+// Mevduat faizi konfigürasyonu
+func GetInterest() interestCfg {
+	return interestCfg{
+		Interval:     mustParseDuration(getenv("INTEREST_INTERVAL", "1h")),
+		Lag:          mustParseDuration(getenv("INTEREST_LAG", "5m")),
+		CatchUpDays:  getenvInt("INTEREST_CATCHUP_DAYS", 7),
+		MaxRangeDays: getenvInt("INTEREST_PREVIEW_MAX_DAYS", 366),
+	}
+}
+
+type statementCfg struct {
+	Interval time.Duration // ay sonu ekstre job'unun çalışma aralığı
+	Lag      time.Duration // ay bittikten sonra ekstre üretmeden önce beklenen süre (geç commit'ler için)
+}
+
+// Ekstre konfigürasyonu
+func GetStatements() statementCfg {
+	return statementCfg{
+		Interval: mustParseDuration(getenv("STATEMENT_INTERVAL", "1h")),
+		Lag:      mustParseDuration(getenv("STATEMENT_LAG", "1h")),
+	}
+}
+
+type exportCfg struct {
+	BankID      string        // OFX BANKID ve camt.053 hesap sağlayıcı kimliği
+	BankName    string        // camt.053 GrpHdr/MsgRcpt yerine dosyalarda görünen kurum adı
+	WriteWindow time.Duration // akış sırasında yazma süresinin her seferinde uzatıldığı pencere (sunucu WriteTimeout'u yerine)
+	FlushEvery  time.Duration // yazılanların istemciye gönderilme aralığı
+}
+
+// Hesap hareketi dışa aktarma konfigürasyonu
+func GetExport() exportCfg {
+	return exportCfg{
+		BankID:      getenv("EXPORT_BANK_ID", "INSIDER"),
+		BankName:    getenv("EXPORT_BANK_NAME", "Insider Wallet"),
+		WriteWindow: mustParseDuration(getenv("EXPORT_WRITE_WINDOW", "30s")),
+		FlushEvery:  mustParseDuration(getenv("EXPORT_FLUSH_EVERY", "1s")),
+	}
+}
+
+type bankImportCfg struct {
+	MatchWindowDays int   // banka kalemi ile credit işlemi arasında kabul edilen en büyük gün farkı
+	MaxFileBytes    int64 // yüklenebilecek en büyük ekstre dosyası
+}
+
+// Banka ekstresi içe aktarma konfigürasyonu
+func GetBankImport() bankImportCfg {
+	return bankImportCfg{
+		MatchWindowDays: getenvInt("BANK_MATCH_WINDOW_DAYS", 3),
+		MaxFileBytes:    int64(getenvInt("BANK_IMPORT_MAX_BYTES", 10<<20)),
+	}
+}
+
+type paymentInitiationCfg struct {
+	MaxTxs       int   // bir pain.001 dosyasındaki en fazla kalem
+	MaxFileBytes int64 // yüklenebilecek en büyük pain.001 dosyası
+}
+
+// pain.001 toplu ödeme konfigürasyonu
+func GetPaymentInitiation() paymentInitiationCfg {
+	return paymentInitiationCfg{
+		MaxTxs:       getenvInt("PAIN001_MAX_TXS", 1000),
+		MaxFileBytes: int64(getenvInt("PAIN001_MAX_BYTES", 10<<20)),
+	}
+}
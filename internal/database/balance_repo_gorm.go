@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type gormBalanceRepository struct {
@@ -16,39 +17,57 @@ func NewGormBalanceRepository(db *gorm.DB) BalanceRepository {
 	return &gormBalanceRepository{db: db, mu: &sync.RWMutex{}}
 }
 
-func (r *gormBalanceRepository) GetBalanceByUserID(userID int) (*models.Balance, error) {
+func (r *gormBalanceRepository) GetBalance(userID int, currency string) (*models.Balance, error) {
 	var balance models.Balance
-	if err := r.db.Table("balances").Where("user_id = ?", userID).First(&balance).Error; err != nil {
+	if err := r.db.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).First(&balance).Error; err != nil {
 		return nil, err
 	}
 	return &balance, nil
 }
 
-func (r *gormBalanceRepository) UpdateBalance(userID int, amount float64) error {
+func (r *gormBalanceRepository) GetBalancesByUserID(userID int) ([]models.Balance, error) {
+	var balances []models.Balance
+	err := r.db.Table("balances").Where("user_id = ?", userID).Order("currency").Find(&balances).Error
+	return balances, err
+}
+
+// UpdateBalance: cüzdanı hedef değere çeker; fark ledger'a "adjustment" olarak yazılır
+func (r *gormBalanceRepository) UpdateBalance(userID int, currency string, amount models.Money) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.db.Table("balances").Where("user_id = ?", userID).Updates(map[string]interface{}{
-		"amount":          amount,
-		"last_updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
-	}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var balance models.Balance
+		if err := tx.Table("balances").Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ? AND currency = ?", userID, currency).First(&balance).Error; err != nil {
+			return err
+		}
+		return postAdjustment(tx, models.TxTypeAdjustment, userID, currency, amount-balance.Amount)
+	})
 }
 
+// CreateBalance: cüzdan satırını sıfırla açar; başlangıç tutarı varsa "opening_balance" olarak ledger'a yazılır
 func (r *gormBalanceRepository) CreateBalance(balance *models.Balance) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	return r.db.Table("balances").Create(balance).Error
+	initial := balance.Amount
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		balance.Amount = 0
+		if err := tx.Table("balances").Create(balance).Error; err != nil {
+			balance.Amount = initial
+			return err
+		}
+		balance.Amount = initial
+		return postAdjustment(tx, models.TxTypeOpeningBalance, balance.UserID, balance.Currency, initial)
+	})
 }
 
-func (r *gormBalanceRepository) AdjustBalance(userID int, delta float64) error {
+func (r *gormBalanceRepository) AdjustBalance(userID int, currency string, delta models.Money) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	var balance models.Balance
-	if err := r.db.Table("balances").Where("user_id = ?", userID).First(&balance).Error; err != nil {
-		return err
-	}
-	newAmount := balance.Amount + delta
-	return r.db.Table("balances").Where("user_id = ?", userID).Updates(map[string]interface{}{
-		"amount":          newAmount,
-		"last_updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
-	}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var balance models.Balance
+		if err := tx.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).First(&balance).Error; err != nil {
+			return err
+		}
+		return postAdjustment(tx, models.TxTypeAdjustment, userID, currency, delta)
+	})
 }
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateBankStatement: aynı dosya daha önce içe aktarılmış
+var ErrDuplicateBankStatement = errors.New("bank statement already imported")
+
+type gormBankStatementRepository struct{ db *gorm.DB }
+
+func NewGormBankStatementRepository(db *gorm.DB) BankStatementRepository {
+	return &gormBankStatementRepository{db: db}
+}
+
+// Create: ekstreyi ve kalemlerini tek DB transaction'ında yazar
+func (r *gormBankStatementRepository) Create(s *models.BankStatement, lines []models.BankStatementLine) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var n int64
+		if err := tx.Table("bank_statements").Where("file_hash = ?", s.FileHash).Count(&n).Error; err != nil {
+			return err
+		}
+		// eşzamanlı yüklemede benzersiz indeks ikinci kaydı ayrıca reddeder
+		if n > 0 {
+			return ErrDuplicateBankStatement
+		}
+		if err := tx.Table("bank_statements").Create(s).Error; err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		for i := range lines {
+			lines[i].StatementID = s.ID
+		}
+		return tx.Table("bank_statement_lines").CreateInBatches(lines, 500).Error
+	})
+}
+
+func (r *gormBankStatementRepository) Get(id int) (*models.BankStatement, error) {
+	var s models.BankStatement
+	if err := r.db.Table("bank_statements").First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List: en yeni ekstreler önce
+func (r *gormBankStatementRepository) List(limit int) ([]models.BankStatement, error) {
+	var items []models.BankStatement
+	err := r.db.Table("bank_statements").Order("id DESC").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// Lines: ekstrenin kalemleri; status boşsa hepsi
+func (r *gormBankStatementRepository) Lines(statementID int, status string) ([]models.BankStatementLine, error) {
+	var lines []models.BankStatementLine
+	q := r.db.Table("bank_statement_lines").Where("statement_id = ?", statementID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("line_no").Find(&lines).Error
+	return lines, err
+}
+
+func (r *gormBankStatementRepository) GetLine(id int) (*models.BankStatementLine, error) {
+	var l models.BankStatementLine
+	if err := r.db.Table("bank_statement_lines").First(&l, id).Error; err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// TransitionLine: kalemi from durumlarından birindeyse to durumuna taşır ve verilen alanları yazar
+func (r *gormBankStatementRepository) TransitionLine(id int, from []string, to string, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to, "updated_at": time.Now()}
+	for k, v := range fields {
+		updates[k] = v
+	}
+	res := r.db.Table("bank_statement_lines").Where("id = ? AND status IN ?", id, from).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("invalid bank statement line state")
+	}
+	return nil
+}
+
+// CreditCandidates: tutarı ve para birimi tutan, geri alınmamış, [from, to) aralığında oluşmuş ve henüz bir banka kalemine bağlanmamış credit işlemleri
+func (r *gormBankStatementRepository) CreditCandidates(currency string, amount models.Money, from, to time.Time) ([]models.Transaction, error) {
+	var txs []models.Transaction
+	err := r.db.Table("transactions t").
+		Where("t.type = ? AND t.status <> ? AND t.currency = ? AND t.amount = ? AND t.created_at >= ? AND t.created_at < ?",
+			"credit", models.TxStatusReversed, currency, amount, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM bank_statement_lines l WHERE l.transaction_id = t.id)").
+		Order("t.created_at, t.id").
+		Find(&txs).Error
+	return txs, err
+}
+
+// DuplicateLine: başka bir ekstrede aynı tarih, tutar, referans ve karşı hesapla yüklenmiş kalem (yoksa nil)
+func (r *gormBankStatementRepository) DuplicateLine(l models.BankStatementLine) (*models.BankStatementLine, error) {
+	var dup models.BankStatementLine
+	err := r.db.Table("bank_statement_lines").
+		Where("booking_date = ? AND amount = ? AND currency = ? AND reference = ? AND counterparty_account = ? AND description = ?",
+			l.BookingDate, l.Amount, l.Currency, l.Reference, l.CounterpartyAccount, l.Description).
+		Order("id").First(&dup).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dup, nil
+}
+
+// Totals: ekstrenin durum ve para birimi başına kalem sayıları ve tutar toplamları
+func (r *gormBankStatementRepository) Totals(statementID int) ([]models.BankReportTotal, error) {
+	var totals []models.BankReportTotal
+	err := r.db.Table("bank_statement_lines").
+		Select("status, currency, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where("statement_id = ?", statementID).
+		Group("status, currency").
+		Order("status, currency").
+		Scan(&totals).Error
+	return totals, err
+}
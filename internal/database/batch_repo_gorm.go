@@ -0,0 +1,164 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormBatchRepository struct{ db *gorm.DB }
+
+func NewGormBatchRepository(db *gorm.DB) BatchRepository {
+	return &gormBatchRepository{db: db}
+}
+
+func (r *gormBatchRepository) CreateBatch(b *models.Batch) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("batches").Create(b).Error; err != nil {
+			return err
+		}
+		for i := range b.Items {
+			b.Items[i].BatchID = b.ID
+			b.Items[i].Status = models.BatchItemPending
+		}
+		if len(b.Items) == 0 {
+			return nil
+		}
+		return tx.Table("batch_items").Create(&b.Items).Error
+	})
+}
+
+// RunAllOrNothing: kalemler sırayla aynı DB transaction'ında uygulanır (limit pencereleri önceki kalemleri görür).
+// Bir kalem başarısız olursa para hareketlerinin tamamı geri alınır; kalem sonuçları ayrıca kaydedilir.
+func (r *gormBatchRepository) RunAllOrNothing(b *models.Batch, opts []models.TxOptions) error {
+	failed := -1
+	var failure error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range b.Items {
+			rec, err := applyBatchItem(tx, b.UserID, &b.Items[i], opts[i])
+			if err != nil {
+				failed, failure = i, err
+				return err
+			}
+			id := rec.ID
+			b.Items[i].Status, b.Items[i].TransactionID = models.BatchItemSucceeded, &id
+		}
+		for i := range b.Items {
+			if err := saveBatchItem(tx, &b.Items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+	for i := range b.Items {
+		b.Items[i].TransactionID = nil
+		b.Items[i].Status, b.Items[i].Error = models.BatchItemRolledBack, ""
+		if i == failed {
+			b.Items[i].Status, b.Items[i].Error = models.BatchItemFailed, failure.Error()
+		}
+	}
+	if failed < 0 {
+		// kalem dışı (ör. bağlantı) hatası: tüm kalemler başarısız sayılır
+		for i := range b.Items {
+			b.Items[i].Status, b.Items[i].Error = models.BatchItemFailed, err.Error()
+		}
+	}
+	if err := r.SaveItems(b); err != nil {
+		return err
+	}
+	if failure != nil {
+		return failure
+	}
+	return err
+}
+
+func (r *gormBatchRepository) RunItem(b *models.Batch, i int, opts models.TxOptions) error {
+	it := &b.Items[i]
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		rec, err := applyBatchItem(tx, b.UserID, it, opts)
+		if err != nil {
+			return err
+		}
+		id := rec.ID
+		it.Status, it.TransactionID, it.Error = models.BatchItemSucceeded, &id, ""
+		return saveBatchItem(tx, it)
+	})
+	if err == nil {
+		return nil
+	}
+	it.Status, it.TransactionID, it.Error = models.BatchItemFailed, nil, err.Error()
+	if saveErr := saveBatchItem(r.db, it); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+func (r *gormBatchRepository) SaveItems(b *models.Batch) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range b.Items {
+			if err := saveBatchItem(tx, &b.Items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *gormBatchRepository) FinishBatch(b *models.Batch) error {
+	b.Tally()
+	now := time.Now()
+	b.CompletedAt = &now
+	return r.db.Table("batches").Where("id = ?", b.ID).Updates(map[string]interface{}{
+		"status":          b.Status,
+		"succeeded_count": b.SucceededCount,
+		"failed_count":    b.FailedCount,
+		"completed_at":    now,
+	}).Error
+}
+
+func (r *gormBatchRepository) GetBatch(id int) (*models.Batch, error) {
+	var b models.Batch
+	if err := r.db.Table("batches").First(&b, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Table("batch_items").Where("batch_id = ?", id).Order("seq").Find(&b.Items).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListBatches: kalemler olmadan, en yeniden eskiye
+func (r *gormBatchRepository) ListBatches(userID int, limit int) ([]models.Batch, error) {
+	var items []models.Batch
+	err := r.db.Table("batches").Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// applyBatchItem: kalemi tipine göre verilen DB transaction'ı içinde uygular
+func applyBatchItem(tx *gorm.DB, userID int, it *models.BatchItem, opts models.TxOptions) (*models.Transaction, error) {
+	switch it.Type {
+	case "credit":
+		return creditTx(tx, userID, it.Amount, it.Currency, opts)
+	case "debit":
+		return debitTx(tx, userID, it.Amount, it.Currency, opts)
+	case "transfer":
+		if it.ToUserID == nil {
+			return nil, errors.New("invalid recipient")
+		}
+		return transferTx(tx, userID, *it.ToUserID, it.Amount, it.Currency, opts)
+	}
+	return nil, errors.New("invalid item type")
+}
+
+func saveBatchItem(tx *gorm.DB, it *models.BatchItem) error {
+	return tx.Table("batch_items").Where("id = ?", it.ID).Updates(map[string]interface{}{
+		"status":         it.Status,
+		"transaction_id": it.TransactionID,
+		"error":          it.Error,
+	}).Error
+}
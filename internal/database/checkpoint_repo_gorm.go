@@ -0,0 +1,71 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormCheckpointRepository struct{ db *gorm.DB }
+
+func NewGormCheckpointRepository(db *gorm.DB) CheckpointRepository {
+	return &gormCheckpointRepository{db: db}
+}
+
+// WriteCheckpoints: her cüzdan için önceki checkpoint'e yalnızca aradaki ledger kayıtlarını ekleyerek
+// asOf kapanışını tek sorguda yazar. (user, currency, as_of) birincil anahtar olduğundan
+// tekrar çalıştırmalar ve paralel replikalar aynı checkpoint'i ikinci kez yazmaz.
+func (r *gormCheckpointRepository) WriteCheckpoints(asOf time.Time) (int64, error) {
+	res := r.db.Exec(`INSERT INTO balance_checkpoints (user_id, currency, as_of, balance, created_at)
+		SELECT b.user_id, b.currency, @as_of,
+			COALESCE(cp.balance, 0) + COALESCE((
+				SELECT SUM(e.amount) FROM ledger_entries e
+				WHERE e.user_id = b.user_id AND e.currency = b.currency
+					AND e.created_at >= COALESCE(cp.as_of, '-infinity') AND e.created_at < @as_of
+			), 0),
+			NOW()
+		FROM balances b
+		LEFT JOIN LATERAL (
+			SELECT c.as_of, c.balance FROM balance_checkpoints c
+			WHERE c.user_id = b.user_id AND c.currency = b.currency AND c.as_of < @as_of
+			ORDER BY c.as_of DESC LIMIT 1
+		) cp ON TRUE
+		ON CONFLICT DO NOTHING`, map[string]interface{}{"as_of": asOf})
+	return res.RowsAffected, res.Error
+}
+
+// LatestCheckpoint: before anından önceki (ya da eşit) en yeni checkpoint; yoksa nil
+func (r *gormCheckpointRepository) LatestCheckpoint(userID int, currency string, before time.Time) (*models.BalanceCheckpoint, error) {
+	var cp models.BalanceCheckpoint
+	err := r.db.Table("balance_checkpoints").
+		Where("user_id = ? AND currency = ? AND as_of <= ?", userID, currency, before).
+		Order("as_of DESC").First(&cp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (r *gormCheckpointRepository) BalanceAt(userID int, currency string, at time.Time) (models.Money, error) {
+	cp, err := r.LatestCheckpoint(userID, currency, at)
+	if err != nil {
+		return 0, err
+	}
+	var base models.Money
+	q := r.db.Table("ledger_entries").Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND currency = ? AND created_at <= ?", userID, currency, at)
+	if cp != nil {
+		base = cp.Balance
+		q = q.Where("created_at >= ?", cp.AsOf)
+	}
+	var delta models.Money
+	if err := q.Scan(&delta).Error; err != nil {
+		return 0, err
+	}
+	return base + delta, nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"insider-go-backend/internal/models"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -48,6 +50,30 @@ func ConnectDB(dsn string) {
 			&models.Transaction{},
 			&models.Balance{},
 			&models.AuditLog{},
+			&models.LedgerEntry{},
+			&models.FXRate{},
+			&models.IdempotencyKey{},
+			&models.Hold{},
+			&models.ScheduledTransfer{},
+			&models.ScheduledTransferRun{},
+			&models.StandingOrder{},
+			&models.StandingOrderRun{},
+			&models.LimitPolicy{},
+			&models.OverdraftAccrual{},
+			&models.FeeSchedule{},
+			&models.BalanceCheckpoint{},
+			&models.Batch{},
+			&models.BatchItem{},
+			&models.PaymentRequest{},
+			&models.Escrow{},
+			&models.InterestRate{},
+			&models.InterestAccrual{},
+			&models.InterestPosting{},
+			&models.Statement{},
+			&models.BankStatement{},
+			&models.BankStatementLine{},
+			&models.PaymentInitiation{},
+			&models.PaymentInitiationTx{},
 		); err != nil {
 			log.Printf("AutoMigrate failed: %v", err)
 		} else {
@@ -56,6 +82,18 @@ func ConnectDB(dsn string) {
 	}
 }
 
+// IsNotFound: kayıt bulunamadı hatası mı (servis katmanı gorm'a doğrudan bağımlı olmasın diye)
+func IsNotFound(err error) bool { return errors.Is(err, gorm.ErrRecordNotFound) }
+
+// uniqueViolation: hata benzersizlik ihlaliyse (SQLSTATE 23505) ihlal edilen kısıtın adını, değilse boş string döner
+func uniqueViolation(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
 func shouldAutoMigrate() bool {
 	v := os.Getenv("AUTO_MIGRATE")
 	if v == "" {
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormEscrowRepository struct{ db *gorm.DB }
+
+func NewGormEscrowRepository(db *gorm.DB) EscrowRepository {
+	return &gormEscrowRepository{db: db}
+}
+
+// Fund: escrow kaydını açar ve alıcıdan system:escrow hesabına fonlama işlemini aynı DB transaction'ı içinde yazar.
+// Transfer yolundaki gibi cüzdanlar doğrulanır, limitler uygulanır ve yetersiz bakiye reddedilir.
+func (r *gormEscrowRepository) Fund(e *models.Escrow, opts models.TxOptions) (*models.Transaction, error) {
+	rec := &models.Transaction{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var buyerB, sellerB models.Balance
+		if err := tx.Table("balances").Where("user_id = ? AND currency = ?", e.BuyerID, e.Currency).First(&buyerB).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("sender balance not found")
+			}
+			return err
+		}
+		// satıcının cüzdanı şimdiden olmalı; aksi halde release kapanışı yazılamaz
+		if err := tx.Table("balances").Where("user_id = ? AND currency = ?", e.SellerID, e.Currency).First(&sellerB).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("recipient balance not found")
+			}
+			return err
+		}
+		if err := enforceLimits(tx, e.BuyerID, e.Currency, e.Amount, false, opts.Limit); err != nil {
+			return err
+		}
+		e.Status = models.EscrowFunded
+		if err := tx.Table("escrows").Create(e).Error; err != nil {
+			return err
+		}
+		opts.Origin = &models.TxOrigin{Type: models.OriginEscrow, ID: e.ID, Seq: 0}
+		*rec = models.Transaction{FromUser: e.BuyerID, ToUser: e.SellerID, Amount: e.Amount, Currency: e.Currency, Type: models.TxTypeEscrowFund, Status: models.TxStatusCompleted, CreatedAt: time.Now()}
+		if err := createWithOptions(tx, rec, opts); err != nil {
+			return err
+		}
+		if err := postEntries(tx, rec.ID, rec.CreatedAt, true,
+			models.UserLeg(e.BuyerID, e.Currency, e.Amount.Neg()),
+			models.SystemLeg(models.AccountEscrow, e.Currency, e.Amount),
+		); err != nil {
+			return err
+		}
+		txID := rec.ID
+		e.FundTransactionID = &txID
+		return tx.Table("escrows").Where("id = ?", e.ID).Update("fund_transaction_id", txID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (r *gormEscrowRepository) Get(id int) (*models.Escrow, error) {
+	var e models.Escrow
+	if err := r.db.Table("escrows").First(&e, id).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// List: side "buyer" | "seller" | boş (ikisi); userID sıfırsa tüm escrow'lar (admin)
+func (r *gormEscrowRepository) List(userID int, side, status string) ([]models.Escrow, error) {
+	var items []models.Escrow
+	q := r.db.Table("escrows")
+	switch {
+	case userID == 0:
+	case side == "buyer":
+		q = q.Where("buyer_id = ?", userID)
+	case side == "seller":
+		q = q.Where("seller_id = ?", userID)
+	default:
+		q = q.Where("buyer_id = ? OR seller_id = ?", userID, userID)
+	}
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("created_at DESC, id DESC").Find(&items).Error
+	return items, err
+}
+
+// Decide: tarafın kararını kaydeder. İki taraf aynı sonucu onayladıysa escrow o sonuçla kapanır,
+// farklı sonuç onayladılarsa disputed olur. Süresi dolmuş (funded) escrow'a karar verilemez; timeout kapanışı işler.
+func (r *gormEscrowRepository) Decide(id, userID int, outcome string, now time.Time) (*models.Escrow, error) {
+	var e models.Escrow
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockEscrow(tx, id, &e); err != nil {
+			return err
+		}
+		if !e.Open() {
+			return errors.New("escrow not open")
+		}
+		if e.Status == models.EscrowFunded && !now.Before(e.ExpiresAt) {
+			return errors.New("escrow expired")
+		}
+		if userID == e.BuyerID {
+			e.BuyerDecision = outcome
+		} else {
+			e.SellerDecision = outcome
+		}
+		if e.BuyerDecision != "" && e.BuyerDecision == e.SellerDecision {
+			actor := userID
+			return settleEscrowTx(tx, &e, outcome, models.EscrowResolutionMutual, &actor, now)
+		}
+		if e.BuyerDecision != "" && e.SellerDecision != "" {
+			e.Status = models.EscrowDisputed
+		}
+		return tx.Table("escrows").Where("id = ?", e.ID).Updates(map[string]interface{}{
+			"buyer_decision": e.BuyerDecision, "seller_decision": e.SellerDecision, "status": e.Status, "updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Settle: escrow'u admin kararıyla ya da süre dolumuyla kapatır. Timeout yalnızca süresi dolmuş ve
+// ihtilafsız (funded) escrow'a uygulanır; arbitration açık her escrow'a uygulanabilir.
+func (r *gormEscrowRepository) Settle(id int, outcome, resolution string, actorID *int, now time.Time) (*models.Escrow, error) {
+	var e models.Escrow
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockEscrow(tx, id, &e); err != nil {
+			return err
+		}
+		if !e.Open() {
+			return errors.New("escrow not open")
+		}
+		if resolution == models.EscrowResolutionTimeout && (e.Status != models.EscrowFunded || now.Before(e.ExpiresAt)) {
+			return errors.New("escrow not expired")
+		}
+		return settleEscrowTx(tx, &e, outcome, resolution, actorID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// DueForTimeout: süresi dolmuş ihtilafsız escrow'ların id'leri
+func (r *gormEscrowRepository) DueForTimeout(now time.Time, limit int) ([]int, error) {
+	var ids []int
+	err := r.db.Table("escrows").
+		Where("status = ? AND expires_at <= ?", models.EscrowFunded, now).
+		Order("expires_at, id").Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func lockEscrow(tx *gorm.DB, id int, e *models.Escrow) error {
+	if err := tx.Table("escrows").Clauses(clause.Locking{Strength: "UPDATE"}).First(e, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("escrow not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// settleEscrowTx: system:escrow'daki tutarı satıcıya (release) ya da alıcıya (refund) geçiren işlemi yazar ve escrow'u kapatır.
+// Kapanış origin'i (escrow, id, 1) escrow başına tektir.
+func settleEscrowTx(tx *gorm.DB, e *models.Escrow, outcome, resolution string, actorID *int, now time.Time) error {
+	to, txType, status := e.SellerID, models.TxTypeEscrowRelease, models.EscrowReleased
+	if outcome == models.EscrowOutcomeRefund {
+		to, txType, status = e.BuyerID, models.TxTypeEscrowRefund, models.EscrowRefunded
+	}
+	rec := &models.Transaction{FromUser: e.BuyerID, ToUser: to, Amount: e.Amount, Currency: e.Currency, Type: txType, Status: models.TxStatusCompleted, CreatedAt: now}
+	opts := models.TxOptions{Origin: &models.TxOrigin{Type: models.OriginEscrow, ID: e.ID, Seq: 1}}
+	if err := createWithOptions(tx, rec, opts); err != nil {
+		return err
+	}
+	if err := postEntries(tx, rec.ID, rec.CreatedAt, false,
+		models.SystemLeg(models.AccountEscrow, e.Currency, e.Amount.Neg()),
+		models.UserLeg(to, e.Currency, e.Amount),
+	); err != nil {
+		return err
+	}
+	txID := rec.ID
+	e.Status, e.SettleTransactionID, e.Resolution, e.ResolvedBy, e.ResolvedAt = status, &txID, resolution, actorID, &now
+	return tx.Table("escrows").Where("id = ?", e.ID).Updates(map[string]interface{}{
+		"status": e.Status, "buyer_decision": e.BuyerDecision, "seller_decision": e.SellerDecision,
+		"settle_transaction_id": txID, "resolution": resolution, "resolved_by": actorID, "resolved_at": now, "updated_at": now,
+	}).Error
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormFeeRepository struct{ db *gorm.DB }
+
+func NewGormFeeRepository(db *gorm.DB) FeeRepository {
+	return &gormFeeRepository{db: db}
+}
+
+// ListSchedules: boş filtreler uygulanmaz; at verilirse yalnızca o anda geçerli tarifeler döner
+func (r *gormFeeRepository) ListSchedules(txType, currency string, at *time.Time) ([]models.FeeSchedule, error) {
+	var items []models.FeeSchedule
+	q := r.db.Table("fee_schedules")
+	if txType != "" {
+		q = q.Where("tx_type = ?", txType)
+	}
+	if currency != "" {
+		q = q.Where("currency = ?", currency)
+	}
+	if at != nil {
+		q = q.Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", *at, *at)
+	}
+	err := q.Order("tx_type, currency, effective_from DESC, id").Find(&items).Error
+	return items, err
+}
+
+func (r *gormFeeRepository) GetSchedule(id int) (*models.FeeSchedule, error) {
+	var f models.FeeSchedule
+	if err := r.db.Table("fee_schedules").First(&f, id).Error; err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *gormFeeRepository) CreateSchedule(f *models.FeeSchedule) error {
+	return r.db.Table("fee_schedules").Create(f).Error
+}
+
+func (r *gormFeeRepository) UpdateSchedule(f *models.FeeSchedule) error {
+	res := r.db.Table("fee_schedules").Where("id = ?", f.ID).Select("*").Omit("id", "created_at").Updates(f)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormFeeRepository) DeleteSchedule(id int) error {
+	res := r.db.Table("fee_schedules").Where("id = ?", id).Delete(&models.FeeSchedule{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormFeeRepository) FindActive(txType, currency string, at time.Time) ([]models.FeeSchedule, error) {
+	return r.ListSchedules(txType, currency, &at)
+}
+
+// chargeFee: ana işleme bağlı ücret transaction'ını aynı DB transaction'ı içinde yazar.
+// Ücret kullanıcının kullanılabilir bakiyesini aşarsa "insufficient funds" döner ve ana işlem de geri alınır.
+func chargeFee(tx *gorm.DB, parent *models.Transaction, fee *models.FeeQuote) error {
+	if fee == nil || !fee.Fee.IsPositive() {
+		return nil
+	}
+	parentID := parent.ID
+	rec := &models.Transaction{
+		FromUser: parent.FromUser, ToUser: parent.FromUser, Amount: fee.Fee, Currency: parent.Currency,
+		Type: models.TxTypeFee, Status: models.TxStatusCompleted, CreatedAt: parent.CreatedAt, ParentID: &parentID,
+	}
+	if err := tx.Table("transactions").Create(rec).Error; err != nil {
+		return err
+	}
+	return postEntries(tx, rec.ID, rec.CreatedAt, true,
+		models.UserLeg(parent.FromUser, parent.Currency, fee.Fee.Neg()),
+		models.SystemLeg(models.AccountFees, parent.Currency, fee.Fee),
+	)
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormFXRateRepository struct{ db *gorm.DB }
+
+func NewGormFXRateRepository(db *gorm.DB) FXRateRepository {
+	return &gormFXRateRepository{db: db}
+}
+
+// CreateRate: yeni kuru ekler; aynı çiftin açık uçlu önceki penceresini yeni kaydın başlangıcında kapatır
+func (r *gormFXRateRepository) CreateRate(rate *models.FXRate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return insertFXRate(tx, rate)
+	})
+}
+
+// CreateRates: toplu yükleme; kayıtlar tek DB transaction'ında ya hep ya hiç yazılır
+func (r *gormFXRateRepository) CreateRates(rates []models.FXRate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range rates {
+			if err := insertFXRate(tx, &rates[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *gormFXRateRepository) GetRateAt(base, quote string, at time.Time) (*models.FXRate, error) {
+	var rate models.FXRate
+	err := r.db.Table("fx_rates").
+		Where("base_currency = ? AND quote_currency = ? AND valid_from <= ? AND (valid_to IS NULL OR valid_to > ?)", base, quote, at, at).
+		Order("valid_from DESC, id DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *gormFXRateRepository) ListRates(base, quote string, limit int) ([]models.FXRate, error) {
+	var rates []models.FXRate
+	q := r.db.Table("fx_rates")
+	if base != "" {
+		q = q.Where("base_currency = ?", base)
+	}
+	if quote != "" {
+		q = q.Where("quote_currency = ?", quote)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	err := q.Order("valid_from DESC, id DESC").Find(&rates).Error
+	return rates, err
+}
+
+func insertFXRate(tx *gorm.DB, rate *models.FXRate) error {
+	if err := tx.Exec("UPDATE fx_rates SET valid_to = ? WHERE base_currency = ? AND quote_currency = ? AND valid_to IS NULL AND valid_from < ?",
+		rate.ValidFrom, rate.Base, rate.Quote, rate.ValidFrom).Error; err != nil {
+		return err
+	}
+	return tx.Table("fx_rates").Create(rate).Error
+}
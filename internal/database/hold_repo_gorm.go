@@ -0,0 +1,213 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormHoldRepository struct{ db *gorm.DB }
+
+func NewGormHoldRepository(db *gorm.DB) HoldRepository {
+	return &gormHoldRepository{db: db}
+}
+
+// CreateHold: kullanılabilir bakiyeden tutarı rezerve eder ve hold kaydını yazar (tek DB transaction'ı).
+// Limitler burada ön kontrol olarak uygulanır; capture anında gerçekleşen tutarla yeniden kontrol edilir.
+func (r *gormHoldRepository) CreateHold(hold *models.Hold, lim *models.EffectiveLimit) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := enforceLimits(tx, hold.UserID, hold.Currency, hold.Amount, hold.ToUserID != nil, lim); err != nil {
+			return err
+		}
+		if hold.ToUserID != nil {
+			var n int64
+			if err := tx.Table("balances").Where("user_id = ? AND currency = ?", *hold.ToUserID, hold.Currency).Count(&n).Error; err != nil {
+				return err
+			}
+			if n == 0 {
+				return errors.New("recipient balance not found")
+			}
+		}
+		res := tx.Exec("UPDATE balances SET held = held + ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND currency = ? AND amount - held + credit_limit >= ?",
+			hold.Amount, hold.UserID, hold.Currency, hold.Amount)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return missingOrInsufficient(tx, hold.UserID, hold.Currency)
+		}
+		hold.Status = models.HoldStatusActive
+		return tx.Table("holds").Create(hold).Error
+	})
+}
+
+func (r *gormHoldRepository) GetHold(id int) (*models.Hold, error) {
+	var h models.Hold
+	if err := r.db.Table("holds").First(&h, id).Error; err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// ListHoldsByUser: kullanıcının (payer ya da alıcı olarak) hold'ları; status boşsa hepsi
+func (r *gormHoldRepository) ListHoldsByUser(userID int, status string) ([]models.Hold, error) {
+	var holds []models.Hold
+	q := r.db.Table("holds").Where("user_id = ? OR to_user_id = ?", userID, userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("created_at DESC, id DESC").Find(&holds).Error
+	return holds, err
+}
+
+// CaptureHold: hold'u serbest bırakıp amount kadar debit (ya da ToUserID'ye transfer) yazar.
+// amount sıfırsa hold tutarının tamamı çekilir; kısmi capture'da kalan kısım serbest kalır.
+// Limitler payer için advisory lock altında kontrol edilir; capture kaydı debit/transfer olarak sonraki pencere toplamlarına girer.
+// Ücret payer'dan aynı DB transaction'ında alınır; bakiye kontrolü capture ve ücretin toplamını kapsar.
+func (r *gormHoldRepository) CaptureHold(id int, amount models.Money, lim *models.EffectiveLimit, fee *models.FeeQuote) (*models.Hold, *models.Transaction, error) {
+	hold := &models.Hold{}
+	rec := &models.Transaction{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockActiveHold(tx, id, hold); err != nil {
+			return err
+		}
+		now := time.Now()
+		if !hold.ExpiresAt.After(now) {
+			return errors.New("hold expired")
+		}
+		if amount.IsZero() {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return errors.New("capture exceeds hold amount")
+		}
+		if err := enforceLimits(tx, hold.UserID, hold.Currency, amount, hold.ToUserID != nil, lim); err != nil {
+			return err
+		}
+		if err := releaseHeld(tx, hold); err != nil {
+			return err
+		}
+
+		*rec = models.Transaction{FromUser: hold.UserID, ToUser: hold.UserID, Amount: amount, Currency: hold.Currency, Type: "debit", Status: "completed", CreatedAt: now}
+		legs := []models.LedgerEntry{
+			models.UserLeg(hold.UserID, hold.Currency, amount.Neg()),
+			models.SystemLeg(models.AccountSettlement, hold.Currency, amount),
+		}
+		if hold.ToUserID != nil {
+			rec.ToUser, rec.Type = *hold.ToUserID, "transfer"
+			legs[1] = models.UserLeg(*hold.ToUserID, hold.Currency, amount)
+		}
+		if err := tx.Table("transactions").Create(rec).Error; err != nil {
+			return err
+		}
+		if err := postEntries(tx, rec.ID, rec.CreatedAt, true, legs...); err != nil {
+			return err
+		}
+		if err := chargeFee(tx, rec, fee); err != nil {
+			return err
+		}
+
+		txID := rec.ID
+		hold.Status, hold.CapturedAmount, hold.TransactionID, hold.ReleasedAt = models.HoldStatusCaptured, amount, &txID, &now
+		return tx.Table("holds").Where("id = ?", hold.ID).Updates(map[string]interface{}{
+			"status":          hold.Status,
+			"captured_amount": hold.CapturedAmount,
+			"transaction_id":  txID,
+			"released_at":     now,
+		}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return hold, rec, nil
+}
+
+// VoidHold: aktif hold'u iptal eder ve rezerve tutarı serbest bırakır
+func (r *gormHoldRepository) VoidHold(id int) (*models.Hold, error) {
+	hold := &models.Hold{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockActiveHold(tx, id, hold); err != nil {
+			return err
+		}
+		return closeHold(tx, hold, models.HoldStatusVoided)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// ExpireHolds: süresi dolmuş aktif hold'ları serbest bırakır.
+// SKIP LOCKED sayesinde birden fazla replika aynı anda çalıştırabilir; capture/void ile yarışmaz.
+func (r *gormHoldRepository) ExpireHolds(now time.Time, limit int) ([]models.Hold, error) {
+	var expired []models.Hold
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.Hold
+		if err := tx.Table("holds").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at <= ?", models.HoldStatusActive, now).
+			Order("expires_at").Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		for i := range due {
+			if err := closeHold(tx, &due[i], models.HoldStatusExpired); err != nil {
+				return err
+			}
+		}
+		expired = due
+		return nil
+	})
+	return expired, err
+}
+
+// lockActiveHold: hold'u FOR UPDATE ile kilitler; aktif değilse hata döner
+func lockActiveHold(tx *gorm.DB, id int, hold *models.Hold) error {
+	if err := tx.Table("holds").Clauses(clause.Locking{Strength: "UPDATE"}).First(hold, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("hold not found")
+		}
+		return err
+	}
+	if hold.Status != models.HoldStatusActive {
+		return errors.New("hold not active")
+	}
+	return nil
+}
+
+// closeHold: rezerve tutarı serbest bırakıp hold'u verilen durumla kapatır
+func closeHold(tx *gorm.DB, hold *models.Hold, status string) error {
+	if err := releaseHeld(tx, hold); err != nil {
+		return err
+	}
+	now := time.Now()
+	hold.Status, hold.ReleasedAt = status, &now
+	return tx.Table("holds").Where("id = ?", hold.ID).Updates(map[string]interface{}{"status": status, "released_at": now}).Error
+}
+
+func releaseHeld(tx *gorm.DB, hold *models.Hold) error {
+	res := tx.Exec("UPDATE balances SET held = held - ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND currency = ? AND held >= ?",
+		hold.Amount, hold.UserID, hold.Currency, hold.Amount)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("balance not found")
+	}
+	return nil
+}
+
+// missingOrInsufficient: koşullu UPDATE satır bulamadığında nedenini ayırt eder
+func missingOrInsufficient(tx *gorm.DB, userID int, currency string) error {
+	var n int64
+	if err := tx.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).Count(&n).Error; err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("balance not found")
+	}
+	return errors.New("insufficient funds")
+}
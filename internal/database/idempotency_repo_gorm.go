@@ -0,0 +1,58 @@
+package database
+
+import (
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormIdempotencyRepository struct{ db *gorm.DB }
+
+func NewGormIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &gormIdempotencyRepository{db: db}
+}
+
+// Reserve: anahtarı "işleniyor" olarak ayırır. Anahtar zaten varsa (ve süresi dolmamışsa) mevcut kaydı döner.
+// Süresi dolmuş kayıt yenisiyle değiştirilir.
+func (r *gormIdempotencyRepository) Reserve(rec *models.IdempotencyKey) (*models.IdempotencyKey, bool, error) {
+	var existing models.IdempotencyKey
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND expires_at <= ?", rec.UserID, rec.Key, time.Now()).Error; err != nil {
+			return err
+		}
+		res := tx.Table("idempotency_keys").Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			created = true
+			existing = *rec
+			return nil
+		}
+		return tx.Table("idempotency_keys").Where("user_id = ? AND idempotency_key = ?", rec.UserID, rec.Key).First(&existing).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, created, nil
+}
+
+func (r *gormIdempotencyRepository) Complete(userID int, key string, statusCode int, contentType, body string) error {
+	return r.db.Table("idempotency_keys").Where("user_id = ? AND idempotency_key = ?", userID, key).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"content_type":  contentType,
+		"response_body": body,
+	}).Error
+}
+
+func (r *gormIdempotencyRepository) Release(userID int, key string) error {
+	return r.db.Exec("DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND status_code = 0", userID, key).Error
+}
+
+func (r *gormIdempotencyRepository) DeleteExpired(before time.Time) (int64, error) {
+	res := r.db.Exec("DELETE FROM idempotency_keys WHERE expires_at <= ?", before)
+	return res.RowsAffected, res.Error
+}
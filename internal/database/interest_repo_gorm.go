@@ -0,0 +1,206 @@
+package database
+
+import (
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormInterestRepository struct{ db *gorm.DB }
+
+func NewGormInterestRepository(db *gorm.DB) InterestRepository {
+	return &gormInterestRepository{db: db}
+}
+
+// ListRates: currency boşsa tümü; at verilirse yalnızca o anda geçerli tarifeler
+func (r *gormInterestRepository) ListRates(currency string, at *time.Time) ([]models.InterestRate, error) {
+	var items []models.InterestRate
+	q := r.db.Table("interest_rates")
+	if currency != "" {
+		q = q.Where("currency = ?", currency)
+	}
+	if at != nil {
+		q = q.Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", *at, *at)
+	}
+	err := q.Order("currency, min_balance, effective_from DESC, id").Find(&items).Error
+	return items, err
+}
+
+func (r *gormInterestRepository) GetRate(id int) (*models.InterestRate, error) {
+	var rate models.InterestRate
+	if err := r.db.Table("interest_rates").First(&rate, id).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *gormInterestRepository) CreateRate(rate *models.InterestRate) error {
+	return r.db.Table("interest_rates").Create(rate).Error
+}
+
+func (r *gormInterestRepository) UpdateRate(rate *models.InterestRate) error {
+	res := r.db.Table("interest_rates").Where("id = ?", rate.ID).Select("*").Omit("id", "created_at").Updates(rate)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *gormInterestRepository) DeleteRate(id int) error {
+	res := r.db.Table("interest_rates").Where("id = ?", id).Delete(&models.InterestRate{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListWallets: filtreye uyan cüzdanlar; userID sıfır ya da currency boşsa o filtre uygulanmaz
+func (r *gormInterestRepository) ListWallets(userID int, currency string) ([]models.Balance, error) {
+	var items []models.Balance
+	q := r.db.Table("balances")
+	if userID != 0 {
+		q = q.Where("user_id = ?", userID)
+	}
+	if currency != "" {
+		q = q.Where("currency = ?", currency)
+	}
+	err := q.Order("user_id, currency").Find(&items).Error
+	return items, err
+}
+
+// ClosingBalances: from..to (dahil, UTC gün başları) arasındaki her günün kapanış bakiyesi.
+// Açılış checkpoint üzerinden bir kez hesaplanır, sonrası günlük ledger toplamlarıyla ilerletilir.
+func (r *gormInterestRepository) ClosingBalances(userID int, currency string, from, to time.Time) ([]models.WalletDay, error) {
+	end := to.AddDate(0, 0, 1)
+	open, err := (&gormCheckpointRepository{db: r.db}).BalanceAt(userID, currency, from.Add(-time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Day   time.Time
+		Delta models.Money
+	}
+	if err := r.db.Raw(`SELECT (created_at AT TIME ZONE 'UTC')::date AS day, SUM(amount) AS delta
+		FROM ledger_entries
+		WHERE user_id = ? AND currency = ? AND created_at >= ? AND created_at < ?
+		GROUP BY 1 ORDER BY 1`, userID, currency, from, end).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	deltas := make(map[string]models.Money, len(rows))
+	for _, row := range rows {
+		deltas[row.Day.Format("2006-01-02")] = row.Delta
+	}
+	var days []models.WalletDay
+	balance := open
+	for d := from; d.Before(end); d = d.AddDate(0, 0, 1) {
+		balance += deltas[d.Format("2006-01-02")]
+		days = append(days, models.WalletDay{UserID: userID, Currency: currency, Day: d, Balance: balance})
+	}
+	return days, nil
+}
+
+// InsertAccrual: (user, currency, gün) için tahakkuku yazar; zaten varsa false döner (tekrar çalıştırmalar çift faiz yazmaz)
+func (r *gormInterestRepository) InsertAccrual(a *models.InterestAccrual) (bool, error) {
+	res := r.db.Table("interest_accruals").Clauses(clause.OnConflict{DoNothing: true}).Create(a)
+	return res.RowsAffected > 0, res.Error
+}
+
+// LastAccrualDate: en son tahakkuk yazılan gün; hiç yoksa nil
+func (r *gormInterestRepository) LastAccrualDate() (*time.Time, error) {
+	var last *time.Time
+	err := r.db.Table("interest_accruals").Select("MAX(accrual_date)").Scan(&last).Error
+	return last, err
+}
+
+// UnpostedPeriods: before'dan önceki aylara ait, henüz ödenmemiş tahakkuku olan cüzdan-aylar.
+// Cüzdanın son ödenen ayına ya da öncesine düşen (geç yazılmış) tahakkuklar bir sonraki aya sayılır.
+func (r *gormInterestRepository) UnpostedPeriods(before time.Time) ([]models.InterestPeriod, error) {
+	var items []models.InterestPeriod
+	err := r.db.Raw(`SELECT user_id, currency, month FROM (
+			SELECT a.user_id, a.currency,
+				GREATEST(date_trunc('month', a.accrual_date)::date, (p.last_month + INTERVAL '1 month')::date) AS month
+			FROM interest_accruals a
+			LEFT JOIN (SELECT user_id, currency, MAX(month) AS last_month FROM interest_postings GROUP BY 1, 2) p
+				ON p.user_id = a.user_id AND p.currency = a.currency
+			WHERE a.posted_at IS NULL
+		) u
+		WHERE month < ?
+		GROUP BY 1, 2, 3 ORDER BY 3, 1, 2`, before).Scan(&items).Error
+	return items, err
+}
+
+// PostInterest: ayın (ve önceki aylardan kalan) ödenmemiş tahakkuklarını toplar, minor unit'e yuvarlar ve
+// system:interest_expense'ten cüzdana tek bir interest işlemi olarak yazar. Ay interest_postings'e (user, currency, month)
+// benzersizliğiyle kaydedilir; ay zaten ödendiyse (paralel replika ya da tekrar çalıştırma) hiçbir şey yazılmaz.
+// Tahakkuklar ödeme işlemine bağlanır; yuvarlanan tutar sıfırsa işlem yazılmaz ama tahakkuklar ödenmiş sayılır.
+func (r *gormInterestRepository) PostInterest(p models.InterestPeriod) (*models.Transaction, models.Money, error) {
+	var rec *models.Transaction
+	var amount models.Money
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		posting := &models.InterestPosting{UserID: p.UserID, Currency: p.Currency, Month: p.Month}
+		res := tx.Table("interest_postings").Clauses(clause.OnConflict{DoNothing: true}).Create(posting)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		monthEnd := p.Month.AddDate(0, 1, 0)
+		var accruals []models.InterestAccrual
+		if err := tx.Table("interest_accruals").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND currency = ? AND accrual_date < ? AND posted_at IS NULL", p.UserID, p.Currency, monthEnd).
+			Find(&accruals).Error; err != nil {
+			return err
+		}
+		if len(accruals) == 0 {
+			return nil
+		}
+		var sum models.Money
+		dates := make([]time.Time, len(accruals))
+		for i, a := range accruals {
+			sum += a.Interest
+			dates[i] = a.AccrualDate
+		}
+		cur, err := models.LookupCurrency(p.Currency)
+		if err != nil {
+			return err
+		}
+		amount = sum.Round(cur)
+		now := time.Now()
+		updates := map[string]interface{}{"posted_at": now}
+		if amount.IsPositive() {
+			rec = &models.Transaction{FromUser: p.UserID, ToUser: p.UserID, Amount: amount, Currency: p.Currency, Type: models.TxTypeInterest, Status: models.TxStatusCompleted, CreatedAt: now}
+			opts := models.TxOptions{Origin: &models.TxOrigin{Type: models.OriginInterestPosting, ID: posting.ID}}
+			if err := createWithOptions(tx, rec, opts); err != nil {
+				return err
+			}
+			if err := postEntries(tx, rec.ID, rec.CreatedAt, false,
+				models.SystemLeg(models.AccountInterestExpense, p.Currency, amount.Neg()),
+				models.UserLeg(p.UserID, p.Currency, amount),
+			); err != nil {
+				return err
+			}
+			updates["transaction_id"] = rec.ID
+			if err := tx.Table("interest_postings").Where("id = ?", posting.ID).
+				Updates(map[string]interface{}{"amount": amount, "transaction_id": rec.ID}).Error; err != nil {
+				return err
+			}
+		}
+		// yalnızca kilitlenip toplanan tahakkuklar ödenmiş işaretlenir
+		return tx.Table("interest_accruals").
+			Where("user_id = ? AND currency = ? AND accrual_date IN ? AND posted_at IS NULL", p.UserID, p.Currency, dates).
+			Updates(updates).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return rec, amount, nil
+}
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnbalancedPosting: kayıt bacaklarının toplamı sıfır değil
+var ErrUnbalancedPosting = errors.New("unbalanced ledger posting")
+
+type gormLedgerRepository struct{ db *gorm.DB }
+
+func NewGormLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &gormLedgerRepository{db: db}
+}
+
+func (r *gormLedgerRepository) GetEntriesByTransaction(txID int) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.db.Table("ledger_entries").Where("transaction_id = ?", txID).Order("id").Find(&entries).Error
+	return entries, err
+}
+
+func (r *gormLedgerRepository) GetEntriesByAccount(account string) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.db.Table("ledger_entries").Where("account = ?", account).Order("created_at, id").Find(&entries).Error
+	return entries, err
+}
+
+func (r *gormLedgerRepository) SumPostingsByCurrency(at time.Time) ([]models.CurrencyTotal, error) {
+	var rows []models.CurrencyTotal
+	err := r.db.Table("ledger_entries").
+		Select("currency, SUM(amount) AS total").
+		Where("created_at <= ?", at).
+		Group("currency").
+		Order("currency").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *gormLedgerRepository) AccountBalances(at time.Time) ([]models.AccountBalance, error) {
+	var rows []models.AccountBalance
+	err := r.db.Table("ledger_entries").
+		Select("account, currency, SUM(amount) AS balance").
+		Where("created_at <= ?", at).
+		Group("account, currency").
+		Order("account, currency").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// postEntries: çift taraflı kayıtları yazar ve balances projeksiyonunu aynı DB transaction'ı içinde günceller.
+// Bacaklar her para biriminde ayrı ayrı sıfıra toplanmalıdır.
+// checkFunds true ise kullanılabilir bakiyeyi (amount - held + credit_limit) aşan kullanıcı bacakları "insufficient funds" ile reddedilir.
+func postEntries(tx *gorm.DB, txID int, at time.Time, checkFunds bool, legs ...models.LedgerEntry) error {
+	if len(legs) < 2 {
+		return ErrUnbalancedPosting
+	}
+	sums := make(map[string]models.Money)
+	rows := make([]models.LedgerEntry, len(legs))
+	for i, l := range legs {
+		if l.Currency == "" {
+			return ErrUnbalancedPosting
+		}
+		sums[l.Currency] += l.Amount
+		l.TransactionID = txID
+		l.CreatedAt = at
+		rows[i] = l
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return ErrUnbalancedPosting
+		}
+	}
+	if err := tx.Table("ledger_entries").Create(&rows).Error; err != nil {
+		return err
+	}
+
+	// projeksiyon: kullanıcı bacaklarını user_id sırasıyla uygula (karşılıklı transferlerde deadlock'u önler)
+	userLegs := make([]models.LedgerEntry, 0, len(rows))
+	for _, l := range rows {
+		if l.UserID != nil {
+			userLegs = append(userLegs, l)
+		}
+	}
+	sort.SliceStable(userLegs, func(i, j int) bool { return *userLegs[i].UserID < *userLegs[j].UserID })
+	for _, l := range userLegs {
+		q := "UPDATE balances SET amount = amount + ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND currency = ?"
+		args := []interface{}{l.Amount, *l.UserID, l.Currency}
+		if checkFunds && l.Amount.IsNegative() {
+			q += " AND amount - held + credit_limit >= ?"
+			args = append(args, l.Amount.Neg())
+		}
+		res := tx.Exec(q, args...)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			if checkFunds && l.Amount.IsNegative() {
+				return errors.New("insufficient funds")
+			}
+			return errors.New("balance not found")
+		}
+	}
+	return nil
+}
+
+// postAdjustment: cüzdanı delta kadar düzelten txType ("adjustment" ya da "opening_balance") transaction'ı ve karşılık kayıtlarını yazar
+func postAdjustment(tx *gorm.DB, txType string, userID int, currency string, delta models.Money) error {
+	if delta.IsZero() {
+		return nil
+	}
+	rec := &models.Transaction{FromUser: userID, ToUser: userID, Amount: delta, Currency: currency, Type: txType, Status: "completed", CreatedAt: time.Now()}
+	if err := tx.Table("transactions").Create(rec).Error; err != nil {
+		return err
+	}
+	return postEntries(tx, rec.ID, rec.CreatedAt, false,
+		models.UserLeg(userID, currency, delta),
+		models.SystemLeg(models.AccountAdjustment, currency, delta.Neg()),
+	)
+}
+
+func (r *gormLedgerRepository) WalletDrift() (int64, []models.ReconcileItem, error) {
+	var wallets int64
+	if err := r.db.Table("balances").Count(&wallets).Error; err != nil {
+		return 0, nil, err
+	}
+	var items []models.ReconcileItem
+	err := r.db.Raw(`SELECT b.user_id, b.currency, COALESCE(SUM(e.amount), 0) AS expected, b.amount AS actual,
+			b.amount - COALESCE(SUM(e.amount), 0) AS delta
+		FROM balances b
+		LEFT JOIN ledger_entries e ON e.user_id = b.user_id AND e.currency = b.currency
+		GROUP BY b.user_id, b.currency, b.amount
+		HAVING b.amount <> COALESCE(SUM(e.amount), 0)
+		ORDER BY b.user_id, b.currency`).Scan(&items).Error
+	return wallets, items, err
+}
+
+// CorrectDrift: balances'ı doğru kabul eder ve farkı "adjustment" transaction'ı olarak yalnızca ledger'a işler
+// (karşı taraf system:adjustment). Sapma cüzdan kilidi altında yeniden ölçülür; eşzamanlı işlemler yarışamaz.
+func (r *gormLedgerRepository) CorrectDrift(userID int, currency string) (*models.ReconcileItem, error) {
+	var item *models.ReconcileItem
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var b models.Balance
+		if err := tx.Table("balances").Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ? AND currency = ?", userID, currency).First(&b).Error; err != nil {
+			return err
+		}
+		var expected models.Money
+		if err := tx.Table("ledger_entries").Select("COALESCE(SUM(amount), 0)").Where("user_id = ? AND currency = ?", userID, currency).Scan(&expected).Error; err != nil {
+			return err
+		}
+		delta := b.Amount - expected
+		if delta.IsZero() {
+			return nil
+		}
+		rec := &models.Transaction{FromUser: userID, ToUser: userID, Amount: delta, Currency: currency, Type: models.TxTypeAdjustment, Status: models.TxStatusCompleted, CreatedAt: time.Now()}
+		if err := tx.Table("transactions").Create(rec).Error; err != nil {
+			return err
+		}
+		// projeksiyon zaten delta kadar ileride; postEntries balances'ı ikinci kez değiştireceğinden kayıtlar doğrudan yazılır
+		legs := []models.LedgerEntry{
+			models.UserLeg(userID, currency, delta),
+			models.SystemLeg(models.AccountAdjustment, currency, delta.Neg()),
+		}
+		for i := range legs {
+			legs[i].TransactionID, legs[i].CreatedAt = rec.ID, rec.CreatedAt
+		}
+		if err := tx.Table("ledger_entries").Create(&legs).Error; err != nil {
+			return err
+		}
+		txID := rec.ID
+		item = &models.ReconcileItem{UserID: userID, Currency: currency, Expected: expected, Actual: b.Amount, Delta: delta, Corrected: true, TransactionID: &txID}
+		return nil
+	})
+	return item, err
+}
+
+// TransactionDrift: işlemleri ledger bacaklarıyla karşılaştırır. Her işlemin bacakları para birimi başına sıfıra
+// toplanmalı, split_part dışındaki her işlemin bacağı olmalı ve sabit biçimli tiplerde cüzdan bacakları
+// from/to/amount (FX'te counter_amount) alanlarından beklenen tutarlara eşit olmalıdır. split_part payları
+// ebeveyn split'in bacaklarıyla karşılaştırılır; reversal/refund bacakları orantılı olduğundan yalnızca denge kontrol edilir.
+func (r *gormLedgerRepository) TransactionDrift() (int64, []models.TxReconcileItem, error) {
+	var transactions int64
+	if err := r.db.Table("transactions").Where("type <> ?", models.TxTypeSplitPart).Count(&transactions).Error; err != nil {
+		return 0, nil, err
+	}
+	var items, rows []models.TxReconcileItem
+	if err := r.db.Raw(`SELECT e.transaction_id, COALESCE(t.type, '') AS type, CAST(? AS TEXT) AS reason, e.currency, 0 AS expected, SUM(e.amount) AS actual
+		FROM ledger_entries e
+		LEFT JOIN transactions t ON t.id = e.transaction_id
+		GROUP BY e.transaction_id, t.type, e.currency
+		HAVING SUM(e.amount) <> 0
+		ORDER BY e.transaction_id, e.currency`, models.TxMismatchUnbalanced).Scan(&rows).Error; err != nil {
+		return 0, nil, err
+	}
+	items = append(items, rows...)
+
+	rows = nil
+	if err := r.db.Raw(`SELECT t.id AS transaction_id, t.type, CAST(? AS TEXT) AS reason, t.currency, t.amount AS expected, 0 AS actual
+		FROM transactions t
+		WHERE t.type <> ? AND NOT EXISTS (SELECT 1 FROM ledger_entries e WHERE e.transaction_id = t.id)
+		ORDER BY t.id`, models.TxMismatchMissingLegs, models.TxTypeSplitPart).Scan(&rows).Error; err != nil {
+		return 0, nil, err
+	}
+	items = append(items, rows...)
+
+	credits := []string{"credit", models.TxTypeInterest, models.TxTypeAdjustment, models.TxTypeOpeningBalance, models.TxTypeEscrowRelease, models.TxTypeEscrowRefund}
+	debits := []string{"debit", "transfer", models.TxTypeFee, models.TxTypeOverdraftInterest, models.TxTypeEscrowFund, models.TxTypeSplit}
+	checked := append(append([]string{}, credits...), debits...)
+	rows = nil
+	if err := r.db.Raw(`WITH expected AS (
+			SELECT id AS transaction_id, to_user_id AS user_id, currency, amount FROM transactions WHERE type IN ?
+			UNION ALL
+			SELECT id, from_user_id, currency, -amount FROM transactions WHERE type IN ?
+			UNION ALL
+			SELECT id, to_user_id, COALESCE(counter_currency, currency), COALESCE(counter_amount, amount) FROM transactions WHERE type = 'transfer'
+			UNION ALL
+			SELECT parent_id, to_user_id, currency, amount FROM transactions WHERE type = ? AND parent_id IS NOT NULL
+		), exp AS (
+			SELECT transaction_id, user_id, currency, SUM(amount) AS amount FROM expected GROUP BY 1, 2, 3
+		), act AS (
+			SELECT e.transaction_id, e.user_id, e.currency, SUM(e.amount) AS amount
+			FROM ledger_entries e JOIN transactions t ON t.id = e.transaction_id
+			WHERE e.user_id IS NOT NULL AND t.type IN ?
+			GROUP BY 1, 2, 3
+		)
+		SELECT t.id AS transaction_id, t.type, CAST(? AS TEXT) AS reason, COALESCE(x.user_id, a.user_id) AS user_id,
+			COALESCE(x.currency, a.currency) AS currency, COALESCE(x.amount, 0) AS expected, COALESCE(a.amount, 0) AS actual
+		FROM exp x
+		FULL OUTER JOIN act a ON a.transaction_id = x.transaction_id AND a.user_id = x.user_id AND a.currency = x.currency
+		JOIN transactions t ON t.id = COALESCE(x.transaction_id, a.transaction_id)
+		WHERE COALESCE(x.amount, 0) <> COALESCE(a.amount, 0)
+			AND EXISTS (SELECT 1 FROM ledger_entries e WHERE e.transaction_id = t.id)
+		ORDER BY t.id, 4, 5`, credits, debits, models.TxTypeSplitPart, checked, models.TxMismatchWalletLeg).Scan(&rows).Error; err != nil {
+		return 0, nil, err
+	}
+	items = append(items, rows...)
+	return transactions, items, nil
+}
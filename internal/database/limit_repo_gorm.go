@@ -0,0 +1,148 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type gormLimitRepository struct{ db *gorm.DB }
+
+func NewGormLimitRepository(db *gorm.DB) LimitRepository {
+	return &gormLimitRepository{db: db}
+}
+
+// ListPolicies: role/userID boşsa filtre uygulanmaz
+func (r *gormLimitRepository) ListPolicies(role string, userID int) ([]models.LimitPolicy, error) {
+	var items []models.LimitPolicy
+	q := r.db.Table("limit_policies")
+	if role != "" {
+		q = q.Where("role = ?", role)
+	}
+	if userID != 0 {
+		q = q.Where("user_id = ?", userID)
+	}
+	err := q.Order("role NULLS LAST, user_id, currency").Find(&items).Error
+	return items, err
+}
+
+func (r *gormLimitRepository) GetPolicy(id int) (*models.LimitPolicy, error) {
+	var p models.LimitPolicy
+	if err := r.db.Table("limit_policies").First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpsertPolicy: aynı kapsam (rol ya da kullanıcı) + para birimi için tek politika tutulur
+func (r *gormLimitRepository) UpsertPolicy(p *models.LimitPolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.LimitPolicy
+		q := tx.Table("limit_policies").Where("currency = ?", p.Currency)
+		if p.Role != nil {
+			q = q.Where("role = ?", *p.Role)
+		} else {
+			q = q.Where("user_id = ?", *p.UserID)
+		}
+		err := q.First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Table("limit_policies").Create(p).Error
+		}
+		if err != nil {
+			return err
+		}
+		p.ID, p.CreatedAt = existing.ID, existing.CreatedAt
+		return tx.Table("limit_policies").Where("id = ?", p.ID).Select("*").Omit("id", "created_at").Updates(p).Error
+	})
+}
+
+func (r *gormLimitRepository) DeletePolicy(id int) error {
+	res := r.db.Table("limit_policies").Where("id = ?", id).Delete(&models.LimitPolicy{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindPolicies: para birimi için rol varsayılanı ve kullanıcı override'ı (yoksa nil)
+func (r *gormLimitRepository) FindPolicies(role string, userID int, currency string) (*models.LimitPolicy, *models.LimitPolicy, error) {
+	var rows []models.LimitPolicy
+	if err := r.db.Table("limit_policies").
+		Where("currency = ? AND (role = ? OR user_id = ?)", currency, role, userID).
+		Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	var roleDefault, override *models.LimitPolicy
+	for i := range rows {
+		if rows[i].UserID != nil {
+			override = &rows[i]
+		} else {
+			roleDefault = &rows[i]
+		}
+	}
+	return roleDefault, override, nil
+}
+
+// limitLockNamespace: kullanıcı başına giden işlem kilidi için pg_advisory_xact_lock ad alanı
+const limitLockNamespace = 7301
+
+// enforceLimits: giden işlemi limitlere karşı DB transaction'ı içinde kontrol eder.
+// Pencere toplamları kullanıcıya özel advisory lock altında okunur; aynı kullanıcının eşzamanlı
+// istekleri sıraya girer, böylece iki istek aynı boşluğu birlikte tüketemez.
+func enforceLimits(tx *gorm.DB, userID int, currency string, amount models.Money, transfer bool, lim *models.EffectiveLimit) error {
+	if lim == nil {
+		return nil
+	}
+	if lim.PerTransactionMax != nil && amount > *lim.PerTransactionMax {
+		return &models.LimitExceededError{Limit: models.LimitPerTransaction, Currency: currency, Max: lim.PerTransactionMax.String(), Current: amount.String()}
+	}
+	checkCount := transfer && lim.HourlyTransferMax != nil
+	if lim.DailyMax == nil && lim.MonthlyMax == nil && !checkCount {
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", limitLockNamespace, userID).Error; err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if lim.DailyMax != nil || lim.MonthlyMax != nil {
+		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		// iade edilen kısım kullanımdan düşülür
+		var used struct {
+			Daily   models.Money
+			Monthly models.Money
+		}
+		if err := tx.Raw(`SELECT COALESCE(SUM(amount - refunded_amount) FILTER (WHERE created_at >= ?), 0) AS daily,
+				COALESCE(SUM(amount - refunded_amount), 0) AS monthly
+			FROM transactions
+			WHERE from_user_id = ? AND currency = ? AND type IN ('debit', 'transfer', 'split', 'escrow_fund') AND created_at >= ?`,
+			dayStart, userID, currency, monthStart).Scan(&used).Error; err != nil {
+			return err
+		}
+		if lim.DailyMax != nil && used.Daily+amount > *lim.DailyMax {
+			return &models.LimitExceededError{Limit: models.LimitDaily, Currency: currency, Max: lim.DailyMax.String(), Current: used.Daily.String()}
+		}
+		if lim.MonthlyMax != nil && used.Monthly+amount > *lim.MonthlyMax {
+			return &models.LimitExceededError{Limit: models.LimitMonthly, Currency: currency, Max: lim.MonthlyMax.String(), Current: used.Monthly.String()}
+		}
+	}
+	if checkCount {
+		var n int64
+		if err := tx.Table("transactions").
+			Where("from_user_id = ? AND ((type = 'transfer' AND to_user_id <> from_user_id) OR type = 'split') AND created_at >= ?", userID, now.Add(-time.Hour)).
+			Count(&n).Error; err != nil {
+			return err
+		}
+		if n >= int64(*lim.HourlyTransferMax) {
+			return &models.LimitExceededError{Limit: models.LimitHourlyCount, Currency: currency, Max: strconv.Itoa(*lim.HourlyTransferMax), Current: strconv.FormatInt(n, 10)}
+		}
+	}
+	return nil
+}
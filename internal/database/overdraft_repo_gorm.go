@@ -0,0 +1,110 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SetCreditLine: cüzdanın kredi (overdraft) limitini ve opsiyonel faiz oranını ayarlar.
+// Limit kullanılan krediden küçük olabilir; bu durumda yeni çıkışlar reddedilir.
+func (r *gormBalanceRepository) SetCreditLine(userID int, currency string, limit models.Money, rateBps *int) (*models.Balance, error) {
+	var b models.Balance
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).Updates(map[string]interface{}{
+			"credit_limit":       limit,
+			"overdraft_rate_bps": rateBps,
+			"last_updated_at":    time.Now(),
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("balance not found")
+		}
+		return tx.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).First(&b).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListCreditWallets: kredi limiti tanımlı ya da negatif bakiyeli cüzdanlar; geçmiş günlerde eksiye düşmüş olabilecek
+// ama şu an pozitif olanlar da dahildir
+func (r *gormBalanceRepository) ListCreditWallets() ([]models.Balance, error) {
+	var items []models.Balance
+	err := r.db.Table("balances").Where("amount < 0 OR credit_limit > 0").Order("user_id, currency").Find(&items).Error
+	return items, err
+}
+
+// LastOverdraftAccrualDate: en son overdraft tahakkuku yazılan gün; hiç yoksa nil
+func (r *gormBalanceRepository) LastOverdraftAccrualDate() (*time.Time, error) {
+	var last *time.Time
+	err := r.db.Table("overdraft_accruals").Select("MAX(accrual_date)").Scan(&last).Error
+	return last, err
+}
+
+// AccrueOverdraftInterest: cüzdanın day günü (UTC) kapanış bakiyesi negatifse o gün için faiz tahakkuk ettirir.
+// Kapanış bakiyesi ledger'dan (checkpoint + gün sonuna kadarki kayıtlar) hesaplanır; çalıştırma anındaki bakiye kullanılmaz.
+// (user, currency, gün) benzersiz olduğundan tekrar çalıştırmalar ve paralel replikalar çift faiz yazmaz.
+// Tahakkuk yoksa (zaten yazılmış, bakiye pozitif ya da faiz sıfır) nil döner.
+func (r *gormBalanceRepository) AccrueOverdraftInterest(userID int, currency string, day time.Time, defaultRateBps int) (*models.OverdraftAccrual, error) {
+	var accrual *models.OverdraftAccrual
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var b models.Balance
+		if err := tx.Table("balances").Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ? AND currency = ?", userID, currency).First(&b).Error; err != nil {
+			return err
+		}
+		cur, err := models.LookupCurrency(currency)
+		if err != nil {
+			return err
+		}
+		closing, err := (&gormCheckpointRepository{db: tx}).BalanceAt(userID, currency, day.AddDate(0, 0, 1).Add(-time.Microsecond))
+		if err != nil {
+			return err
+		}
+		b.Amount = closing
+		interest := b.DailyOverdraftInterest(defaultRateBps, cur)
+		if interest.IsZero() {
+			return nil
+		}
+		rate := defaultRateBps
+		if b.OverdraftRateBps != nil {
+			rate = *b.OverdraftRateBps
+		}
+		a := &models.OverdraftAccrual{UserID: userID, Currency: currency, AccrualDate: day, Balance: b.Amount, RateBps: rate, Interest: interest}
+		res := tx.Table("overdraft_accruals").Clauses(clause.OnConflict{DoNothing: true}).Create(a)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		rec := &models.Transaction{FromUser: userID, ToUser: userID, Amount: interest, Currency: currency, Type: models.TxTypeOverdraftInterest, Status: "completed", CreatedAt: time.Now()}
+		if err := tx.Table("transactions").Create(rec).Error; err != nil {
+			return err
+		}
+		// faiz kredi limitini aşsa da yazılır (checkFunds=false)
+		if err := postEntries(tx, rec.ID, rec.CreatedAt, false,
+			models.UserLeg(userID, currency, interest.Neg()),
+			models.SystemLeg(models.AccountInterestIncome, currency, interest),
+		); err != nil {
+			return err
+		}
+		txID := rec.ID
+		a.TransactionID = &txID
+		if err := tx.Table("overdraft_accruals").
+			Where("user_id = ? AND currency = ? AND accrual_date = ?", userID, currency, day).
+			Update("transaction_id", txID).Error; err != nil {
+			return err
+		}
+		accrual = a
+		return nil
+	})
+	return accrual, err
+}
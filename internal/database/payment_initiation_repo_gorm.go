@@ -0,0 +1,102 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrDuplicatePaymentInitiation: gönderenin aynı MsgId'li dosyası daha önce alınmış
+var ErrDuplicatePaymentInitiation = errors.New("payment initiation already submitted")
+
+type gormPaymentInitiationRepository struct{ db *gorm.DB }
+
+func NewGormPaymentInitiationRepository(db *gorm.DB) PaymentInitiationRepository {
+	return &gormPaymentInitiationRepository{db: db}
+}
+
+// Create: talimatı ve kalemlerini tek DB transaction'ında yazar
+func (r *gormPaymentInitiationRepository) Create(p *models.PaymentInitiation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var n int64
+		if err := tx.Table("payment_initiations").Where("submitted_by = ? AND msg_id = ?", p.SubmittedBy, p.MsgID).Count(&n).Error; err != nil {
+			return err
+		}
+		// eşzamanlı yüklemede benzersiz indeks ikinci kaydı ayrıca reddeder
+		if n > 0 {
+			return ErrDuplicatePaymentInitiation
+		}
+		if err := tx.Table("payment_initiations").Create(p).Error; err != nil {
+			return err
+		}
+		if len(p.Txs) == 0 {
+			return nil
+		}
+		for i := range p.Txs {
+			p.Txs[i].InitiationID = p.ID
+		}
+		return tx.Table("payment_initiation_txs").CreateInBatches(p.Txs, 500).Error
+	})
+}
+
+// Get: talimat ve kalemleri (dosyadaki sırayla)
+func (r *gormPaymentInitiationRepository) Get(id int) (*models.PaymentInitiation, error) {
+	var p models.PaymentInitiation
+	if err := r.db.Table("payment_initiations").First(&p, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Table("payment_initiation_txs").Where("initiation_id = ?", id).Order("seq").Find(&p.Txs).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List: kalemler olmadan, en yeniden eskiye
+func (r *gormPaymentInitiationRepository) List(submittedBy, limit int) ([]models.PaymentInitiation, error) {
+	var items []models.PaymentInitiation
+	err := r.db.Table("payment_initiations").Where("submitted_by = ?", submittedBy).Order("id DESC").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// Processing: tamamlanmamış talimatların kimlikleri (yeniden başlatmada kuyruğa geri alınır)
+func (r *gormPaymentInitiationRepository) Processing() ([]int, error) {
+	var ids []int
+	err := r.db.Table("payment_initiations").Where("status = ?", models.PaymentInitiationProcessing).Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// SettleTx: bekleyen kalemi sonuçlandırır; kalem artık PDNG değilse (ör. başka bir replika işledi) hata döner
+func (r *gormPaymentInitiationRepository) SettleTx(id int, status, reasonCode, reason string, transactionID *int) error {
+	res := r.db.Table("payment_initiation_txs").Where("id = ? AND status = ?", id, models.PainStatusPending).Updates(map[string]interface{}{
+		"status":         status,
+		"reason_code":    reasonCode,
+		"reason":         reason,
+		"transaction_id": transactionID,
+		"updated_at":     time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("invalid payment initiation transaction state")
+	}
+	return nil
+}
+
+// Finish: kalem durumlarından sayaçları ve grup durumunu yazar; bekleyen kalem varsa talimat açık kalır
+func (r *gormPaymentInitiationRepository) Finish(p *models.PaymentInitiation) error {
+	p.Tally()
+	updates := map[string]interface{}{
+		"group_status":   p.GroupStatus,
+		"settled_count":  p.SettledCount,
+		"rejected_count": p.RejectedCount,
+	}
+	if p.SettledCount+p.RejectedCount == len(p.Txs) {
+		now := time.Now()
+		p.Status, p.CompletedAt = models.PaymentInitiationCompleted, &now
+		updates["status"], updates["completed_at"] = p.Status, now
+	}
+	return r.db.Table("payment_initiations").Where("id = ?", p.ID).Updates(updates).Error
+}
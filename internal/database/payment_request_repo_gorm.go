@@ -0,0 +1,95 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormPaymentRequestRepository struct{ db *gorm.DB }
+
+func NewGormPaymentRequestRepository(db *gorm.DB) PaymentRequestRepository {
+	return &gormPaymentRequestRepository{db: db}
+}
+
+func (r *gormPaymentRequestRepository) Create(pr *models.PaymentRequest) error {
+	return r.db.Table("payment_requests").Create(pr).Error
+}
+
+func (r *gormPaymentRequestRepository) Get(id int) (*models.PaymentRequest, error) {
+	var pr models.PaymentRequest
+	if err := r.db.Table("payment_requests").First(&pr, id).Error; err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// List: direction "incoming" kullanıcının ödeyeceği, "outgoing" kullanıcının istediği istekler; boşsa ikisi birden
+func (r *gormPaymentRequestRepository) List(userID int, direction, status string) ([]models.PaymentRequest, error) {
+	var items []models.PaymentRequest
+	q := r.db.Table("payment_requests")
+	switch direction {
+	case "incoming":
+		q = q.Where("payer_id = ?", userID)
+	case "outgoing":
+		q = q.Where("requester_id = ?", userID)
+	default:
+		q = q.Where("payer_id = ? OR requester_id = ?", userID, userID)
+	}
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("created_at DESC, id DESC").Find(&items).Error
+	return items, err
+}
+
+// Transition: isteği from durumlarından birindeyse to durumuna geçirir. Süresi dolmuş bekleyen istek
+// artık yanıtlanamaz; sonuç durumlarında responded_at, kabulde transaction_id yazılır.
+func (r *gormPaymentRequestRepository) Transition(id int, from []string, to string, txID *int, now time.Time) error {
+	updates := map[string]interface{}{"status": to, "updated_at": now}
+	if to != models.PaymentRequestPending && to != models.PaymentRequestAccepting {
+		updates["responded_at"] = now
+	}
+	if txID != nil {
+		updates["transaction_id"] = *txID
+	}
+	res := r.db.Table("payment_requests").
+		Where("id = ? AND status IN ?", id, from).
+		Where("status <> ? OR expires_at > ?", models.PaymentRequestPending, now).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("invalid payment request state")
+	}
+	return nil
+}
+
+// ExpireDue: süresi dolan bekleyen istekleri expired'a çeker ve çekilenleri döner (FOR UPDATE SKIP LOCKED)
+func (r *gormPaymentRequestRepository) ExpireDue(now time.Time, limit int) ([]models.PaymentRequest, error) {
+	var expired []models.PaymentRequest
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("payment_requests").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at <= ?", models.PaymentRequestPending, now).
+			Order("expires_at, id").Limit(limit).
+			Find(&expired).Error; err != nil {
+			return err
+		}
+		if len(expired) == 0 {
+			return nil
+		}
+		ids := make([]int, len(expired))
+		for i := range expired {
+			ids[i] = expired[i].ID
+			expired[i].Status, expired[i].RespondedAt = models.PaymentRequestExpired, &now
+		}
+		return tx.Table("payment_requests").Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": models.PaymentRequestExpired, "responded_at": now, "updated_at": now}).Error
+	})
+	return expired, err
+}
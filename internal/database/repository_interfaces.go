@@ -2,6 +2,7 @@ package database
 
 import (
 	"insider-go-backend/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,12 +18,17 @@ type UserRepository interface {
 	DeleteUser(id int) error
 }
 
-// BalanceRepository arayüzü
+// BalanceRepository arayüzü (cüzdan = (user_id, currency))
 type BalanceRepository interface {
-	GetBalanceByUserID(userID int) (*models.Balance, error)
-	UpdateBalance(userID int, amount float64) error
+	GetBalance(userID int, currency string) (*models.Balance, error)
+	GetBalancesByUserID(userID int) ([]models.Balance, error)
+	UpdateBalance(userID int, currency string, amount models.Money) error
 	CreateBalance(balance *models.Balance) error
-	AdjustBalance(userID int, delta float64) error
+	AdjustBalance(userID int, currency string, delta models.Money) error
+	SetCreditLine(userID int, currency string, limit models.Money, rateBps *int) (*models.Balance, error)
+	ListCreditWallets() ([]models.Balance, error)
+	LastOverdraftAccrualDate() (*time.Time, error)
+	AccrueOverdraftInterest(userID int, currency string, day time.Time, defaultRateBps int) (*models.OverdraftAccrual, error)
 }
 
 // TransactionRepository arayüzü
@@ -30,10 +36,197 @@ type TransactionRepository interface {
 	CreateTransaction(tx *models.Transaction) error
 	GetTransactionsByUser(userID int) ([]*models.Transaction, error)
 	GetTransactionByID(id int) (*models.Transaction, error)
+	GetTransactionByOrigin(origin models.TxOrigin) (*models.Transaction, error)
+	// Geçmiş: filtreli, keyset sayfalı liste (en fazla f.Limit+1 kayıt) ve bölünmüş ödeme payları
+	ListTransactionsByUser(userID int, f models.TxFilter) ([]*models.Transaction, error)
+	GetChildTransactions(parentIDs []int, txType string) ([]*models.Transaction, error)
+	// Metadata: sahibin düzenleyebildiği alanların güncellenmesi
+	UpdateTransactionMeta(t *models.Transaction) error
 	// Atomik para hareketleri
-	CreditAtomic(userID int, amount float64) (float64, *models.Transaction, error)
-	DebitAtomic(userID int, amount float64) (float64, *models.Transaction, error)
-	TransferAtomic(fromUserID, toUserID int, amount float64) (float64, float64, *models.Transaction, error)
+	CreditAtomic(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error)
+	DebitAtomic(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error)
+	TransferAtomic(fromUserID, toUserID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, models.Money, *models.Transaction, error)
+	// Çapraz kur transferi: kur ve iki tutar transaction kaydına snapshot olarak yazılır
+	TransferFXAtomic(fromUserID, toUserID int, quote models.FXQuote, opts models.TxOptions) (models.Money, models.Money, *models.Transaction, error)
+	// SplitAtomic: gönderenden tek seferde düşer, her alıcıya payını yazar; payların tutarları çözülmüş olmalıdır
+	SplitAtomic(fromUserID int, amount models.Money, currency string, parts []models.SplitPart, opts models.TxOptions) (models.Money, *models.Transaction, error)
+	ReverseAtomic(originalID int, amount models.Money, txType string) (reversal *models.Transaction, original *models.Transaction, err error)
+}
+
+// LedgerRepository arayüzü (çift taraflı kayıtlar; yazma işlemleri atomik repo yolları içinden yapılır)
+type LedgerRepository interface {
+	GetEntriesByTransaction(txID int) ([]models.LedgerEntry, error)
+	GetEntriesByAccount(account string) ([]models.LedgerEntry, error)
+	SumPostingsByCurrency(at time.Time) ([]models.CurrencyTotal, error)
+	AccountBalances(at time.Time) ([]models.AccountBalance, error)
+	// WalletDrift: balances satırları ile ledger toplamları arasındaki uyuşmazlıklar ve taranan cüzdan sayısı
+	WalletDrift() (wallets int64, mismatches []models.ReconcileItem, err error)
+	// CorrectDrift: cüzdanın güncel sapmasını ledger'a düzeltme kaydı olarak yazar (balances değişmez); sapma yoksa nil
+	CorrectDrift(userID int, currency string) (*models.ReconcileItem, error)
+	// TransactionDrift: ledger bacakları dengesiz, eksik ya da from/to/amount ile uyuşmayan işlemler ve taranan işlem sayısı
+	TransactionDrift() (transactions int64, mismatches []models.TxReconcileItem, err error)
+}
+
+// FXRateRepository arayüzü (kur tablosu, geçerlilik pencereleriyle)
+type FXRateRepository interface {
+	CreateRate(rate *models.FXRate) error
+	CreateRates(rates []models.FXRate) error
+	GetRateAt(base, quote string, at time.Time) (*models.FXRate, error)
+	ListRates(base, quote string, limit int) ([]models.FXRate, error)
+}
+
+// IdempotencyRepository arayüzü (Idempotency-Key kayıtları)
+type IdempotencyRepository interface {
+	Reserve(rec *models.IdempotencyKey) (existing *models.IdempotencyKey, created bool, err error)
+	Complete(userID int, key string, statusCode int, contentType, body string) error
+	Release(userID int, key string) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// HoldRepository arayüzü (authorization hold'ları)
+type HoldRepository interface {
+	CreateHold(hold *models.Hold, lim *models.EffectiveLimit) error
+	GetHold(id int) (*models.Hold, error)
+	ListHoldsByUser(userID int, status string) ([]models.Hold, error)
+	CaptureHold(id int, amount models.Money, lim *models.EffectiveLimit, fee *models.FeeQuote) (*models.Hold, *models.Transaction, error)
+	VoidHold(id int) (*models.Hold, error)
+	ExpireHolds(now time.Time, limit int) ([]models.Hold, error)
+}
+
+// ScheduledTransferRepository arayüzü (ileri tarihli transferler)
+type ScheduledTransferRepository interface {
+	Create(st *models.ScheduledTransfer) error
+	Get(id int) (*models.ScheduledTransfer, error)
+	ListByUser(userID int, status string) ([]models.ScheduledTransfer, error)
+	UpdatePending(st *models.ScheduledTransfer) error
+	Cancel(id, userID int) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.ScheduledTransfer, error)
+	Finish(st *models.ScheduledTransfer, run *models.ScheduledTransferRun) error
+	ListRuns(id int) ([]models.ScheduledTransferRun, error)
+}
+
+// StandingOrderRepository arayüzü (düzenli transfer talimatları)
+type StandingOrderRepository interface {
+	Create(o *models.StandingOrder) error
+	Get(id int) (*models.StandingOrder, error)
+	ListByUser(userID int, status string) ([]models.StandingOrder, error)
+	Transition(id, userID int, from []string, to string, nextRunAt *time.Time) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.StandingOrder, error)
+	Finish(o *models.StandingOrder, run *models.StandingOrderRun) error
+	ListRuns(id int) ([]models.StandingOrderRun, error)
+}
+
+// LimitRepository arayüzü (giden işlem limitleri)
+type LimitRepository interface {
+	ListPolicies(role string, userID int) ([]models.LimitPolicy, error)
+	GetPolicy(id int) (*models.LimitPolicy, error)
+	UpsertPolicy(p *models.LimitPolicy) error
+	DeletePolicy(id int) error
+	FindPolicies(role string, userID int, currency string) (roleDefault *models.LimitPolicy, override *models.LimitPolicy, err error)
+}
+
+// FeeRepository arayüzü (işlem ücret tarifeleri)
+type FeeRepository interface {
+	ListSchedules(txType, currency string, at *time.Time) ([]models.FeeSchedule, error)
+	GetSchedule(id int) (*models.FeeSchedule, error)
+	CreateSchedule(f *models.FeeSchedule) error
+	UpdateSchedule(f *models.FeeSchedule) error
+	DeleteSchedule(id int) error
+	// FindActive: at anında geçerli, işlem tipi ve para birimine uyan tarifeler
+	FindActive(txType, currency string, at time.Time) ([]models.FeeSchedule, error)
+}
+
+// CheckpointRepository arayüzü (bakiye checkpoint'leri)
+type CheckpointRepository interface {
+	// WriteCheckpoints: tüm cüzdanlar için asOf anındaki kapanış bakiyesini yazar; mevcut checkpoint'ler korunur
+	WriteCheckpoints(asOf time.Time) (int64, error)
+	// BalanceAt: en yakın checkpoint + sonraki ledger kayıtlarının toplamı (created_at <= at)
+	BalanceAt(userID int, currency string, at time.Time) (models.Money, error)
+	LatestCheckpoint(userID int, currency string, before time.Time) (*models.BalanceCheckpoint, error)
+}
+
+// BatchRepository arayüzü (toplu işlemler)
+type BatchRepository interface {
+	// CreateBatch: batch'i ve kalemlerini (pending) yazar
+	CreateBatch(b *models.Batch) error
+	// RunAllOrNothing: tüm kalemleri tek DB transaction'ında uygular; biri başarısızsa hepsi geri alınır
+	// ve başarısız kalemin hatası döner (kalem durumları yine kaydedilir)
+	RunAllOrNothing(b *models.Batch, opts []models.TxOptions) error
+	// RunItem: tek kalemi kendi DB transaction'ında uygular ve sonucunu kalemle birlikte yazar; kalem hatasını döner
+	RunItem(b *models.Batch, i int, opts models.TxOptions) error
+	// SaveItems: çalıştırılmadan sonuçlanan kalemlerin durumlarını yazar
+	SaveItems(b *models.Batch) error
+	FinishBatch(b *models.Batch) error
+	GetBatch(id int) (*models.Batch, error)
+	ListBatches(userID int, limit int) ([]models.Batch, error)
+}
+
+// PaymentRequestRepository arayüzü (kullanıcılar arası ödeme istekleri)
+type PaymentRequestRepository interface {
+	Create(pr *models.PaymentRequest) error
+	Get(id int) (*models.PaymentRequest, error)
+	List(userID int, direction, status string) ([]models.PaymentRequest, error)
+	Transition(id int, from []string, to string, txID *int, now time.Time) error
+	ExpireDue(now time.Time, limit int) ([]models.PaymentRequest, error)
+}
+
+// EscrowRepository arayüzü (koşullu ödeme (escrow))
+type EscrowRepository interface {
+	Fund(e *models.Escrow, opts models.TxOptions) (*models.Transaction, error)
+	Get(id int) (*models.Escrow, error)
+	List(userID int, side, status string) ([]models.Escrow, error)
+	Decide(id, userID int, outcome string, now time.Time) (*models.Escrow, error)
+	Settle(id int, outcome, resolution string, actorID *int, now time.Time) (*models.Escrow, error)
+	DueForTimeout(now time.Time, limit int) ([]int, error)
+}
+
+// InterestRepository arayüzü (mevduat faizi tarifeleri, tahakkuk ve ödeme)
+type InterestRepository interface {
+	ListRates(currency string, at *time.Time) ([]models.InterestRate, error)
+	GetRate(id int) (*models.InterestRate, error)
+	CreateRate(r *models.InterestRate) error
+	UpdateRate(r *models.InterestRate) error
+	DeleteRate(id int) error
+	ListWallets(userID int, currency string) ([]models.Balance, error)
+	ClosingBalances(userID int, currency string, from, to time.Time) ([]models.WalletDay, error)
+	InsertAccrual(a *models.InterestAccrual) (bool, error)
+	LastAccrualDate() (*time.Time, error)
+	UnpostedPeriods(before time.Time) ([]models.InterestPeriod, error)
+	PostInterest(p models.InterestPeriod) (*models.Transaction, models.Money, error)
+}
+
+// StatementRepository arayüzü (dönem ekstreleri)
+type StatementRepository interface {
+	Lines(userID int, currency string, start, end time.Time) ([]models.StatementLine, error)
+	StreamLines(userID int, currency string, start, end time.Time, fn func(models.ExportLine) error) error
+	Create(s *models.Statement) (bool, error)
+	Get(id int) (*models.Statement, error)
+	GetByPeriod(userID int, currency string, start time.Time) (*models.Statement, error)
+	List(userID int, currency string) ([]models.Statement, error)
+	MissingWallets(start, end time.Time) ([]models.Balance, error)
+}
+
+// BankStatementRepository arayüzü (içe aktarılan banka ekstreleri ve mutabakat kalemleri)
+type BankStatementRepository interface {
+	Create(s *models.BankStatement, lines []models.BankStatementLine) error
+	Get(id int) (*models.BankStatement, error)
+	List(limit int) ([]models.BankStatement, error)
+	Lines(statementID int, status string) ([]models.BankStatementLine, error)
+	GetLine(id int) (*models.BankStatementLine, error)
+	TransitionLine(id int, from []string, to string, fields map[string]interface{}) error
+	CreditCandidates(currency string, amount models.Money, from, to time.Time) ([]models.Transaction, error)
+	DuplicateLine(l models.BankStatementLine) (*models.BankStatementLine, error)
+	Totals(statementID int) ([]models.BankReportTotal, error)
+}
+
+// PaymentInitiationRepository arayüzü (pain.001 toplu ödeme talimatları ve kalem durumları)
+type PaymentInitiationRepository interface {
+	Create(p *models.PaymentInitiation) error
+	Get(id int) (*models.PaymentInitiation, error)
+	List(submittedBy, limit int) ([]models.PaymentInitiation, error)
+	Processing() ([]int, error)
+	SettleTx(id int, status, reasonCode, reason string, transactionID *int) error
+	Finish(p *models.PaymentInitiation) error
 }
 
 // AuditLogRepository arayüzü
@@ -45,10 +238,26 @@ type AuditLogRepository interface {
 
 // Varsayılan repo örnekleri
 var (
-	defaultUserRepo        UserRepository
-	defaultBalanceRepo     BalanceRepository
-	defaultTransactionRepo TransactionRepository
-	defaultAuditLogRepo    AuditLogRepository
+	defaultUserRepo              UserRepository
+	defaultBalanceRepo           BalanceRepository
+	defaultTransactionRepo       TransactionRepository
+	defaultAuditLogRepo          AuditLogRepository
+	defaultLedgerRepo            LedgerRepository
+	defaultFXRateRepo            FXRateRepository
+	defaultIdempotencyRepo       IdempotencyRepository
+	defaultHoldRepo              HoldRepository
+	defaultScheduledTransferRepo ScheduledTransferRepository
+	defaultStandingOrderRepo     StandingOrderRepository
+	defaultLimitRepo             LimitRepository
+	defaultFeeRepo               FeeRepository
+	defaultCheckpointRepo        CheckpointRepository
+	defaultBatchRepo             BatchRepository
+	defaultPaymentRequestRepo    PaymentRequestRepository
+	defaultEscrowRepo            EscrowRepository
+	defaultInterestRepo          InterestRepository
+	defaultStatementRepo         StatementRepository
+	defaultBankStatementRepo     BankStatementRepository
+	defaultPaymentInitiationRepo PaymentInitiationRepository
 )
 
 // InitDefaultRepos: uygulama başlangıcında çağrılmalı
@@ -57,16 +266,64 @@ func InitDefaultRepos(db *gorm.DB) {
 	defaultBalanceRepo = NewGormBalanceRepository(db)
 	defaultTransactionRepo = NewGormTransactionRepository(db)
 	defaultAuditLogRepo = NewGormAuditLogRepository(db)
+	defaultLedgerRepo = NewGormLedgerRepository(db)
+	defaultFXRateRepo = NewGormFXRateRepository(db)
+	defaultIdempotencyRepo = NewGormIdempotencyRepository(db)
+	defaultHoldRepo = NewGormHoldRepository(db)
+	defaultScheduledTransferRepo = NewGormScheduledTransferRepository(db)
+	defaultStandingOrderRepo = NewGormStandingOrderRepository(db)
+	defaultLimitRepo = NewGormLimitRepository(db)
+	defaultFeeRepo = NewGormFeeRepository(db)
+	defaultCheckpointRepo = NewGormCheckpointRepository(db)
+	defaultBatchRepo = NewGormBatchRepository(db)
+	defaultPaymentRequestRepo = NewGormPaymentRequestRepository(db)
+	defaultEscrowRepo = NewGormEscrowRepository(db)
+	defaultInterestRepo = NewGormInterestRepository(db)
+	defaultStatementRepo = NewGormStatementRepository(db)
+	defaultBankStatementRepo = NewGormBankStatementRepository(db)
+	defaultPaymentInitiationRepo = NewGormPaymentInitiationRepository(db)
 }
 
 // Getter'lar
-func UserRepo() UserRepository               { return defaultUserRepo }
-func BalanceRepo() BalanceRepository         { return defaultBalanceRepo }
-func TransactionRepo() TransactionRepository { return defaultTransactionRepo }
-func AuditLogRepo() AuditLogRepository       { return defaultAuditLogRepo }
+func UserRepo() UserRepository                           { return defaultUserRepo }
+func BalanceRepo() BalanceRepository                     { return defaultBalanceRepo }
+func TransactionRepo() TransactionRepository             { return defaultTransactionRepo }
+func AuditLogRepo() AuditLogRepository                   { return defaultAuditLogRepo }
+func LedgerRepo() LedgerRepository                       { return defaultLedgerRepo }
+func FXRateRepo() FXRateRepository                       { return defaultFXRateRepo }
+func IdempotencyRepo() IdempotencyRepository             { return defaultIdempotencyRepo }
+func HoldRepo() HoldRepository                           { return defaultHoldRepo }
+func ScheduledTransferRepo() ScheduledTransferRepository { return defaultScheduledTransferRepo }
+func StandingOrderRepo() StandingOrderRepository         { return defaultStandingOrderRepo }
+func LimitRepo() LimitRepository                         { return defaultLimitRepo }
+func FeeRepo() FeeRepository                             { return defaultFeeRepo }
+func CheckpointRepo() CheckpointRepository               { return defaultCheckpointRepo }
+func BatchRepo() BatchRepository                         { return defaultBatchRepo }
+func PaymentRequestRepo() PaymentRequestRepository       { return defaultPaymentRequestRepo }
+func EscrowRepo() EscrowRepository                       { return defaultEscrowRepo }
+func InterestRepo() InterestRepository                   { return defaultInterestRepo }
+func StatementRepo() StatementRepository                 { return defaultStatementRepo }
+func BankStatementRepo() BankStatementRepository         { return defaultBankStatementRepo }
+func PaymentInitiationRepo() PaymentInitiationRepository { return defaultPaymentInitiationRepo }
 
 // Setters (test veya özel implementasyonlar için)
-func SetUserRepo(r UserRepository)               { defaultUserRepo = r }
-func SetBalanceRepo(r BalanceRepository)         { defaultBalanceRepo = r }
-func SetTransactionRepo(r TransactionRepository) { defaultTransactionRepo = r }
-func SetAuditLogRepo(r AuditLogRepository)       { defaultAuditLogRepo = r }
+func SetUserRepo(r UserRepository)                           { defaultUserRepo = r }
+func SetBalanceRepo(r BalanceRepository)                     { defaultBalanceRepo = r }
+func SetTransactionRepo(r TransactionRepository)             { defaultTransactionRepo = r }
+func SetAuditLogRepo(r AuditLogRepository)                   { defaultAuditLogRepo = r }
+func SetLedgerRepo(r LedgerRepository)                       { defaultLedgerRepo = r }
+func SetFXRateRepo(r FXRateRepository)                       { defaultFXRateRepo = r }
+func SetIdempotencyRepo(r IdempotencyRepository)             { defaultIdempotencyRepo = r }
+func SetHoldRepo(r HoldRepository)                           { defaultHoldRepo = r }
+func SetScheduledTransferRepo(r ScheduledTransferRepository) { defaultScheduledTransferRepo = r }
+func SetStandingOrderRepo(r StandingOrderRepository)         { defaultStandingOrderRepo = r }
+func SetLimitRepo(r LimitRepository)                         { defaultLimitRepo = r }
+func SetFeeRepo(r FeeRepository)                             { defaultFeeRepo = r }
+func SetCheckpointRepo(r CheckpointRepository)               { defaultCheckpointRepo = r }
+func SetBatchRepo(r BatchRepository)                         { defaultBatchRepo = r }
+func SetPaymentRequestRepo(r PaymentRequestRepository)       { defaultPaymentRequestRepo = r }
+func SetEscrowRepo(r EscrowRepository)                       { defaultEscrowRepo = r }
+func SetInterestRepo(r InterestRepository)                   { defaultInterestRepo = r }
+func SetStatementRepo(r StatementRepository)                 { defaultStatementRepo = r }
+func SetBankStatementRepo(r BankStatementRepository)         { defaultBankStatementRepo = r }
+func SetPaymentInitiationRepo(r PaymentInitiationRepository) { defaultPaymentInitiationRepo = r }
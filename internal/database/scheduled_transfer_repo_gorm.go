@@ -0,0 +1,146 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLeaseLost: kaydın lease'i dolmuş ve başka bir replika tarafından yeniden sahiplenilmiş
+var ErrLeaseLost = errors.New("lease lost")
+
+type gormScheduledTransferRepository struct{ db *gorm.DB }
+
+func NewGormScheduledTransferRepository(db *gorm.DB) ScheduledTransferRepository {
+	return &gormScheduledTransferRepository{db: db}
+}
+
+func (r *gormScheduledTransferRepository) Create(st *models.ScheduledTransfer) error {
+	return r.db.Table("scheduled_transfers").Create(st).Error
+}
+
+func (r *gormScheduledTransferRepository) Get(id int) (*models.ScheduledTransfer, error) {
+	var st models.ScheduledTransfer
+	if err := r.db.Table("scheduled_transfers").First(&st, id).Error; err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (r *gormScheduledTransferRepository) ListByUser(userID int, status string) ([]models.ScheduledTransfer, error) {
+	var items []models.ScheduledTransfer
+	q := r.db.Table("scheduled_transfers").Where("user_id = ?", userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("execute_at, id").Find(&items).Error
+	return items, err
+}
+
+// UpdatePending: yalnızca henüz işlenmemiş (pending) kaydı günceller
+func (r *gormScheduledTransferRepository) UpdatePending(st *models.ScheduledTransfer) error {
+	res := r.db.Table("scheduled_transfers").
+		Where("id = ? AND user_id = ? AND status = ?", st.ID, st.UserID, models.ScheduledPending).
+		Updates(map[string]interface{}{
+			"to_user_id":  st.ToUserID,
+			"amount":      st.Amount,
+			"currency":    st.Currency,
+			"execute_at":  st.ExecuteAt,
+			"next_run_at": st.NextRunAt,
+			"updated_at":  time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("scheduled transfer not pending")
+	}
+	return nil
+}
+
+func (r *gormScheduledTransferRepository) Cancel(id, userID int) error {
+	res := r.db.Table("scheduled_transfers").
+		Where("id = ? AND user_id = ? AND status = ?", id, userID, models.ScheduledPending).
+		Updates(map[string]interface{}{"status": models.ScheduledCancelled, "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("scheduled transfer not pending")
+	}
+	return nil
+}
+
+// ClaimDue: vadesi gelen kayıtları "processing" olarak işaretleyip lease süresiyle sahiplenir.
+// FOR UPDATE SKIP LOCKED sayesinde birden fazla replika aynı kaydı almaz; lease'i dolan kayıtlar yeniden alınabilir.
+func (r *gormScheduledTransferRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.ScheduledTransfer, error) {
+	var claimed []models.ScheduledTransfer
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.ScheduledTransfer
+		if err := tx.Table("scheduled_transfers").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND next_run_at <= ?) OR (status = ? AND locked_until < ?)", models.ScheduledPending, now, models.ScheduledProcessing, now).
+			Order("next_run_at, id").Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]int, len(due))
+		for i := range due {
+			ids[i] = due[i].ID
+		}
+		// Finish lease'i değerle karşılaştırır; DB'nin mikrosaniye hassasiyetine yuvarlanır
+		until := now.Add(lease).Truncate(time.Microsecond)
+		if err := tx.Table("scheduled_transfers").Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       models.ScheduledProcessing,
+			"locked_until": until,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"updated_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+		for i := range due {
+			due[i].Status, due[i].LockedUntil = models.ScheduledProcessing, &until
+			due[i].Attempts++
+		}
+		claimed = due
+		return nil
+	})
+	return claimed, err
+}
+
+// Finish: çalıştırma sonucunu yazar ve kaydın yeni durumunu kaydeder (tek DB transaction'ı).
+// Yalnızca ClaimDue'da alınan lease hâlâ geçerliyse yazar; kayıt başka replikaya geçtiyse ErrLeaseLost döner.
+func (r *gormScheduledTransferRepository) Finish(st *models.ScheduledTransfer, run *models.ScheduledTransferRun) error {
+	if st.LockedUntil == nil {
+		return ErrLeaseLost
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("scheduled_transfers").Where("id = ? AND locked_until = ?", st.ID, *st.LockedUntil).Updates(map[string]interface{}{
+			"status":         st.Status,
+			"next_run_at":    st.NextRunAt,
+			"last_error":     st.LastError,
+			"transaction_id": st.TransactionID,
+			"locked_until":   nil,
+			"updated_at":     time.Now(),
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLeaseLost
+		}
+		return tx.Table("scheduled_transfer_runs").Create(run).Error
+	})
+}
+
+func (r *gormScheduledTransferRepository) ListRuns(id int) ([]models.ScheduledTransferRun, error) {
+	var runs []models.ScheduledTransferRun
+	err := r.db.Table("scheduled_transfer_runs").Where("scheduled_transfer_id = ?", id).Order("executed_at, id").Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormStandingOrderRepository struct{ db *gorm.DB }
+
+func NewGormStandingOrderRepository(db *gorm.DB) StandingOrderRepository {
+	return &gormStandingOrderRepository{db: db}
+}
+
+func (r *gormStandingOrderRepository) Create(o *models.StandingOrder) error {
+	return r.db.Table("standing_orders").Create(o).Error
+}
+
+func (r *gormStandingOrderRepository) Get(id int) (*models.StandingOrder, error) {
+	var o models.StandingOrder
+	if err := r.db.Table("standing_orders").First(&o, id).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *gormStandingOrderRepository) ListByUser(userID int, status string) ([]models.StandingOrder, error) {
+	var items []models.StandingOrder
+	q := r.db.Table("standing_orders").Where("user_id = ?", userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Order("created_at DESC, id DESC").Find(&items).Error
+	return items, err
+}
+
+// Transition: talimatı from durumlarından birindeyse to durumuna geçirir (pause/resume/cancel).
+// O an çalışan occurrence tamamlanır; Finish yeni durumu ezmez.
+func (r *gormStandingOrderRepository) Transition(id, userID int, from []string, to string, nextRunAt *time.Time) error {
+	updates := map[string]interface{}{"status": to, "updated_at": time.Now()}
+	if nextRunAt != nil {
+		updates["next_run_at"] = *nextRunAt
+	}
+	res := r.db.Table("standing_orders").Where("id = ? AND user_id = ? AND status IN ?", id, userID, from).Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("invalid standing order state")
+	}
+	return nil
+}
+
+// ClaimDue: vadesi gelen aktif talimatları lease süresiyle sahiplenir (FOR UPDATE SKIP LOCKED).
+// Lease'i dolan talimat (çöken replika) tekrar alınabilir; occurrence seq'i origin ile korunduğu için çift transfer olmaz.
+func (r *gormStandingOrderRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.StandingOrder, error) {
+	var claimed []models.StandingOrder
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var due []models.StandingOrder
+		if err := tx.Table("standing_orders").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?)", models.StandingOrderActive, now, now).
+			Order("next_run_at, id").Limit(limit).
+			Find(&due).Error; err != nil {
+			return err
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		ids := make([]int, len(due))
+		for i := range due {
+			ids[i] = due[i].ID
+		}
+		// Finish lease'i değerle karşılaştırır; DB'nin mikrosaniye hassasiyetine yuvarlanır
+		until := now.Add(lease).Truncate(time.Microsecond)
+		if err := tx.Table("standing_orders").Where("id IN ?", ids).Updates(map[string]interface{}{"locked_until": until}).Error; err != nil {
+			return err
+		}
+		for i := range due {
+			due[i].LockedUntil = &until
+		}
+		claimed = due
+		return nil
+	})
+	return claimed, err
+}
+
+// Finish: occurrence sonucunu yazar, sayacı ve bir sonraki çalıştırmayı günceller; lease'i bırakır.
+// Talimat bu arada duraklatıldı/iptal edildiyse durumu korunur. Lease başka replikaya geçtiyse ErrLeaseLost döner.
+func (r *gormStandingOrderRepository) Finish(o *models.StandingOrder, run *models.StandingOrderRun) error {
+	if o.LockedUntil == nil {
+		return ErrLeaseLost
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"occurrences":  o.Occurrences,
+			"next_run_at":  o.NextRunAt,
+			"last_error":   o.LastError,
+			"locked_until": nil,
+			"updated_at":   time.Now(),
+		}
+		if o.Status == models.StandingOrderCompleted {
+			updates["status"] = gorm.Expr("CASE WHEN status = ? THEN status ELSE ? END", models.StandingOrderCancelled, models.StandingOrderCompleted)
+		}
+		res := tx.Table("standing_orders").Where("id = ? AND locked_until = ?", o.ID, *o.LockedUntil).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLeaseLost
+		}
+		return tx.Table("standing_order_runs").Create(run).Error
+	})
+}
+
+func (r *gormStandingOrderRepository) ListRuns(id int) ([]models.StandingOrderRun, error) {
+	var runs []models.StandingOrderRun
+	err := r.db.Table("standing_order_runs").Where("standing_order_id = ?", id).Order("seq, id").Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,100 @@
+package database
+
+import (
+	"insider-go-backend/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormStatementRepository struct{ db *gorm.DB }
+
+func NewGormStatementRepository(db *gorm.DB) StatementRepository {
+	return &gormStatementRepository{db: db}
+}
+
+// Lines: cüzdanın [start, end) aralığındaki ledger kayıtlarını işlem başına toplar.
+// Karşı taraf, işlemin kullanıcı olmayan ucudur (kendi cüzdanı ya da house hesabıyla yapılan işlemlerde boş).
+func (r *gormStatementRepository) Lines(userID int, currency string, start, end time.Time) ([]models.StatementLine, error) {
+	var lines []models.StatementLine
+	err := r.db.Raw(`SELECT le.transaction_id, MIN(le.created_at) AS posted_at, t.type,
+			CASE WHEN t.from_user_id <> ? THEN t.from_user_id WHEN t.to_user_id <> ? THEN t.to_user_id END AS counterparty_id,
+			SUM(le.amount) AS amount
+		FROM ledger_entries le
+		JOIN transactions t ON t.id = le.transaction_id
+		WHERE le.user_id = ? AND le.currency = ? AND le.created_at >= ? AND le.created_at < ?
+		GROUP BY le.transaction_id, t.type, t.from_user_id, t.to_user_id
+		ORDER BY posted_at, le.transaction_id`, userID, userID, userID, currency, start, end).Scan(&lines).Error
+	return lines, err
+}
+
+// StreamLines: Lines ile aynı gruplamayı memo ve external_ref ile birlikte satır satır okur; sonuç belleğe alınmaz.
+// fn hata dönerse okuma durur ve hata iletilir.
+func (r *gormStatementRepository) StreamLines(userID int, currency string, start, end time.Time, fn func(models.ExportLine) error) error {
+	rows, err := r.db.Raw(`SELECT le.transaction_id, MIN(le.created_at) AS posted_at, t.type,
+			CASE WHEN t.from_user_id <> ? THEN t.from_user_id WHEN t.to_user_id <> ? THEN t.to_user_id END AS counterparty_id,
+			SUM(le.amount) AS amount, t.memo, t.external_ref
+		FROM ledger_entries le
+		JOIN transactions t ON t.id = le.transaction_id
+		WHERE le.user_id = ? AND le.currency = ? AND le.created_at >= ? AND le.created_at < ?
+		GROUP BY le.transaction_id, t.type, t.from_user_id, t.to_user_id, t.memo, t.external_ref
+		ORDER BY posted_at, le.transaction_id`, userID, userID, userID, currency, start, end).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var l models.ExportLine
+		if err := r.db.ScanRows(rows, &l); err != nil {
+			return err
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Create: ekstreyi yazar; aynı cüzdan ve dönem için zaten varsa dokunmaz ve false döner
+func (r *gormStatementRepository) Create(s *models.Statement) (bool, error) {
+	res := r.db.Table("statements").Clauses(clause.OnConflict{DoNothing: true}).Create(s)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *gormStatementRepository) Get(id int) (*models.Statement, error) {
+	var s models.Statement
+	if err := r.db.Table("statements").First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *gormStatementRepository) GetByPeriod(userID int, currency string, start time.Time) (*models.Statement, error) {
+	var s models.Statement
+	if err := r.db.Table("statements").Where("user_id = ? AND currency = ? AND period_start = ?", userID, currency, start).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List: ekstre listesi (içerik olmadan); currency boşsa tüm cüzdanlar
+func (r *gormStatementRepository) List(userID int, currency string) ([]models.Statement, error) {
+	var items []models.Statement
+	q := r.db.Table("statements").Omit("content").Where("user_id = ?", userID)
+	if currency != "" {
+		q = q.Where("currency = ?", currency)
+	}
+	err := q.Order("period_start DESC, currency").Find(&items).Error
+	return items, err
+}
+
+// MissingWallets: dönem sonundan önce hareketi olan ama start dönemi için ekstresi olmayan cüzdanlar
+func (r *gormStatementRepository) MissingWallets(start, end time.Time) ([]models.Balance, error) {
+	var items []models.Balance
+	err := r.db.Raw(`SELECT b.user_id, b.currency FROM balances b
+		WHERE EXISTS (SELECT 1 FROM ledger_entries le WHERE le.user_id = b.user_id AND le.currency = b.currency AND le.created_at < ?)
+		AND NOT EXISTS (SELECT 1 FROM statements s WHERE s.user_id = b.user_id AND s.currency = b.currency AND s.period_start = ?)
+		ORDER BY b.user_id, b.currency`, end, start).Scan(&items).Error
+	return items, err
+}
@@ -6,8 +6,15 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrDuplicateOrigin: aynı kaynak (origin) için işlem zaten yazılmış
+var ErrDuplicateOrigin = errors.New("transaction already exists for origin")
+
+// ErrDuplicateExternalRef: kullanıcının external_ref'i başka bir işlemde kullanılmış
+var ErrDuplicateExternalRef = errors.New("external_ref already used")
+
 type gormTransactionRepository struct{ db *gorm.DB }
 
 func NewGormTransactionRepository(db *gorm.DB) TransactionRepository {
@@ -26,6 +33,108 @@ func (r *gormTransactionRepository) GetTransactionsByUser(userID int) ([]*models
 	return transactions, err
 }
 
+// ListTransactionsByUser: kullanıcının işlem geçmişinden filtreye uyan en fazla f.Limit+1 kaydı (created_at, id) sırasıyla döner;
+// fazladan kayıt sonraki sayfanın varlığını gösterir. Gönderenin bölünmüş ödeme payları ebeveyn üzerinden listelenir.
+func (r *gormTransactionRepository) ListTransactionsByUser(userID int, f models.TxFilter) ([]*models.Transaction, error) {
+	order := "created_at DESC, id DESC"
+	if f.Ascending {
+		order = "created_at ASC, id ASC"
+	}
+	limit := f.Limit + 1
+	// OR yerine iki dal: her dal kendi (user, created_at, id) indeksinden sıralı ve sınırlı okunur
+	sent := r.historyBranch(userID, f).Where("from_user_id = ? AND type <> ?", userID, models.TxTypeSplitPart)
+	if f.Counterparty != 0 {
+		sent = sent.Where("(to_user_id = ? OR (type = ? AND EXISTS (SELECT 1 FROM transactions p WHERE p.parent_id = transactions.id AND p.type = ? AND p.to_user_id = ?)))",
+			f.Counterparty, models.TxTypeSplit, models.TxTypeSplitPart, f.Counterparty)
+	}
+	sent = sent.Order(order).Limit(limit)
+	var out []*models.Transaction
+	// external_ref yalnızca işlemi başlatanın kayıtlarında aranır
+	if f.ExternalRef != "" {
+		err := sent.Find(&out).Error
+		return out, err
+	}
+	received := r.historyBranch(userID, f).Where("to_user_id = ? AND from_user_id <> ?", userID, userID)
+	if f.Counterparty != 0 {
+		received = received.Where("from_user_id = ?", f.Counterparty)
+	}
+	received = received.Order(order).Limit(limit)
+	err := r.db.Table("((?) UNION ALL (?)) AS t", sent, received).Order(order).Limit(limit).Find(&out).Error
+	return out, err
+}
+
+// historyBranch: iki dalda ortak filtreler ve keyset koşulu
+func (r *gormTransactionRepository) historyBranch(userID int, f models.TxFilter) *gorm.DB {
+	q := r.db.Table("transactions")
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+	if len(f.Types) > 0 {
+		q = q.Where("type IN ?", f.Types)
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.MinAmount != nil {
+		q = q.Where("amount >= ?", *f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		q = q.Where("amount <= ?", *f.MaxAmount)
+	}
+	if f.Category != "" {
+		q = q.Where("category = ?", f.Category)
+	}
+	if f.Tag != "" {
+		// GIN indeksli jsonb içerme sorgusu
+		q = q.Where("tags @> ?::jsonb", models.Tags{f.Tag})
+	}
+	if f.ExternalRef != "" {
+		q = q.Where("external_ref = ?", f.ExternalRef)
+	}
+	if f.Query != "" {
+		q = q.Where("to_tsvector('simple', memo) @@ plainto_tsquery('simple', ?)", f.Query)
+	}
+	if f.Direction != "" {
+		// yön kullanıcının ledger bacaklarının net işaretidir; bölünmüş ödeme payının bacakları ebeveyndedir
+		op := ">"
+		if f.Direction == models.TxDirectionOut {
+			op = "<"
+		}
+		q = q.Where("(SELECT COALESCE(SUM(le.amount), 0) FROM ledger_entries le WHERE le.transaction_id = CASE WHEN transactions.type = ? THEN transactions.parent_id ELSE transactions.id END AND le.user_id = ?) "+op+" 0",
+			models.TxTypeSplitPart, userID)
+	}
+	if c := f.Cursor; c != nil {
+		if f.Ascending {
+			q = q.Where("(created_at, id) > (?, ?)", c.CreatedAt, c.ID)
+		} else {
+			q = q.Where("(created_at, id) < (?, ?)", c.CreatedAt, c.ID)
+		}
+	}
+	return q
+}
+
+// GetChildTransactions: verilen ebeveynlere bağlı, belirtilen türdeki alt işlemler
+func (r *gormTransactionRepository) GetChildTransactions(parentIDs []int, txType string) ([]*models.Transaction, error) {
+	var children []*models.Transaction
+	if len(parentIDs) == 0 {
+		return children, nil
+	}
+	err := r.db.Table("transactions").Where("parent_id IN ? AND type = ?", parentIDs, txType).Order("id").Find(&children).Error
+	return children, err
+}
+
+// UpdateTransactionMeta: yalnızca memo, kategori ve etiketleri günceller; tutar ve durum alanlarına dokunmaz
+func (r *gormTransactionRepository) UpdateTransactionMeta(t *models.Transaction) error {
+	return r.db.Table("transactions").Where("id = ?", t.ID).Updates(map[string]interface{}{
+		"memo":     t.Memo,
+		"category": t.Category,
+		"tags":     t.Tags,
+	}).Error
+}
+
 func (r *gormTransactionRepository) GetTransactionByID(id int) (*models.Transaction, error) {
 	var tx models.Transaction
 	if err := r.db.Table("transactions").First(&tx, id).Error; err != nil {
@@ -34,109 +143,389 @@ func (r *gormTransactionRepository) GetTransactionByID(id int) (*models.Transact
 	return &tx, nil
 }
 
-func (r *gormTransactionRepository) CreditAtomic(userID int, amount float64) (float64, *models.Transaction, error) {
-	var newAmount float64
-	rec := &models.Transaction{}
+// GetTransactionByOrigin: kaynağın (type, id, seq) ürettiği işlem
+func (r *gormTransactionRepository) GetTransactionByOrigin(origin models.TxOrigin) (*models.Transaction, error) {
+	var tx models.Transaction
+	if err := r.db.Table("transactions").Where("origin_type = ? AND origin_id = ? AND origin_seq = ?", origin.Type, origin.ID, origin.Seq).First(&tx).Error; err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func (r *gormTransactionRepository) CreditAtomic(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	var newAmount models.Money
+	var rec *models.Transaction
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		var b models.Balance
-		if err := tx.Table("balances").Where("user_id = ?", userID).First(&b).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("balance not found")
-			}
+		var err error
+		if rec, err = creditTx(tx, userID, amount, currency, opts); err != nil {
 			return err
 		}
-		if err := tx.Exec("UPDATE balances SET amount = amount + ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ?", amount, userID).Error; err != nil {
+		return tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", userID, currency).Scan(&newAmount).Error
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return newAmount, rec, nil
+}
+
+func (r *gormTransactionRepository) DebitAtomic(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	var newAmount models.Money
+	var rec *models.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if rec, err = debitTx(tx, userID, amount, currency, opts); err != nil {
 			return err
 		}
-		if err := tx.Table("balances").Select("amount").Where("user_id = ?", userID).Scan(&newAmount).Error; err != nil {
+		return tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", userID, currency).Scan(&newAmount).Error
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return newAmount, rec, nil
+}
+
+// TransferAtomic: aynı para birimindeki iki cüzdan arasında aktarım; farklı para birimli cüzdanlar arası hareket reddedilir
+func (r *gormTransactionRepository) TransferAtomic(fromUserID, toUserID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, models.Money, *models.Transaction, error) {
+	var fromAmt, toAmt models.Money
+	var rec *models.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if rec, err = transferTx(tx, fromUserID, toUserID, amount, currency, opts); err != nil {
 			return err
 		}
-		*rec = models.Transaction{FromUser: userID, ToUser: userID, Amount: amount, Type: "credit", Status: "completed", CreatedAt: time.Now()}
-		if err := tx.Table("transactions").Create(rec).Error; err != nil {
+		if err := tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", fromUserID, currency).Scan(&fromAmt).Error; err != nil {
 			return err
 		}
-		return nil
+		return tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", toUserID, currency).Scan(&toAmt).Error
 	})
 	if err != nil {
-		return 0, nil, err
+		return 0, 0, nil, err
 	}
-	return newAmount, rec, nil
+	return fromAmt, toAmt, rec, nil
+}
+
+// creditTx: verilen DB transaction'ı içinde credit kaydını ve ledger bacaklarını yazar (toplu işlemler de kullanır)
+func creditTx(tx *gorm.DB, userID int, amount models.Money, currency string, opts models.TxOptions) (*models.Transaction, error) {
+	var b models.Balance
+	if err := tx.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).First(&b).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("balance not found")
+		}
+		return nil, err
+	}
+	rec := &models.Transaction{FromUser: userID, ToUser: userID, Amount: amount, Currency: currency, Type: "credit", Status: "completed", CreatedAt: time.Now()}
+	if err := createWithOptions(tx, rec, opts); err != nil {
+		return nil, err
+	}
+	// dış dünyadan giriş: settlement hesabı borçlanır, kullanıcı alacaklanır
+	if err := postEntries(tx, rec.ID, rec.CreatedAt, true,
+		models.UserLeg(userID, currency, amount),
+		models.SystemLeg(models.AccountSettlement, currency, amount.Neg()),
+	); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// debitTx: verilen DB transaction'ı içinde limit kontrolü, debit kaydı, ledger bacakları ve ücret
+func debitTx(tx *gorm.DB, userID int, amount models.Money, currency string, opts models.TxOptions) (*models.Transaction, error) {
+	var b models.Balance
+	if err := tx.Table("balances").Where("user_id = ? AND currency = ?", userID, currency).First(&b).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("balance not found")
+		}
+		return nil, err
+	}
+	if err := enforceLimits(tx, userID, currency, amount, false, opts.Limit); err != nil {
+		return nil, err
+	}
+	rec := &models.Transaction{FromUser: userID, ToUser: userID, Amount: amount, Currency: currency, Type: "debit", Status: "completed", CreatedAt: time.Now()}
+	if err := createWithOptions(tx, rec, opts); err != nil {
+		return nil, err
+	}
+	// dış dünyaya çıkış: kullanıcı borçlanır, settlement hesabı alacaklanır
+	if err := postEntries(tx, rec.ID, rec.CreatedAt, true,
+		models.UserLeg(userID, currency, amount.Neg()),
+		models.SystemLeg(models.AccountSettlement, currency, amount),
+	); err != nil {
+		return nil, err
+	}
+	if err := chargeFee(tx, rec, opts.Fee); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// transferTx: verilen DB transaction'ı içinde aynı para birimli transferi, limit kontrolünü ve ücreti yazar
+func transferTx(tx *gorm.DB, fromUserID, toUserID int, amount models.Money, currency string, opts models.TxOptions) (*models.Transaction, error) {
+	var fromB, toB models.Balance
+	if err := tx.Table("balances").Where("user_id = ? AND currency = ?", fromUserID, currency).First(&fromB).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sender balance not found")
+		}
+		return nil, err
+	}
+	if err := tx.Table("balances").Where("user_id = ? AND currency = ?", toUserID, currency).First(&toB).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		// alıcının başka para biriminde cüzdanı varsa bu bir para birimi uyuşmazlığıdır
+		var other int64
+		if err := tx.Table("balances").Where("user_id = ?", toUserID).Count(&other).Error; err != nil {
+			return nil, err
+		}
+		if other > 0 {
+			return nil, errors.New("currency mismatch")
+		}
+		return nil, errors.New("recipient balance not found")
+	}
+	if fromB.Currency != toB.Currency {
+		return nil, errors.New("currency mismatch")
+	}
+	if err := enforceLimits(tx, fromUserID, currency, amount, true, opts.Limit); err != nil {
+		return nil, err
+	}
+	rec := &models.Transaction{FromUser: fromUserID, ToUser: toUserID, Amount: amount, Currency: currency, Type: "transfer", Status: "completed", CreatedAt: time.Now()}
+	if err := createWithOptions(tx, rec, opts); err != nil {
+		return nil, err
+	}
+	if err := postEntries(tx, rec.ID, rec.CreatedAt, true,
+		models.UserLeg(fromUserID, currency, amount.Neg()),
+		models.UserLeg(toUserID, currency, amount),
+	); err != nil {
+		return nil, err
+	}
+	if err := chargeFee(tx, rec, opts.Fee); err != nil {
+		return nil, err
+	}
+	return rec, nil
 }
 
-func (r *gormTransactionRepository) DebitAtomic(userID int, amount float64) (float64, *models.Transaction, error) {
-	var newAmount float64
+// TransferFXAtomic: farklı para birimli iki cüzdan arasında kur teklifine göre aktarım.
+// Ledger'da iki para birimi bacağı system:fx pozisyon hesabı üzerinden dengelenir; ücret gönderenin para biriminde alınır.
+func (r *gormTransactionRepository) TransferFXAtomic(fromUserID, toUserID int, quote models.FXQuote, opts models.TxOptions) (models.Money, models.Money, *models.Transaction, error) {
+	var fromAmt, toAmt models.Money
 	rec := &models.Transaction{}
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		var b models.Balance
-		if err := tx.Table("balances").Where("user_id = ?", userID).First(&b).Error; err != nil {
+		var fromB, toB models.Balance
+		if err := tx.Table("balances").Where("user_id = ? AND currency = ?", fromUserID, quote.FromCurrency).First(&fromB).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("sender balance not found")
+			}
+			return err
+		}
+		if err := tx.Table("balances").Where("user_id = ? AND currency = ?", toUserID, quote.ToCurrency).First(&toB).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("balance not found")
+				return errors.New("recipient balance not found")
 			}
 			return err
 		}
-		res := tx.Exec("UPDATE balances SET amount = amount - ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND amount >= ?", amount, userID, amount)
-		if res.Error != nil {
-			return res.Error
+		if err := enforceLimits(tx, fromUserID, quote.FromCurrency, quote.Amount, true, opts.Limit); err != nil {
+			return err
 		}
-		if res.RowsAffected == 0 {
-			return errors.New("insufficient funds")
+		counter, counterCur := quote.CounterAmount, quote.ToCurrency
+		rate, mid, spread, rateID := quote.Rate, quote.MidRate, quote.SpreadBps, quote.RateID
+		*rec = models.Transaction{
+			FromUser: fromUserID, ToUser: toUserID, Amount: quote.Amount, Currency: quote.FromCurrency,
+			Type: "transfer", Status: "completed", CreatedAt: time.Now(),
+			CounterAmount: &counter, CounterCurrency: &counterCur,
+			FXRate: &rate, FXMidRate: &mid, FXSpreadBps: &spread, FXRateID: &rateID,
 		}
-		if err := tx.Table("balances").Select("amount").Where("user_id = ?", userID).Scan(&newAmount).Error; err != nil {
+		if err := createWithOptions(tx, rec, opts); err != nil {
 			return err
 		}
-		*rec = models.Transaction{FromUser: userID, ToUser: userID, Amount: amount, Type: "debit", Status: "completed", CreatedAt: time.Now()}
-		if err := tx.Table("transactions").Create(rec).Error; err != nil {
+		if err := postEntries(tx, rec.ID, rec.CreatedAt, true,
+			models.UserLeg(fromUserID, quote.FromCurrency, quote.Amount.Neg()),
+			models.SystemLeg(models.AccountFX, quote.FromCurrency, quote.Amount),
+			models.SystemLeg(models.AccountFX, quote.ToCurrency, counter.Neg()),
+			models.UserLeg(toUserID, quote.ToCurrency, counter),
+		); err != nil {
 			return err
 		}
-		return nil
+		if err := chargeFee(tx, rec, opts.Fee); err != nil {
+			return err
+		}
+		if err := tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", fromUserID, quote.FromCurrency).Scan(&fromAmt).Error; err != nil {
+			return err
+		}
+		return tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", toUserID, quote.ToCurrency).Scan(&toAmt).Error
 	})
 	if err != nil {
-		return 0, nil, err
+		return 0, 0, nil, err
 	}
-	return newAmount, rec, nil
+	return fromAmt, toAmt, rec, nil
 }
 
-func (r *gormTransactionRepository) TransferAtomic(fromUserID, toUserID int, amount float64) (float64, float64, *models.Transaction, error) {
-	var fromAmt, toAmt float64
+// SplitAtomic: bölünmüş ödeme. Ebeveyn "split" işlemi tüm ledger bacaklarını taşır (gönderen bir kez borçlanır),
+// her alıcı payı ParentID ile bağlı "split_part" kaydıdır; hepsi aynı DB transaction'ında yazılır.
+func (r *gormTransactionRepository) SplitAtomic(fromUserID int, amount models.Money, currency string, parts []models.SplitPart, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	var fromAmt models.Money
 	rec := &models.Transaction{}
 	err := r.db.Transaction(func(tx *gorm.DB) error {
-		var fromB, toB models.Balance
-		if err := tx.Table("balances").Where("user_id = ?", fromUserID).First(&fromB).Error; err != nil {
+		var fromB models.Balance
+		if err := tx.Table("balances").Where("user_id = ? AND currency = ?", fromUserID, currency).First(&fromB).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return errors.New("sender balance not found")
 			}
 			return err
 		}
-		if err := tx.Table("balances").Where("user_id = ?", toUserID).First(&toB).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("recipient balance not found")
-			}
+		recipients := make([]int, len(parts))
+		for i, p := range parts {
+			recipients[i] = p.ToUserID
+		}
+		var found int64
+		if err := tx.Table("balances").Where("user_id IN ? AND currency = ?", recipients, currency).Count(&found).Error; err != nil {
+			return err
+		}
+		if int(found) != len(parts) {
+			return errors.New("recipient balance not found")
+		}
+		if err := enforceLimits(tx, fromUserID, currency, amount, true, opts.Limit); err != nil {
+			return err
+		}
+		*rec = models.Transaction{FromUser: fromUserID, ToUser: fromUserID, Amount: amount, Currency: currency, Type: models.TxTypeSplit, Status: models.TxStatusCompleted, CreatedAt: time.Now()}
+		if err := createWithOptions(tx, rec, opts); err != nil {
 			return err
 		}
-		res := tx.Exec("UPDATE balances SET amount = amount - ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ? AND amount >= ?", amount, fromUserID, amount)
-		if res.Error != nil {
-			return res.Error
+		parentID := rec.ID
+		legs := []models.LedgerEntry{models.UserLeg(fromUserID, currency, amount.Neg())}
+		for _, p := range parts {
+			child := &models.Transaction{
+				FromUser: fromUserID, ToUser: p.ToUserID, Amount: p.Amount, Currency: currency,
+				Type: models.TxTypeSplitPart, Status: models.TxStatusCompleted, CreatedAt: rec.CreatedAt, ParentID: &parentID,
+			}
+			if err := tx.Table("transactions").Create(child).Error; err != nil {
+				return err
+			}
+			rec.Legs = append(rec.Legs, child)
+			legs = append(legs, models.UserLeg(p.ToUserID, currency, p.Amount))
 		}
-		if res.RowsAffected == 0 {
-			return errors.New("insufficient funds")
+		if err := postEntries(tx, rec.ID, rec.CreatedAt, true, legs...); err != nil {
+			return err
 		}
-		if err := tx.Exec("UPDATE balances SET amount = amount + ?, last_updated_at = CURRENT_TIMESTAMP WHERE user_id = ?", amount, toUserID).Error; err != nil {
+		if err := chargeFee(tx, rec, opts.Fee); err != nil {
 			return err
 		}
-		if err := tx.Table("balances").Select("amount").Where("user_id = ?", fromUserID).Scan(&fromAmt).Error; err != nil {
+		return tx.Table("balances").Select("amount").Where("user_id = ? AND currency = ?", fromUserID, currency).Scan(&fromAmt).Error
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return fromAmt, rec, nil
+}
+
+// ReverseAtomic: orijinal işlemin (kısmen ya da tamamen) telafisini aynı DB transaction'ı içinde yazar.
+// amount sıfırsa kalan tutarın tamamı geri alınır. Telafi kayıtları orijinal ledger bacaklarının
+// orantılı tersidir; kümülatif yuvarlama sayesinde iadelerin toplamı orijinal tutara tam eşitlenir.
+// Dönüş: telafi transaction'ı ve güncellenmiş orijinal.
+func (r *gormTransactionRepository) ReverseAtomic(originalID int, amount models.Money, txType string) (*models.Transaction, *models.Transaction, error) {
+	rec := &models.Transaction{}
+	orig := &models.Transaction{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// aynı işlemin eşzamanlı iadelerini sıraya sokmak için orijinali kilitle
+		if err := tx.Table("transactions").Clauses(clause.Locking{Strength: "UPDATE"}).First(orig, originalID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("transaction not found")
+			}
 			return err
 		}
-		if err := tx.Table("balances").Select("amount").Where("user_id = ?", toUserID).Scan(&toAmt).Error; err != nil {
+		if orig.Status == models.TxStatusReversed {
+			return errors.New("transaction already reversed")
+		}
+		if !orig.Reversible() {
+			return errors.New("transaction not reversible")
+		}
+		remaining := orig.RemainingRefundable()
+		if amount.IsZero() {
+			amount = remaining
+		}
+		if amount > remaining {
+			return errors.New("refund exceeds remaining amount")
+		}
+
+		var entries []models.LedgerEntry
+		if err := tx.Table("ledger_entries").Where("transaction_id = ?", orig.ID).Order("id").Find(&entries).Error; err != nil {
 			return err
 		}
-		*rec = models.Transaction{FromUser: fromUserID, ToUser: toUserID, Amount: amount, Type: "transfer", Status: "completed", CreatedAt: time.Now()}
+		if len(entries) == 0 {
+			return errors.New("transaction has no ledger entries")
+		}
+		before, after := orig.RefundedAmount, orig.RefundedAmount+amount
+		prorate := func(v models.Money, ccy string) models.Money {
+			cur, err := models.LookupCurrency(ccy)
+			if err != nil {
+				cur = models.Currency{Code: ccy, Exponent: models.MoneyScale}
+			}
+			return v.Prorate(after, orig.Amount, cur) - v.Prorate(before, orig.Amount, cur)
+		}
+		legs := make([]models.LedgerEntry, len(entries))
+		for i, e := range entries {
+			legs[i] = models.LedgerEntry{Account: e.Account, UserID: e.UserID, Currency: e.Currency, Amount: prorate(e.Amount, e.Currency).Neg()}
+		}
+
+		parentID := orig.ID
+		*rec = models.Transaction{
+			FromUser: orig.FromUser, ToUser: orig.ToUser, Amount: amount, Currency: orig.Currency,
+			Type: txType, Status: models.TxStatusCompleted, CreatedAt: time.Now(), ParentID: &parentID,
+			FXRate: orig.FXRate, FXMidRate: orig.FXMidRate, FXSpreadBps: orig.FXSpreadBps, FXRateID: orig.FXRateID,
+		}
+		if orig.CounterAmount != nil && orig.CounterCurrency != nil {
+			counter, ccy := prorate(*orig.CounterAmount, *orig.CounterCurrency), *orig.CounterCurrency
+			rec.CounterAmount, rec.CounterCurrency = &counter, &ccy
+		}
 		if err := tx.Table("transactions").Create(rec).Error; err != nil {
 			return err
 		}
-		return nil
+		// geri alınan taraf parayı harcamışsa telafi reddedilir
+		if err := postEntries(tx, rec.ID, rec.CreatedAt, true, legs...); err != nil {
+			return err
+		}
+
+		orig.RefundedAmount = after
+		orig.Status = models.TxStatusPartiallyRefunded
+		if after == orig.Amount {
+			orig.Status = models.TxStatusReversed
+		}
+		return tx.Table("transactions").Where("id = ?", orig.ID).Updates(map[string]interface{}{
+			"refunded_amount": orig.RefundedAmount,
+			"status":          orig.Status,
+		}).Error
 	})
 	if err != nil {
-		return 0, 0, nil, err
+		return nil, nil, err
 	}
-	return fromAmt, toAmt, rec, nil
+	return rec, orig, nil
+}
+
+// createWithOptions: transaction kaydını seçeneklerle yazar; aynı origin daha önce işlendiyse ErrDuplicateOrigin,
+// external_ref kullanıcı için zaten kullanılmışsa ErrDuplicateExternalRef döner
+func createWithOptions(tx *gorm.DB, rec *models.Transaction, opts models.TxOptions) error {
+	opts.Apply(rec)
+	if o := opts.Origin; o != nil {
+		var n int64
+		if err := tx.Table("transactions").Where("origin_type = ? AND origin_id = ? AND origin_seq = ?", o.Type, o.ID, o.Seq).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrDuplicateOrigin
+		}
+	}
+	if rec.ExternalRef != nil {
+		var n int64
+		if err := tx.Table("transactions").Where("from_user_id = ? AND external_ref = ?", rec.FromUser, *rec.ExternalRef).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrDuplicateExternalRef
+		}
+	}
+	// sayım eşzamanlı iki isteği ayırt edemez; yarışı kaybeden INSERT benzersiz indekse takılır ve aynı hataya çevrilir
+	err := tx.Table("transactions").Create(rec).Error
+	switch uniqueViolation(err) {
+	case "uq_transactions_origin":
+		return ErrDuplicateOrigin
+	case "idx_transactions_from_user_external_ref":
+		return ErrDuplicateExternalRef
+	}
+	return err
 }
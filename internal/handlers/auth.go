@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"insider-go-backend/internal/config"
 	"insider-go-backend/internal/services"
 	"net/http"
 
@@ -26,8 +27,8 @@ func RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	// Yeni kullanıcı için başlangıç bakiyesi oluştur
-	services.CreateBalanceForUser(user.ID, 0.0)
+	// Yeni kullanıcı için varsayılan para biriminde başlangıç bakiyesi oluştur
+	services.CreateBalanceForUser(user.ID, config.DefaultCurrency(), 0)
 
 	c.JSON(http.StatusOK, gin.H{"message": "user registered successfully"})
 }
@@ -1,25 +1,58 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/models"
 	"insider-go-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Kullanıcının mevcut bakiyesi
+// Kullanıcının mevcut bakiyeleri (sahip olduğu tüm para birimi cüzdanları)
 func CurrentBalanceHandler(c *gin.Context) {
 	userID := c.GetInt("user_id")
 
-	balance, err := services.GetBalance(userID)
+	balances, err := services.GetBalances(userID)
 	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch balances"})
+		return
+	}
+	if len(balances) == 0 {
 		c.JSON(http.StatusNotFound, gin.H{"error": "balance not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, balance)
+	// ledger: kayıtlı bakiye, available: aktif hold'lar düşüldükten sonra harcanabilir bakiye
+	summaries := make([]models.BalanceSummary, len(balances))
+	for i := range balances {
+		summaries[i] = balances[i].Summary()
+	}
+	c.JSON(http.StatusOK, gin.H{"user_id": userID, "balances": summaries})
+}
+
+// Yeni para birimi cüzdanı aç
+func OpenWalletHandler(c *gin.Context) {
+	userID := c.GetInt("user_id")
+
+	var req struct {
+		Currency string `json:"currency" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balance, err := services.OpenWallet(userID, req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, balance)
 }
 
 // Tarihsel bakiye
@@ -39,9 +72,14 @@ func HistoricalBalanceHandler(c *gin.Context) {
 			"from_user":  tx.FromUser,
 			"to_user":    tx.ToUser,
 			"amount":     tx.Amount,
+			"currency":   tx.Currency,
 			"type":       tx.Type,
 			"status":     tx.Status,
 			"created_at": tx.CreatedAt,
+			// FX transferlerinde karşı tutar ve uygulanan kur
+			"counter_amount":   tx.CounterAmount,
+			"counter_currency": tx.CounterCurrency,
+			"fx_rate":          tx.FXRate,
 		})
 	}
 
@@ -51,26 +89,23 @@ func HistoricalBalanceHandler(c *gin.Context) {
 // Belirli bir zamanda bakiye
 func BalanceAtTimeHandler(c *gin.Context) {
 	userID := c.GetInt("user_id")
-	timeParam := c.Query("at_time")
-
-	// Accept RFC3339 or date-only (YYYY-MM-DD). Date-only is treated as end of that day (UTC).
-	atTime, err := time.Parse(time.RFC3339, timeParam)
+	atTime, err := parseTimeParam(c.Query("at_time"))
 	if err != nil {
-		if d, derr := time.Parse("2006-01-02", timeParam); derr == nil {
-			// end of day UTC: 23:59:59
-			atTime = time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, time.UTC)
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid time format; use RFC3339 or YYYY-MM-DD"})
-			return
-		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	balance, err := services.CalculateBalanceAt(userID, atTime)
+	currency := c.DefaultQuery("currency", config.DefaultCurrency())
+	balance, err := services.CalculateBalanceAt(userID, currency, atTime)
+	if errors.Is(err, models.ErrUnsupportedCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute balance at time"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"balance_at_time": balance, "at_time": atTime})
+	c.JSON(http.StatusOK, gin.H{"balance_at_time": balance, "currency": strings.ToUpper(currency), "at_time": atTime})
 }
 
 // Yeni bakiye oluştur
@@ -78,17 +113,31 @@ func CreateBalanceHandler(c *gin.Context) {
 	userID := c.GetInt("user_id")
 
 	var req struct {
-		Amount float64 `json:"amount"`
+		Amount   models.Money `json:"amount"`
+		Currency string       `json:"currency"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	updated, err := services.SetBalance(userID, req.Amount)
+	updated, err := services.SetBalance(userID, req.Currency, req.Amount)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create balance"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "balance created", "balance": updated})
 }
+
+// parseTimeParam: RFC3339 veya sadece tarih (YYYY-MM-DD) kabul eder.
+// Sadece tarih verilirse o günün sonu (UTC 23:59:59) kabul edilir.
+func parseTimeParam(v string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, v)
+	if err == nil {
+		return t, nil
+	}
+	if d, derr := time.Parse("2006-01-02", v); derr == nil {
+		return time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, time.UTC), nil
+	}
+	return time.Time{}, errors.New("invalid time format; use RFC3339 or YYYY-MM-DD")
+}
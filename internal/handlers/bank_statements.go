@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /bank-statements (admin) — multipart: file, format=camt053|csv, mapping={"date":"Tarih","amount":"Tutar",...}
+// Dosya gövde olarak da gönderilebilir (format ve mapping o zaman query parametresidir).
+func ImportBankStatementHandler(c *gin.Context) {
+	limit := config.GetBankImport().MaxFileBytes
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+1<<20)
+	format, rawMapping := c.Query("format"), c.Query("mapping")
+	filename := ""
+	body := io.Reader(c.Request.Body)
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot open uploaded file"})
+			return
+		}
+		defer f.Close()
+		body, filename = f, fh.Filename
+		if v := c.PostForm("format"); v != "" {
+			format = v
+		}
+		if v := c.PostForm("mapping"); v != "" {
+			rawMapping = v
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read bank statement"})
+		return
+	}
+	if int64(len(data)) > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "bank statement too large"})
+		return
+	}
+	var mapping *models.BankCSVMapping
+	if rawMapping != "" {
+		mapping = &models.BankCSVMapping{}
+		if err := json.Unmarshal([]byte(rawMapping), mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid mapping"})
+			return
+		}
+	}
+	report, err := services.ImportBankStatement(c.GetInt("user_id"), format, filename, data, mapping)
+	if err != nil {
+		if err.Error() == "bank statement already imported" {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "bank statement imported", "report": report})
+}
+
+// GET /bank-statements?limit=100 (admin)
+func ListBankStatementsHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	list, err := services.ListBankStatements(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch bank statements"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bank_statements": list})
+}
+
+// GET /bank-statements/:id?status=unmatched (admin)
+func GetBankStatementHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	stmt, lines, err := services.GetBankStatement(id, c.Query("status"))
+	if err != nil {
+		writeBankStatementError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bank_statement": stmt, "lines": lines})
+}
+
+// GET /bank-statements/:id/report (admin) — durum toplamları, eşleşmeyen ve istisna kalemler
+func GetBankStatementReportHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	report, err := services.GetBankStatementReport(id)
+	if err != nil {
+		writeBankStatementError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// POST /bank-statements/:id/rematch (admin) — sonradan girilen credit'leri eşleşmeyen kalemlere bağlar
+func RematchBankStatementHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	report, err := services.RematchBankStatement(c.GetInt("user_id"), id)
+	if err != nil {
+		writeBankStatementError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+type CreditBankLineRequest struct {
+	UserID int `json:"user_id"` // boşsa kalem için önerilen kullanıcı
+}
+
+// POST /bank-statements/lines/:line_id/credit (admin) — eşleşmeyen yatırımı tek adımda kullanıcıya yükler
+func CreditBankStatementLineHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("line_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid line id"})
+		return
+	}
+	var req CreditBankLineRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	line, tx, err := services.CreditBankStatementLine(c.GetInt("user_id"), id, req.UserID)
+	if err != nil {
+		switch err.Error() {
+		case "bank statement line not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "bank statement line is not unmatched":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			writeTxError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "credited", "line": line, "transaction": tx})
+}
+
+func writeBankStatementError(c *gin.Context, err error) {
+	if err.Error() == "bank statement not found" {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process bank statement"})
+}
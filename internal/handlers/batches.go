@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchRequest: toplu işlem isteği; kalemler çağıranın cüzdanı üzerinde çalışır
+type BatchRequest struct {
+	Mode  string             `json:"mode"` // all_or_nothing | best_effort (varsayılan)
+	Items []models.BatchItem `json:"items" binding:"required"`
+}
+
+// POST /transactions/batch
+// all_or_nothing modunda batch başarısızsa 422, aksi halde 201 döner; kalem sonuçları gövdededir
+func SubmitBatchHandler(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	b, err := services.SubmitBatch(c.GetInt("user_id"), req.Mode, req.Items)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status := http.StatusCreated
+	if b.Mode == models.BatchAllOrNothing && b.Status == models.BatchFailed {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, b)
+}
+
+// GET /transactions/batches?limit=50
+func ListBatchesHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	items, err := services.ListBatches(c.GetInt("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch batches"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"batches": items})
+}
+
+// GET /transactions/batches/:id (sahibi ya da admin)
+func GetBatchHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	b, err := services.GetBatch(c.GetInt("user_id"), c.GetString("role") == "admin", id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EscrowRequest struct {
+	SellerID       int          `json:"seller_id" binding:"required"`
+	Amount         models.Money `json:"amount" binding:"required,gt=0"`
+	Currency       string       `json:"currency"`
+	Description    string       `json:"description"`
+	ExpiresIn      string       `json:"expires_in"`      // Go duration ("72h"); boşsa ESCROW_TTL
+	TimeoutOutcome string       `json:"timeout_outcome"` // release | refund; boşsa ESCROW_TIMEOUT_OUTCOME
+}
+
+type EscrowDecisionRequest struct {
+	Outcome string `json:"outcome" binding:"required"` // release | refund
+	Reason  string `json:"reason"`                     // yalnızca arbitration için
+}
+
+// POST /escrows — alıcı escrow'u fonlar
+func OpenEscrowHandler(c *gin.Context) {
+	var req EscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_in"})
+			return
+		}
+		ttl = d
+	}
+	e, err := services.OpenEscrow(c.GetInt("user_id"), req.SellerID, req.Amount, req.Currency, req.Description, ttl, req.TimeoutOutcome)
+	if err != nil {
+		writeTxError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, e)
+}
+
+// GET /escrows?side=buyer|seller&status=funded — admin için all=true tüm escrow'ları döner
+func ListEscrowsHandler(c *gin.Context) {
+	var (
+		items []models.Escrow
+		err   error
+	)
+	if c.Query("all") == "true" && c.GetString("role") == "admin" {
+		items, err = services.ListAllEscrows(c.Query("status"))
+	} else {
+		items, err = services.ListEscrows(c.GetInt("user_id"), c.Query("side"), c.Query("status"))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"escrows": items})
+}
+
+// GET /escrows/:id
+func GetEscrowHandler(c *gin.Context) {
+	id, ok := escrowID(c)
+	if !ok {
+		return
+	}
+	e, err := services.GetEscrow(c.GetInt("user_id"), c.GetString("role") == "admin", id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// POST /escrows/:id/confirm — alıcı ya da satıcı sonucu onaylar
+func ConfirmEscrowHandler(c *gin.Context) {
+	id, req, ok := escrowDecision(c)
+	if !ok {
+		return
+	}
+	e, err := services.ConfirmEscrow(c.GetInt("user_id"), id, req.Outcome)
+	if err != nil {
+		writeEscrowError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+// POST /escrows/:id/arbitrate — admin kararı
+func ArbitrateEscrowHandler(c *gin.Context) {
+	id, req, ok := escrowDecision(c)
+	if !ok {
+		return
+	}
+	e, err := services.ArbitrateEscrow(c.GetInt("user_id"), id, req.Outcome, req.Reason)
+	if err != nil {
+		writeEscrowError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, e)
+}
+
+func escrowDecision(c *gin.Context) (int, EscrowDecisionRequest, bool) {
+	var req EscrowDecisionRequest
+	id, ok := escrowID(c)
+	if !ok {
+		return 0, req, false
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, req, false
+	}
+	return id, req, true
+}
+
+func writeEscrowError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "escrow not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case "escrow not open", "escrow expired":
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+func escrowID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
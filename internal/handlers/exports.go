@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /transactions/export?format=csv|ofx|camt053&currency=USD&from=&to=&user_id=
+// Hesap hareketlerini açılış/kapanış ve yürüyen bakiyelerle akış olarak indirir; admin user_id ile başka kullanıcıyı dışa aktarabilir.
+// Akış başladıktan sonraki hatalarda yanıt yarım kalır (XML kapanmaz, CSV'de closing_balance satırı olmaz).
+// Sunucunun WriteTimeout'u uzun dışa aktarmaları kesmesin diye yazma süresi akış boyunca uzatılır.
+func ExportTransactionsHandler(c *gin.Context) {
+	userID, ok := statementUser(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	if c.Query("from") == "" || c.Query("to") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+	from, err := parseRangeBound("from", c.Query("from"), false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseRangeBound("to", c.Query("to"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := c.DefaultQuery("format", models.ExportCSV)
+	cfg := config.GetExport()
+	out := &streamWriter{w: c.Writer, rc: http.NewResponseController(c.Writer), window: cfg.WriteWindow, every: cfg.FlushEvery}
+	w, err := models.NewExportWriter(format, out)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h, err := services.PrepareExport(userID, c.Query("currency"), from, to)
+	if err != nil {
+		if err.Error() == "balance not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := fmt.Sprintf("transactions-%d-%s-%s-%s", h.UserID, h.Currency.Code, h.From.UTC().Format("20060102"), h.To.UTC().Format("20060102"))
+	c.Header("Content-Type", w.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, w.Extension()))
+	c.Status(http.StatusOK)
+	out.extend(time.Now())
+	// başlıklar gönderildi; hata servis tarafında loglanır
+	_ = services.StreamExport(c.GetInt("user_id"), h, w, format)
+	_ = out.rc.Flush()
+}
+
+// streamWriter: uzun akışlarda her yazmadan önce bağlantının yazma süresini window kadar ileri alır
+// ve yazılanları en geç every aralıklarla istemciye gönderir
+type streamWriter struct {
+	w       io.Writer
+	rc      *http.ResponseController
+	window  time.Duration
+	every   time.Duration
+	flushed time.Time
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	now := time.Now()
+	s.extend(now)
+	n, err := s.w.Write(p)
+	if err == nil && now.Sub(s.flushed) >= s.every {
+		err = s.rc.Flush()
+		s.flushed = now
+	}
+	return n, err
+}
+
+// extend: yazma süresini uzatır; desteklenmeyen yazıcılarda (ör. testler) sessizce atlanır
+func (s *streamWriter) extend(now time.Time) {
+	_ = s.rc.SetWriteDeadline(now.Add(s.window))
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /transactions/fee-quote?type=transfer&amount=100&currency=USD — işlem öncesi ücret teklifi
+func FeeQuoteHandler(c *gin.Context) {
+	amount, err := models.ParseMoney(c.Query("amount"))
+	if err != nil || !amount.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be a positive decimal"})
+		return
+	}
+	quote, err := services.QuoteFee(c.GetInt("user_id"), c.DefaultQuery("type", "transfer"), amount, c.Query("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+// GET /fees?type=transfer&currency=USD&at=2025-01-01T00:00:00Z (admin)
+func ListFeeSchedulesHandler(c *gin.Context) {
+	var at *time.Time
+	if v := c.Query("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at must be RFC3339"})
+			return
+		}
+		at = &t
+	}
+	items, err := services.ListFeeSchedules(c.Query("type"), c.Query("currency"), at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch fee schedules"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedules": items})
+}
+
+// GET /fees/:id (admin)
+func GetFeeScheduleHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	f, err := services.GetFeeSchedule(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, f)
+}
+
+// POST /fees (admin)
+func CreateFeeScheduleHandler(c *gin.Context) {
+	var f models.FeeSchedule
+	if err := c.ShouldBindJSON(&f); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.CreateFeeSchedule(&f, c.GetInt("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, f)
+}
+
+// PUT /fees/:id (admin) — tarifenin tamamını değiştirir
+func UpdateFeeScheduleHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var f models.FeeSchedule
+	if err := c.ShouldBindJSON(&f); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	f.ID = id
+	if err := services.UpdateFeeSchedule(&f, c.GetInt("user_id")); err != nil {
+		if err.Error() == "fee schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, f)
+}
+
+// DELETE /fees/:id (admin)
+func DeleteFeeScheduleHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := services.DeleteFeeSchedule(id, c.GetInt("user_id")); err != nil {
+		if err.Error() == "fee schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
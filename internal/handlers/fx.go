@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /transactions/fx-quote?from=USD&to=EUR&amount=100
+func FXQuoteHandler(c *gin.Context) {
+	amount, err := models.ParseMoney(c.Query("amount"))
+	if err != nil || !amount.IsPositive() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be a positive decimal"})
+		return
+	}
+	quote, err := services.QuoteFX(c.Query("from"), c.Query("to"), amount, time.Now())
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrFXRateNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+// POST /fx/rates
+func CreateFXRateHandler(c *gin.Context) {
+	var req struct {
+		Base      string      `json:"base" binding:"required"`
+		Quote     string      `json:"quote" binding:"required"`
+		Rate      models.Rate `json:"rate" binding:"required,gt=0"`
+		SpreadBps *int        `json:"spread_bps"`
+		ValidFrom *time.Time  `json:"valid_from"`
+		ValidTo   *time.Time  `json:"valid_to"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rate := &models.FXRate{
+		Base:      req.Base,
+		Quote:     req.Quote,
+		Rate:      req.Rate,
+		SpreadBps: req.SpreadBps,
+		ValidTo:   req.ValidTo,
+		CreatedBy: c.GetInt("user_id"),
+	}
+	if req.ValidFrom != nil {
+		rate.ValidFrom = *req.ValidFrom
+	}
+	if err := services.CreateFXRate(rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+// POST /fx/rates/bulk (text/csv gövde ya da multipart "file" alanı)
+func BulkUploadFXRatesHandler(c *gin.Context) {
+	body := c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file field required"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot open uploaded file"})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+	n, err := services.ImportFXRatesCSV(body, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "rates imported", "count": n})
+}
+
+// GET /fx/rates?base=USD&quote=EUR&limit=100
+func ListFXRatesHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	rates, err := services.ListFXRates(c.Query("base"), c.Query("quote"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch rates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rates": rates})
+}
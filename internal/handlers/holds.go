@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorizeRequest struct {
+	Amount    models.Money `json:"amount" binding:"required,gt=0"`
+	Currency  string       `json:"currency"`
+	ToUser    int          `json:"to_user_id"` // capture alıcısı (merchant); boşsa capture debit olur
+	ExpiresIn string       `json:"expires_in"` // Go duration ("30m", "72h"); boşsa HOLD_TTL
+	Reference string       `json:"reference"`
+}
+
+type CaptureRequest struct {
+	Amount models.Money `json:"amount"` // boşsa hold tutarının tamamı
+}
+
+// POST /transactions/authorize
+func AuthorizeHandler(c *gin.Context) {
+	var req AuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_in"})
+			return
+		}
+		ttl = d
+	}
+	hold, err := services.AuthorizeHold(c.GetInt("user_id"), req.ToUser, req.Amount, req.Currency, ttl, req.Reference)
+	if err != nil {
+		writeTxError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "authorized", "hold": hold})
+}
+
+// POST /transactions/holds/:id/capture
+func CaptureHoldHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold id"})
+		return
+	}
+	var req CaptureRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	hold, tx, err := services.CaptureHold(c.GetInt("user_id"), id, req.Amount)
+	if err != nil {
+		var lim *models.LimitExceededError
+		if errors.As(err, &lim) {
+			writeTxError(c, err)
+			return
+		}
+		c.JSON(holdErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "captured", "hold": hold, "transaction": tx})
+}
+
+// POST /transactions/holds/:id/void
+func VoidHoldHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hold id"})
+		return
+	}
+	hold, err := services.VoidHold(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(holdErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "voided", "hold": hold})
+}
+
+// GET /transactions/holds?status=active
+func ListHoldsHandler(c *gin.Context) {
+	holds, err := services.ListHolds(c.GetInt("user_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch holds"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"holds": holds})
+}
+
+func holdErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrHoldForbidden):
+		return http.StatusForbidden
+	case err.Error() == "hold not found":
+		return http.StatusNotFound
+	case err.Error() == "hold not active", err.Error() == "hold expired":
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /ledger/trial-balance?at=...
+// Tüm hesapların bakiyesi; kayıtların toplamı sıfır değilse "balanced": false döner
+func TrialBalanceHandler(c *gin.Context) {
+	at := time.Now()
+	if v := c.Query("at"); v != "" {
+		t, err := parseTimeParam(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		at = t
+	}
+	report, err := services.TrialBalance(at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute trial balance"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GET /ledger/transactions/:id/entries
+func TransactionLedgerEntriesHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+	entries, err := services.GetLedgerEntriesByTransaction(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch ledger entries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"transaction_id": id, "entries": entries})
+}
+
+// GET /ledger/accounts/:account/entries
+func AccountLedgerEntriesHandler(c *gin.Context) {
+	account := c.Param("account")
+	entries, err := services.GetLedgerEntriesByAccount(account)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch ledger entries"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"account": account, "entries": entries})
+}
+
+// GET /ledger/reconcile?format=json|csv — balances ile ledger ve işlemler ile ledger bacakları arasındaki uyuşmazlık raporu (düzeltme yapmaz)
+// POST /ledger/reconcile?confirm=true — aynı rapor; her uyuşmazlık için düzeltme kaydı açar
+func ReconcileHandler(c *gin.Context) {
+	apply := c.Request.Method == http.MethodPost
+	if apply && c.Query("confirm") != "true" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "corrections require confirm=true"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+	report, err := services.Reconcile(apply, c.GetInt("user_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reconcile ledger"})
+		return
+	}
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", `attachment; filename="reconcile.csv"`)
+		c.Status(http.StatusOK)
+		_ = report.WriteCSV(c.Writer)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /limits?role=user&user_id=42 (admin)
+func ListLimitPoliciesHandler(c *gin.Context) {
+	userID, _ := strconv.Atoi(c.Query("user_id"))
+	items, err := services.ListLimitPolicies(c.Query("role"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch limit policies"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"policies": items})
+}
+
+// PUT /limits (admin) — rol varsayılanı (role) ya da kullanıcı override'ı (user_id) ekler/günceller
+func UpsertLimitPolicyHandler(c *gin.Context) {
+	var p models.LimitPolicy
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	p.ID = 0
+	if err := services.UpsertLimitPolicy(&p, c.GetInt("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// DELETE /limits/:id (admin)
+func DeleteLimitPolicyHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := services.DeleteLimitPolicy(id, c.GetInt("user_id")); err != nil {
+		if err.Error() == "limit policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// GET /limits/effective?user_id=42&currency=USD (admin)
+func EffectiveLimitsHandler(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Query("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id required"})
+		return
+	}
+	cur, err := models.LookupCurrency(c.DefaultQuery("currency", config.DefaultCurrency()))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	eff, err := services.ResolveLimits(userID, cur.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if eff == nil {
+		eff = &models.EffectiveLimit{UserID: userID, Currency: cur.Code}
+	}
+	c.JSON(http.StatusOK, eff)
+}
+
+// writeTxError: para hareketi hatasını yanıtlar; limit aşımı ayırt edici kodla 422 döner
+func writeTxError(c *gin.Context, err error) {
+	var lim *models.LimitExceededError
+	if errors.As(err, &lim) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "code": models.ErrCodeLimitExceeded, "limit": lim})
+		return
+	}
+	if err.Error() == "external_ref already used" {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PUT /users/:id/wallets/:currency/credit-line (admin) — overdraft limiti ve opsiyonel yıllık faiz oranı (bps)
+func SetCreditLineHandler(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+	var req struct {
+		CreditLimit      models.Money `json:"credit_limit"`
+		OverdraftRateBps *int         `json:"overdraft_rate_bps"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	b, err := services.SetCreditLine(c.GetInt("user_id"), userID, strings.ToUpper(c.Param("currency")), req.CreditLimit, req.OverdraftRateBps)
+	if err != nil {
+		switch err.Error() {
+		case "balance not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, b.Summary())
+}
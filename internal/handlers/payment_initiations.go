@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/processor"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /payment-initiations — pain.001 dosyası (multipart "file" ya da ham XML gövde).
+// Dosya doğrulanıp kaydedilir, kalemler işlemcide arka planda çalışır; sonuç GET /payment-initiations/:id/report ile alınır.
+func SubmitPaymentInitiationHandler(c *gin.Context) {
+	p := processor.GetDefault()
+	if p == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "processor not running"})
+		return
+	}
+	limit := config.GetPaymentInitiation().MaxFileBytes
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+1<<20)
+	filename := ""
+	body := io.Reader(c.Request.Body)
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot open uploaded file"})
+			return
+		}
+		defer f.Close()
+		body, filename = f, fh.Filename
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read pain.001"})
+		return
+	}
+	if int64(len(data)) > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "pain.001 too large"})
+		return
+	}
+	pi, err := services.SubmitPain001(c.GetInt("user_id"), c.GetString("role") == "admin", filename, data)
+	if err != nil {
+		var rej *models.Pain001Error
+		if !errors.As(err, &rej) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to submit payment initiation"})
+			return
+		}
+		switch rej.Code {
+		case models.PainReasonDuplicateMsg:
+			c.JSON(http.StatusConflict, rej)
+		case models.PainReasonForbidden:
+			c.JSON(http.StatusForbidden, rej)
+		default:
+			c.JSON(http.StatusUnprocessableEntity, rej)
+		}
+		return
+	}
+	if pi.Status == models.PaymentInitiationCompleted {
+		// tüm kalemler doğrulamada reddedildi; çalıştırılacak kalem yok
+		c.JSON(http.StatusOK, gin.H{"message": "payment initiation rejected", "payment_initiation": pi})
+		return
+	}
+	p.RunPaymentInitiation(pi)
+	c.JSON(http.StatusAccepted, gin.H{"message": "payment initiation accepted", "payment_initiation": pi})
+}
+
+// GET /payment-initiations?limit=50 — kullanıcının talimatları (kalemler olmadan)
+func ListPaymentInitiationsHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	list, err := services.ListPaymentInitiations(c.GetInt("user_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch payment initiations"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"payment_initiations": list})
+}
+
+// GET /payment-initiations/:id (gönderen ya da admin)
+func GetPaymentInitiationHandler(c *gin.Context) {
+	pi, ok := paymentInitiation(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, pi)
+}
+
+// GET /payment-initiations/:id/report?format=xml|json — pain.002 durum raporu; çalıştırma sürerken bekleyen kalemler PDNG görünür
+func GetPaymentInitiationReportHandler(c *gin.Context) {
+	format := c.DefaultQuery("format", "xml")
+	if format != "xml" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be xml or json"})
+		return
+	}
+	pi, ok := paymentInitiation(c)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if format == "json" {
+		c.JSON(http.StatusOK, pi.Pain002(now))
+		return
+	}
+	c.Header("Content-Type", "application/xml")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="pain002-%d.xml"`, pi.ID))
+	c.Status(http.StatusOK)
+	_ = pi.WritePain002(c.Writer, now)
+}
+
+func paymentInitiation(c *gin.Context) (*models.PaymentInitiation, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return nil, false
+	}
+	pi, err := services.GetPaymentInitiation(c.GetInt("user_id"), c.GetString("role") == "admin", id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return pi, true
+}
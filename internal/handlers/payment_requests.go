@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentRequestRequest struct {
+	PayerID   int          `json:"payer_id" binding:"required"`
+	Amount    models.Money `json:"amount" binding:"required,gt=0"`
+	Currency  string       `json:"currency"`
+	Note      string       `json:"note"`
+	ExpiresIn string       `json:"expires_in"` // Go duration ("48h"); boşsa PAYMENT_REQUEST_TTL
+}
+
+// POST /payment-requests
+func CreatePaymentRequestHandler(c *gin.Context) {
+	var req PaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		d, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || d <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_in"})
+			return
+		}
+		ttl = d
+	}
+	pr, err := services.CreatePaymentRequest(c.GetInt("user_id"), req.PayerID, req.Amount, req.Currency, req.Note, ttl)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, pr)
+}
+
+// GET /payment-requests?direction=incoming|outgoing&status=pending
+func ListPaymentRequestsHandler(c *gin.Context) {
+	items, err := services.ListPaymentRequests(c.GetInt("user_id"), c.Query("direction"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"payment_requests": items})
+}
+
+// GET /payment-requests/:id
+func GetPaymentRequestHandler(c *gin.Context) {
+	id, ok := paymentRequestID(c)
+	if !ok {
+		return
+	}
+	pr, err := services.GetPaymentRequest(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pr)
+}
+
+// POST /payment-requests/:id/accept — ödeyen kabul eder, transfer yapılır
+func AcceptPaymentRequestHandler(c *gin.Context) {
+	paymentRequestAction(c, services.AcceptPaymentRequest)
+}
+
+// POST /payment-requests/:id/decline — ödeyen reddeder
+func DeclinePaymentRequestHandler(c *gin.Context) {
+	paymentRequestAction(c, services.DeclinePaymentRequest)
+}
+
+// POST /payment-requests/:id/cancel — isteyen geri çeker
+func CancelPaymentRequestHandler(c *gin.Context) {
+	paymentRequestAction(c, services.CancelPaymentRequest)
+}
+
+func paymentRequestAction(c *gin.Context, fn func(userID, id int) (*models.PaymentRequest, error)) {
+	id, ok := paymentRequestID(c)
+	if !ok {
+		return
+	}
+	pr, err := fn(c.GetInt("user_id"), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentRequestForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case err.Error() == "payment request not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case err.Error() == "payment request not pending", err.Error() == "payment request expired":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			writeTxError(c, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, pr)
+}
+
+func paymentRequestID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
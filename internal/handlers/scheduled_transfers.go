@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledTransferRequest struct {
+	ToUser    int          `json:"to_user_id" binding:"required"`
+	Amount    models.Money `json:"amount" binding:"required,gt=0"`
+	Currency  string       `json:"currency"`
+	ExecuteAt time.Time    `json:"execute_at" binding:"required"` // RFC3339
+}
+
+// POST /transfers/scheduled
+func CreateScheduledTransferHandler(c *gin.Context) {
+	var req ScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	st, err := services.CreateScheduledTransfer(c.GetInt("user_id"), req.ToUser, req.Amount, req.Currency, req.ExecuteAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, st)
+}
+
+// GET /transfers/scheduled?status=pending
+func ListScheduledTransfersHandler(c *gin.Context) {
+	items, err := services.ListScheduledTransfers(c.GetInt("user_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch scheduled transfers"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"scheduled_transfers": items})
+}
+
+// GET /transfers/scheduled/:id — çalıştırma geçmişiyle birlikte
+func GetScheduledTransferHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	st, runs, err := services.GetScheduledTransfer(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"scheduled_transfer": st, "runs": runs})
+}
+
+// PUT /transfers/scheduled/:id
+func UpdateScheduledTransferHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req ScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	st, err := services.UpdateScheduledTransfer(c.GetInt("user_id"), id, req.ToUser, req.Amount, req.Currency, req.ExecuteAt)
+	if err != nil {
+		c.JSON(scheduledErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, st)
+}
+
+// DELETE /transfers/scheduled/:id
+func CancelScheduledTransferHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	if err := services.CancelScheduledTransfer(c.GetInt("user_id"), id); err != nil {
+		c.JSON(scheduledErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "cancelled"})
+}
+
+func scheduledErrorStatus(err error) int {
+	switch err.Error() {
+	case "scheduled transfer not found":
+		return http.StatusNotFound
+	case "scheduled transfer not pending":
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StandingOrderRequest struct {
+	ToUser         int          `json:"to_user_id" binding:"required"`
+	Amount         models.Money `json:"amount" binding:"required,gt=0"`
+	Currency       string       `json:"currency"`
+	Frequency      string       `json:"frequency" binding:"required"` // weekly | monthly | last_business_day
+	DayOfMonth     int          `json:"day_of_month"`                 // monthly için 1-31
+	StartAt        time.Time    `json:"start_at"`                     // boşsa şimdi; çalıştırma saati buradan alınır
+	EndAt          *time.Time   `json:"end_at"`
+	MaxOccurrences *int         `json:"max_occurrences"`
+}
+
+// POST /standing-orders
+func CreateStandingOrderHandler(c *gin.Context) {
+	var req StandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	o := &models.StandingOrder{
+		UserID: c.GetInt("user_id"), ToUserID: req.ToUser, Amount: req.Amount, Currency: req.Currency,
+		Frequency: req.Frequency, DayOfMonth: req.DayOfMonth, StartAt: req.StartAt, EndAt: req.EndAt, MaxOccurrences: req.MaxOccurrences,
+	}
+	if err := services.CreateStandingOrder(o); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, o)
+}
+
+// GET /standing-orders?status=active
+func ListStandingOrdersHandler(c *gin.Context) {
+	items, err := services.ListStandingOrders(c.GetInt("user_id"), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch standing orders"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"standing_orders": items})
+}
+
+// GET /standing-orders/:id
+func GetStandingOrderHandler(c *gin.Context) {
+	id, ok := standingOrderID(c)
+	if !ok {
+		return
+	}
+	o, err := services.GetStandingOrder(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, o)
+}
+
+// GET /standing-orders/:id/runs — her occurrence ve sonucu
+func StandingOrderRunsHandler(c *gin.Context) {
+	id, ok := standingOrderID(c)
+	if !ok {
+		return
+	}
+	runs, err := services.GetStandingOrderRuns(c.GetInt("user_id"), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"standing_order_id": id, "runs": runs})
+}
+
+// POST /standing-orders/:id/pause
+func PauseStandingOrderHandler(c *gin.Context) {
+	standingOrderAction(c, services.PauseStandingOrder, "paused")
+}
+
+// POST /standing-orders/:id/resume
+func ResumeStandingOrderHandler(c *gin.Context) {
+	standingOrderAction(c, services.ResumeStandingOrder, "resumed")
+}
+
+// DELETE /standing-orders/:id
+func CancelStandingOrderHandler(c *gin.Context) {
+	standingOrderAction(c, services.CancelStandingOrder, "cancelled")
+}
+
+func standingOrderAction(c *gin.Context, fn func(userID, id int) error, message string) {
+	id, ok := standingOrderID(c)
+	if !ok {
+		return
+	}
+	if err := fn(c.GetInt("user_id"), id); err != nil {
+		switch err.Error() {
+		case "standing order not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "invalid standing order state":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func standingOrderID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatementRequest struct {
+	Period   string `json:"period" binding:"required"` // YYYY-MM
+	Currency string `json:"currency"`
+	UserID   int    `json:"user_id"` // yalnızca admin başka kullanıcı için üretebilir
+}
+
+// GET /statements?currency=USD — admin user_id ile başka kullanıcının ekstrelerini listeleyebilir
+func ListStatementsHandler(c *gin.Context) {
+	userID, ok := statementUser(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+	items, err := services.ListStatements(userID, c.Query("currency"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch statements"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"statements": items})
+}
+
+// POST /statements — kapanmış bir ay için ekstre üretir; zaten varsa mevcut ekstre döner
+func GenerateStatementHandler(c *gin.Context) {
+	var req StatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	month, err := time.Parse("2006-01", req.Period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be YYYY-MM"})
+		return
+	}
+	userID, ok := statementUser(c, strconv.Itoa(req.UserID))
+	if !ok {
+		return
+	}
+	s, err := services.GenerateStatement(userID, req.Currency, month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, s)
+}
+
+// GET /statements/:id?format=json|csv|html — ekstre indirme; X-Content-SHA256 saklanan içeriğin hash'idir
+func GetStatementHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" && format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json, csv or html"})
+		return
+	}
+	s, err := services.GetStatement(c.GetInt("user_id"), c.GetString("role") == "admin", id)
+	if err != nil {
+		if err.Error() == "statement not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("X-Content-SHA256", s.ContentHash)
+	name := fmt.Sprintf("statement-%d-%s-%s", s.UserID, s.Currency, s.PeriodStart.Format("2006-01"))
+	switch format {
+	case "csv":
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+		c.Status(http.StatusOK)
+		_ = s.WriteCSV(c.Writer)
+	case "html":
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.html"`, name))
+		c.Status(http.StatusOK)
+		_ = s.WriteHTML(c.Writer)
+	default:
+		c.JSON(http.StatusOK, s)
+	}
+}
+
+// statementUser: hedef kullanıcı; boş/sıfırsa istek sahibi, başka kullanıcı yalnızca admin için
+func statementUser(c *gin.Context, raw string) (int, bool) {
+	self := c.GetInt("user_id")
+	if raw == "" || raw == "0" {
+		return self, true
+	}
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
+		return 0, false
+	}
+	if id != self && c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden: insufficient role"})
+		return 0, false
+	}
+	return id, true
+}
@@ -1,17 +1,42 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"insider-go-backend/internal/models"
 	"insider-go-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TransactionRequest struct {
-	Amount float64 `json:"amount" binding:"required,gt=0"`
-	ToUser int     `json:"to_user_id"`
+	Amount   models.Money `json:"amount" binding:"required,gt=0"`
+	Currency string       `json:"currency"` // ISO 4217; boşsa varsayılan para birimi
+	ToUser   int          `json:"to_user_id"`
+	// ToCurrency: transfer için alıcı cüzdanın para birimi; farklıysa kur tablosuyla çevrilir
+	ToCurrency string `json:"to_currency"`
+	// Opsiyonel metadata; external_ref kullanıcı için benzersizdir
+	Memo        string      `json:"memo"`
+	Category    string      `json:"category"`
+	Tags        models.Tags `json:"tags"`
+	ExternalRef string      `json:"external_ref"`
+}
+
+// opts: istekteki metadata'yı işlem seçeneklerine çevirir
+func (r TransactionRequest) opts() models.TxOptions {
+	if r.Memo == "" && r.Category == "" && len(r.Tags) == 0 && r.ExternalRef == "" {
+		return models.TxOptions{}
+	}
+	meta := &models.TxMeta{Memo: r.Memo, Category: r.Category, Tags: r.Tags}
+	if r.ExternalRef != "" {
+		ref := r.ExternalRef
+		meta.ExternalRef = &ref
+	}
+	return models.TxOptions{Meta: meta}
 }
 
 // POST /transactions/credit
@@ -22,12 +47,12 @@ func CreditHandler(c *gin.Context) {
 		return
 	}
 	userID := c.GetInt("user_id")
-	newBal, err := services.Credit(userID, req.Amount)
+	newBal, tx, err := services.CreditWith(userID, req.Amount, req.Currency, req.opts())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeTxError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "credited", "new_balance": newBal})
+	c.JSON(http.StatusOK, gin.H{"message": "credited", "new_balance": newBal, "transaction": tx})
 }
 
 // POST /transactions/debit
@@ -38,12 +63,12 @@ func DebitHandler(c *gin.Context) {
 		return
 	}
 	userID := c.GetInt("user_id")
-	newBal, err := services.Debit(userID, req.Amount)
+	newBal, tx, err := services.DebitWith(userID, req.Amount, req.Currency, req.opts())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeTxError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "debited", "new_balance": newBal})
+	c.JSON(http.StatusOK, gin.H{"message": "debited", "new_balance": newBal, "transaction": tx})
 }
 
 // POST /transactions/transfer
@@ -60,23 +85,157 @@ func TransferHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipient"})
 		return
 	}
-	fromNew, _, err := services.Transfer(fromUserID, toUserID, req.Amount)
+	// ücret önceden hesaplanır ki yanıttaki eski bakiye ayrı ücret bacağını da içersin
+	fee, err := services.QuoteFee(fromUserID, "transfer", req.Amount, req.Currency)
 	if err != nil {
+		writeTxError(c, err)
+		return
+	}
+	opts := req.opts()
+	opts.Fee = fee
+	if services.IsCrossCurrency(req.Currency, req.ToCurrency) {
+		fromNew, _, quote, tx, err := services.TransferFXWith(fromUserID, toUserID, req.Amount, req.Currency, req.ToCurrency, opts)
+		if err != nil {
+			writeTxError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "transfer completed", "old_balance": fromNew + fee.Total, "new_balance": fromNew, "amount_transferred": req.Amount, "fee": fee.Fee, "fx": quote, "transaction": tx})
+		return
+	}
+	fromNew, _, tx, err := services.TransferWith(fromUserID, toUserID, req.Amount, req.Currency, opts)
+	if err != nil {
+		writeTxError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "transfer completed", "old_balance": fromNew + fee.Total, "new_balance": fromNew, "amount_transferred": req.Amount, "fee": fee.Fee, "transaction": tx})
+}
+
+// SplitRequest: bölünmüş ödeme; Amount boşsa tutarla verilen payların toplamıdır
+type SplitRequest struct {
+	Amount     models.Money       `json:"amount"`
+	Currency   string             `json:"currency"`
+	Recipients []models.SplitPart `json:"recipients" binding:"required"`
+}
+
+// POST /transactions/split
+func SplitTransferHandler(c *gin.Context) {
+	var req SplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "transfer completed", "old_balance": fromNew + req.Amount, "new_balance": fromNew, "amount_transferred": req.Amount})
+	fromNew, tx, err := services.SplitTransfer(c.GetInt("user_id"), req.Amount, req.Currency, req.Recipients)
+	if err != nil {
+		writeTxError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "split completed", "new_balance": fromNew, "transaction": tx})
 }
 
-// GET /transactions/history
+// GET /transactions/history?from=&to=&type=&status=&min_amount=&max_amount=&counterparty=&direction=&category=&tag=&external_ref=&q=&order=&limit=&cursor=
+// Sonuçlar zamana göre sıralıdır (varsayılan en yeni önce); sonraki sayfa next_cursor ile istenir.
+// Bölünmüş ödemelerin payları ebeveyn işlemin "legs" alanında gruplanır; q memo içinde tam metin arar
 func TransactionHistoryHandler(c *gin.Context) {
-	userID := c.GetInt("user_id")
-	txs, err := services.GetTransactionsByUser(userID)
+	f, err := historyFilter(c)
+	if err == nil {
+		err = f.Normalize()
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"transactions": txs})
+	page, err := services.ListTransactionHistory(c.GetInt("user_id"), f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch transactions"})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// historyFilter: geçmiş sorgu parametrelerini filtreye çevirir; sadece tarih verilirse from günün başı, to ertesi günün başıdır
+func historyFilter(c *gin.Context) (models.TxFilter, error) {
+	f := models.TxFilter{
+		Category:    c.Query("category"),
+		Tag:         c.Query("tag"),
+		ExternalRef: c.Query("external_ref"),
+		Query:       c.Query("q"),
+		Status:      c.Query("status"),
+		Direction:   c.Query("direction"),
+	}
+	if v := c.Query("type"); v != "" {
+		f.Types = strings.Split(v, ",")
+	}
+	for _, b := range []struct {
+		name string
+		dst  **time.Time
+		end  bool
+	}{{"from", &f.From, false}, {"to", &f.To, true}} {
+		v := c.Query(b.name)
+		if v == "" {
+			continue
+		}
+		t, err := parseRangeBound(b.name, v, b.end)
+		if err != nil {
+			return f, err
+		}
+		*b.dst = &t
+	}
+	for _, b := range []struct {
+		name string
+		dst  **models.Money
+	}{{"min_amount", &f.MinAmount}, {"max_amount", &f.MaxAmount}} {
+		if v := c.Query(b.name); v != "" {
+			m, err := models.ParseMoney(v)
+			if err != nil {
+				return f, errors.New(b.name + " must be a decimal")
+			}
+			*b.dst = &m
+		}
+	}
+	if v := c.Query("counterparty"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			return f, errors.New("invalid counterparty")
+		}
+		f.Counterparty = id
+	}
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+	case "asc":
+		f.Ascending = true
+	default:
+		return f, errors.New("order must be asc or desc")
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return f, errors.New("invalid limit")
+		}
+		f.Limit = n
+	}
+	if v := c.Query("cursor"); v != "" {
+		cur, err := models.ParseTxCursor(v)
+		if err != nil {
+			return f, err
+		}
+		f.Cursor = cur
+	}
+	return f, nil
+}
+
+// parseRangeBound: RFC3339 ya da YYYY-MM-DD; sadece tarih verilirse başlangıç günün başı, bitiş (end) ertesi günün başıdır
+func parseRangeBound(name, v string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	d, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + name + "; use RFC3339 or YYYY-MM-DD")
+	}
+	if end {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d, nil
 }
 
 // GET /transactions/:id
@@ -94,3 +253,81 @@ func GetTransactionHandler(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, tx)
 }
+
+// PATCH /transactions/:id — işlemi başlatan kullanıcı memo, kategori ve etiketleri düzenler
+func UpdateTransactionMetaHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+	var patch models.TxMetaPatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tx, err := services.UpdateTransactionMeta(c.GetInt("user_id"), id, patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTransactionForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case err.Error() == "transaction not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, tx)
+}
+
+// ReverseRequest: iade/geri alma isteği; Amount yalnızca kısmi iadede kullanılır
+type ReverseRequest struct {
+	Amount models.Money `json:"amount"`
+	Reason string       `json:"reason"`
+}
+
+// POST /transactions/:id/reverse (admin)
+func ReverseTransactionHandler(c *gin.Context) {
+	compensateHandler(c, false)
+}
+
+// POST /transactions/:id/refund (admin) — kısmi iade
+func RefundTransactionHandler(c *gin.Context) {
+	compensateHandler(c, true)
+}
+
+func compensateHandler(c *gin.Context, partial bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+	var req ReverseRequest
+	// reverse için gövde opsiyoneldir
+	if c.Request.ContentLength != 0 || partial {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	actorID := c.GetInt("user_id")
+	var rec, orig *models.Transaction
+	if partial {
+		rec, orig, err = services.RefundTransaction(id, req.Amount, req.Reason, actorID)
+	} else {
+		rec, orig, err = services.ReverseTransaction(id, req.Reason, actorID)
+	}
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case "transaction already reversed":
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": rec.Type + " completed", "transaction": rec, "original": orig})
+}
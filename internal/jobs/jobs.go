@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Periyodik arka plan işleri (janitor, sweeper, scheduler vb.)
+var wg sync.WaitGroup
+
+// Every: fn'i interval aralıklarla ctx iptal edilene kadar çalıştırır.
+// Bir çalışmanın hatası sonraki çalışmaları durdurmaz; panic'ler yakalanıp loglanır.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		slog.Warn("jobs.disabled", "job", name)
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		slog.Info("jobs.started", "job", name, "interval", interval)
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("jobs.stopped", "job", name)
+				return
+			case <-t.C:
+				run(ctx, name, fn)
+			}
+		}
+	}()
+}
+
+func run(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("jobs.panic", "job", name, "panic", rec)
+		}
+	}()
+	start := time.Now()
+	if err := fn(ctx); err != nil {
+		slog.Error("jobs.failed", "job", name, "err", err, "duration", time.Since(start))
+	}
+}
+
+// Wait: iptal edilen işlerin bitmesini bekler (graceful shutdown)
+func Wait() {
+	wg.Wait()
+}
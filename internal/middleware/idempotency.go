@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"insider-go-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyHeader: para hareketi yapan uç noktalarda tekrar korumalı istek anahtarı
+const IdempotencyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLen = 255
+
+// responseRecorder: yanıt gövdesini istemciye yazarken bir kopyasını da tutar
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency: Idempotency-Key başlığı varsa isteği kullanıcı başına tekilleştirir.
+// AuthMiddleware'den sonra çalışmalıdır (user_id gerekli).
+// - Aynı anahtar + aynı gövde: saklanan orijinal yanıt döner (Idempotent-Replayed: true)
+// - Aynı anahtar + farklı gövde: 422
+// - Orijinal istek sürerken tekrar: 409
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key too long"})
+			return
+		}
+		userID := c.GetInt("user_id")
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "cannot read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec, replay, err := services.BeginIdempotent(userID, key, c.Request.Method, c.Request.URL.Path, body)
+		switch {
+		case errors.Is(err, services.ErrIdempotencyMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrIdempotencyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "idempotency check failed"})
+			return
+		}
+		if replay {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(rec.StatusCode, rec.ContentType, []byte(rec.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		rw := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = rw
+		handled := false
+		defer func() {
+			// panic ya da 5xx: anahtarı bırak ki istemci güvenle yeniden denesin
+			if !handled {
+				_ = services.ReleaseIdempotent(userID, key)
+			}
+		}()
+
+		c.Next()
+
+		status := rw.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		// istek işlendi: yanıt saklanamasa bile anahtar bırakılmaz (tekrar para hareketi olmasın); tekrarlar TTL dolana kadar 409 alır
+		handled = true
+		_ = services.CompleteIdempotent(userID, key, status, rw.Header().Get("Content-Type"), rw.body.Bytes())
+	}
+}
@@ -6,9 +6,57 @@ import (
 )
 
 type Balance struct {
-	UserID      int       `gorm:"column:user_id;primaryKey;autoIncrement:false;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" db:"user_id" json:"user_id"`
-	Amount      float64   `gorm:"column:amount;type:numeric(18,2);default:0" db:"amount" json:"amount"`
-	LastUpdated time.Time `gorm:"column:last_updated_at;autoUpdateTime" db:"last_updated_at" json:"last_updated_at"`
+	UserID   int    `gorm:"column:user_id;primaryKey;autoIncrement:false;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" db:"user_id" json:"user_id"`
+	Currency string `gorm:"column:currency;primaryKey;type:char(3);default:USD" db:"currency" json:"currency"`
+	Amount   Money  `gorm:"column:amount;type:numeric(20,4);default:0" db:"amount" json:"amount"`
+	Held     Money  `gorm:"column:held;type:numeric(20,4);default:0" db:"held" json:"held"` // aktif hold'ların toplamı
+	// CreditLimit: admin onaylı kredili hesap (overdraft) limiti; bakiye -CreditLimit'e kadar inebilir
+	CreditLimit Money `gorm:"column:credit_limit;type:numeric(20,4);default:0" db:"credit_limit" json:"credit_limit"`
+	// OverdraftRateBps: yıllık overdraft faizi (baz puan); boşsa OVERDRAFT_RATE_BPS
+	OverdraftRateBps *int      `gorm:"column:overdraft_rate_bps" db:"overdraft_rate_bps" json:"overdraft_rate_bps,omitempty"`
+	LastUpdated      time.Time `gorm:"column:last_updated_at;autoUpdateTime" db:"last_updated_at" json:"last_updated_at"`
+}
+
+// Available: harcanabilir bakiye (ledger bakiyesi - aktif hold'lar + kredi limiti)
+func (b *Balance) Available() Money { return b.Amount - b.Held + b.CreditLimit }
+
+// OverdraftUsed: kullanılan kredi (negatif bakiyenin mutlak değeri)
+func (b *Balance) OverdraftUsed() Money {
+	if b.Amount.IsNegative() {
+		return b.Amount.Neg()
+	}
+	return 0
+}
+
+// DailyOverdraftInterest: kullanılan kredi üzerinden bir günlük faiz (gerçek/365), minor unit'e yarım-yukarı yuvarlanır
+func (b *Balance) DailyOverdraftInterest(defaultRateBps int, cur Currency) Money {
+	rate := defaultRateBps
+	if b.OverdraftRateBps != nil {
+		rate = *b.OverdraftRateBps
+	}
+	used := b.OverdraftUsed()
+	if used.IsZero() || rate <= 0 {
+		return 0
+	}
+	return used.Prorate(Money(rate), Money(10000*365), cur)
+}
+
+// BalanceSummary: cüzdan özeti; ledger = kayıtlı bakiye, available = harcanabilir bakiye
+type BalanceSummary struct {
+	Currency      string    `json:"currency"`
+	Ledger        Money     `json:"ledger"`
+	Held          Money     `json:"held"`
+	Available     Money     `json:"available"`
+	CreditLimit   Money     `json:"credit_limit"`
+	OverdraftUsed Money     `json:"overdraft_used"`
+	LastUpdated   time.Time `json:"last_updated_at"`
+}
+
+func (b *Balance) Summary() BalanceSummary {
+	return BalanceSummary{
+		Currency: b.Currency, Ledger: b.Amount, Held: b.Held, Available: b.Available(),
+		CreditLimit: b.CreditLimit, OverdraftUsed: b.OverdraftUsed(), LastUpdated: b.LastUpdated,
+	}
 }
 
 // JSON helper’ları
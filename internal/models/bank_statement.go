@@ -0,0 +1,169 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Banka ekstresi kaynak biçimleri
+const (
+	BankSourceCamt053 = "camt053"
+	BankSourceCSV     = "csv"
+)
+
+// Banka ekstresi satır durumları
+const (
+	BankLineMatched   = "matched"   // mevcut bir credit işlemiyle eşleşti
+	BankLineUnmatched = "unmatched" // karşılık gelen credit yok; kullanıcı belliyse tek adımda yüklenebilir
+	BankLineException = "exception" // belirsiz ya da yatırım olmayan kalem; elle incelenmeli
+	BankLineCrediting = "crediting" // tek adımlı credit sürüyor
+	BankLineCredited  = "credited"  // eşleşmeyen kalem için credit yapıldı
+)
+
+// Eşleşme yolları
+const (
+	BankMatchReference  = "reference"        // banka referansı işlemin external_ref'i
+	BankMatchUser       = "amount_date_user" // referanstan çözülen kullanıcının tek adayı
+	BankMatchAmountDate = "amount_date"      // tutar ve tarih penceresinde tek aday
+	BankMatchCredit     = "credit"           // tek adımlı credit ile oluşturuldu
+)
+
+// OriginBankStatementLine: eşleşmeyen kalem için yapılan credit'in origin tipi (kalem başına tek credit)
+const OriginBankStatementLine = "bank_statement_line"
+
+// BankStatement: admin tarafından yüklenen banka ekstresi; aynı dosya (FileHash) ikinci kez yüklenemez
+type BankStatement struct {
+	ID         int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	Source     string    `gorm:"column:source" db:"source" json:"source"`
+	Filename   string    `gorm:"column:filename" db:"filename" json:"filename,omitempty"`
+	FileHash   string    `gorm:"column:file_hash;type:char(64);uniqueIndex" db:"file_hash" json:"file_hash"`
+	AccountID  string    `gorm:"column:account_id" db:"account_id" json:"account_id,omitempty"`
+	LineCount  int       `gorm:"column:line_count" db:"line_count" json:"line_count"`
+	UploadedBy int       `gorm:"column:uploaded_by" db:"uploaded_by" json:"uploaded_by"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+}
+
+// BankStatementLine: ekstredeki tek kalem. Amount işaretlidir (yatırım pozitif, çıkış negatif).
+// TransactionID eşleşen ya da tek adımda oluşturulan credit işlemidir; SuggestedUserID referanstan çözülen kullanıcıdır.
+type BankStatementLine struct {
+	ID                  int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	StatementID         int        `gorm:"column:statement_id;index" db:"statement_id" json:"statement_id"`
+	LineNo              int        `gorm:"column:line_no" db:"line_no" json:"line_no"`
+	BookingDate         time.Time  `gorm:"column:booking_date;type:date" db:"booking_date" json:"booking_date"`
+	Amount              Money      `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency            string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Reference           string     `gorm:"column:reference" db:"reference" json:"reference,omitempty"`
+	Description         string     `gorm:"column:description" db:"description" json:"description,omitempty"`
+	CounterpartyName    string     `gorm:"column:counterparty_name" db:"counterparty_name" json:"counterparty_name,omitempty"`
+	CounterpartyAccount string     `gorm:"column:counterparty_account" db:"counterparty_account" json:"counterparty_account,omitempty"`
+	Status              string     `gorm:"column:status;index" db:"status" json:"status"`
+	Reason              string     `gorm:"column:reason" db:"reason" json:"reason,omitempty"`
+	MatchedBy           string     `gorm:"column:matched_by" db:"matched_by" json:"matched_by,omitempty"`
+	SuggestedUserID     *int       `gorm:"column:suggested_user_id" db:"suggested_user_id" json:"suggested_user_id,omitempty"`
+	TransactionID       *int       `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	ResolvedBy          *int       `gorm:"column:resolved_by" db:"resolved_by" json:"resolved_by,omitempty"`
+	ResolvedAt          *time.Time `gorm:"column:resolved_at" db:"resolved_at" json:"resolved_at,omitempty"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// BankReportTotal: durum ve para birimi başına kalem sayısı ve toplam tutar
+type BankReportTotal struct {
+	Status   string `json:"status"`
+	Currency string `json:"currency"`
+	Count    int    `json:"count"`
+	Amount   Money  `json:"amount"`
+}
+
+// BankStatementReport: eşleşmeyen ve istisna kalemlerin raporu
+type BankStatementReport struct {
+	Statement  *BankStatement      `json:"statement"`
+	Totals     []BankReportTotal   `json:"totals"`
+	Unmatched  []BankStatementLine `json:"unmatched"`
+	Exceptions []BankStatementLine `json:"exceptions"`
+}
+
+// BankCSVMapping: banka CSV'sinin sütun eşlemesi (başlık adlarıyla). Tutar tek sütunda (Amount, işaretli)
+// ya da ayrı Credit/Debit sütunlarında verilir; Currency sütunu yoksa DefaultCurrency kullanılır.
+// Boş bırakılan isteğe bağlı sütunlar okunmaz, verilen her sütun dosyada bulunmalıdır.
+type BankCSVMapping struct {
+	Date                string `json:"date"`
+	Amount              string `json:"amount"`
+	Credit              string `json:"credit"`
+	Debit               string `json:"debit"`
+	Currency            string `json:"currency"`
+	DefaultCurrency     string `json:"default_currency"`
+	Reference           string `json:"reference"`
+	Description         string `json:"description"`
+	CounterpartyName    string `json:"counterparty_name"`
+	CounterpartyAccount string `json:"counterparty_account"`
+	DateFormat          string `json:"date_format"` // Go düzeni; varsayılan 2006-01-02
+	Delimiter           string `json:"delimiter"`   // tek karakter; varsayılan virgül
+	DecimalComma        bool   `json:"decimal_comma"`
+}
+
+// Normalize: varsayılanları doldurur ve eşlemenin kullanılabilir olduğunu doğrular
+func (m *BankCSVMapping) Normalize() error {
+	if m.Date == "" {
+		m.Date = "date"
+	}
+	if m.Amount == "" && m.Credit == "" && m.Debit == "" {
+		m.Amount = "amount"
+	}
+	if m.DateFormat == "" {
+		m.DateFormat = "2006-01-02"
+	}
+	if m.Delimiter == "" {
+		m.Delimiter = ","
+	}
+	if len([]rune(m.Delimiter)) != 1 {
+		return errors.New("delimiter must be a single character")
+	}
+	if m.Amount != "" && (m.Credit != "" || m.Debit != "") {
+		return errors.New("use either amount or credit/debit columns")
+	}
+	if m.Currency == "" && m.DefaultCurrency == "" {
+		return errors.New("currency column or default_currency required")
+	}
+	return nil
+}
+
+// ParseAmount: sütundaki tutarı okur; binlik ayırıcıları atar, DecimalComma ise virgülü ondalık kabul eder
+func (m BankCSVMapping) ParseAmount(v string) (Money, error) {
+	v = strings.ReplaceAll(strings.TrimSpace(v), " ", "")
+	if m.DecimalComma {
+		v = strings.ReplaceAll(v, ".", "")
+		v = strings.ReplaceAll(v, ",", ".")
+	} else {
+		v = strings.ReplaceAll(v, ",", "")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	return ParseMoney(v)
+}
+
+// WalletAccountID: cüzdanın dış sistemlerde kullanılan hesap kimliği (ör. "42-USD"); dışa aktarımlarda ve
+// banka havalesi açıklamalarında kullanıcıyı tanımlar
+func WalletAccountID(userID int, currency string) string {
+	return fmt.Sprintf("%d-%s", userID, currency)
+}
+
+var walletAccountPattern = regexp.MustCompile(`\b(\d+)-([A-Za-z]{3})\b`)
+
+// FindWalletAccountID: metindeki ilk cüzdan hesap kimliğini çözer
+func FindWalletAccountID(s string) (userID int, currency string, ok bool) {
+	m := walletAccountPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", false
+	}
+	id, err := strconv.Atoi(m[1])
+	if err != nil || id <= 0 {
+		return 0, "", false
+	}
+	return id, strings.ToUpper(m[2]), true
+}
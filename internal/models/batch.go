@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Toplu işlem modları
+const (
+	BatchAllOrNothing = "all_or_nothing" // tüm kalemler tek DB transaction'ında; biri başarısızsa hiçbiri yazılmaz
+	BatchBestEffort   = "best_effort"    // her kalem kendi DB transaction'ında, ayrı durumla
+)
+
+// Toplu işlem durumları
+const (
+	BatchProcessing         = "processing"
+	BatchCompleted          = "completed"
+	BatchPartiallyCompleted = "partially_completed"
+	BatchFailed             = "failed"
+)
+
+// Kalem durumları; rolled_back: all_or_nothing modunda başka bir kalem yüzünden geri alınan kalem
+const (
+	BatchItemPending    = "pending"
+	BatchItemSucceeded  = "succeeded"
+	BatchItemFailed     = "failed"
+	BatchItemRolledBack = "rolled_back"
+)
+
+// OriginBatch: toplu işlem kalemlerinin ürettiği işlemlerin origin tipi (seq = kalem sırası)
+const OriginBatch = "batch"
+
+// Batch: tek istekte gönderilen credit/debit/transfer kalemleri
+type Batch struct {
+	ID             int         `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	UserID         int         `gorm:"column:user_id;index" db:"user_id" json:"user_id"`
+	Mode           string      `gorm:"column:mode" db:"mode" json:"mode"`
+	Status         string      `gorm:"column:status;index" db:"status" json:"status"`
+	ItemCount      int         `gorm:"column:item_count" db:"item_count" json:"item_count"`
+	SucceededCount int         `gorm:"column:succeeded_count;default:0" db:"succeeded_count" json:"succeeded_count"`
+	FailedCount    int         `gorm:"column:failed_count;default:0" db:"failed_count" json:"failed_count"`
+	CreatedAt      time.Time   `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	CompletedAt    *time.Time  `gorm:"column:completed_at" db:"completed_at" json:"completed_at,omitempty"`
+	Items          []BatchItem `gorm:"-" json:"items,omitempty"`
+}
+
+// BatchItem: toplu işlemin tek kalemi; kaynak cüzdan her zaman batch sahibinindir
+type BatchItem struct {
+	ID            int    `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	BatchID       int    `gorm:"column:batch_id;index" db:"batch_id" json:"batch_id"`
+	Seq           int    `gorm:"column:seq" db:"seq" json:"seq"`
+	Type          string `gorm:"column:type" db:"type" json:"type"` // credit | debit | transfer
+	ToUserID      *int   `gorm:"column:to_user_id" db:"to_user_id" json:"to_user_id,omitempty"`
+	Amount        Money  `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency      string `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Status        string `gorm:"column:status" db:"status" json:"status"`
+	TransactionID *int   `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	Error         string `gorm:"column:error" db:"error" json:"error,omitempty"`
+}
+
+// Tally: kalem durumlarından sayaçları ve batch durumunu hesaplar
+func (b *Batch) Tally() {
+	b.SucceededCount, b.FailedCount = 0, 0
+	for _, it := range b.Items {
+		switch it.Status {
+		case BatchItemSucceeded:
+			b.SucceededCount++
+		case BatchItemFailed, BatchItemRolledBack:
+			b.FailedCount++
+		}
+	}
+	switch {
+	case b.FailedCount == 0:
+		b.Status = BatchCompleted
+	case b.SucceededCount == 0:
+		b.Status = BatchFailed
+	default:
+		b.Status = BatchPartiallyCompleted
+	}
+}
+
+// JSON helper’ları
+func (b *Batch) ToJSON() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b *Batch) FromJSON(data []byte) error {
+	return json.Unmarshal(data, b)
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// ISO 20022 camt.053 (BankToCustomerStatement) öğeleri; yalnızca kullanılan alt küme tanımlıdır
+
+const CamtNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camt.053 kodları
+const (
+	CamtCredit        = "CRDT"
+	CamtDebit         = "DBIT"
+	CamtOpeningBooked = "OPBD"
+	CamtClosingBooked = "CLBD"
+	CamtBooked        = "BOOK"
+)
+
+type CamtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type CamtDate struct {
+	Dt   string `xml:"Dt,omitempty"`
+	DtTm string `xml:"DtTm,omitempty"`
+}
+
+type CamtBalanceType struct {
+	Cd string `xml:"CdOrPrtry>Cd"`
+}
+
+type CamtBalance struct {
+	XMLName   xml.Name        `xml:"Bal"`
+	Tp        CamtBalanceType `xml:"Tp"`
+	Amt       CamtAmount      `xml:"Amt"`
+	CdtDbtInd string          `xml:"CdtDbtInd"`
+	Dt        CamtDate        `xml:"Dt"`
+}
+
+type CamtBankTxCode struct {
+	Prtry struct {
+		Cd   string `xml:"Cd"`
+		Issr string `xml:"Issr,omitempty"`
+	} `xml:"Prtry"`
+}
+
+type CamtRefs struct {
+	AcctSvcrRef string `xml:"AcctSvcrRef,omitempty"`
+	EndToEndId  string `xml:"EndToEndId,omitempty"`
+}
+
+type CamtRemittance struct {
+	Ustrd []string `xml:"Ustrd"`
+}
+
+// CamtAccountID: IBAN ya da diğer hesap kimliği
+type CamtAccountID struct {
+	IBAN string `xml:"IBAN,omitempty"`
+	Othr *struct {
+		Id string `xml:"Id"`
+	} `xml:"Othr,omitempty"`
+}
+
+// String: IBAN, yoksa diğer kimlik
+func (a CamtAccountID) String() string {
+	if a.IBAN != "" {
+		return a.IBAN
+	}
+	if a.Othr != nil {
+		return a.Othr.Id
+	}
+	return ""
+}
+
+type CamtParty struct {
+	Nm string `xml:"Nm,omitempty"`
+}
+
+type CamtRelatedParties struct {
+	Dbtr     *CamtParty `xml:"Dbtr,omitempty"`
+	DbtrAcct *struct {
+		Id CamtAccountID `xml:"Id"`
+	} `xml:"DbtrAcct,omitempty"`
+	Cdtr     *CamtParty `xml:"Cdtr,omitempty"`
+	CdtrAcct *struct {
+		Id CamtAccountID `xml:"Id"`
+	} `xml:"CdtrAcct,omitempty"`
+}
+
+type CamtTxDetails struct {
+	Refs      *CamtRefs           `xml:"Refs,omitempty"`
+	RltdPties *CamtRelatedParties `xml:"RltdPties,omitempty"`
+	RmtInf    *CamtRemittance     `xml:"RmtInf,omitempty"`
+}
+
+type CamtEntryDetails struct {
+	TxDtls []CamtTxDetails `xml:"TxDtls"`
+}
+
+// CamtEntry: ekstre kalemi (Ntry); Amt her zaman pozitiftir, yön CdtDbtInd ile verilir
+type CamtEntry struct {
+	XMLName      xml.Name           `xml:"Ntry"`
+	NtryRef      string             `xml:"NtryRef,omitempty"`
+	Amt          CamtAmount         `xml:"Amt"`
+	CdtDbtInd    string             `xml:"CdtDbtInd"`
+	Sts          string             `xml:"Sts"`
+	BookgDt      CamtDate           `xml:"BookgDt"`
+	ValDt        *CamtDate          `xml:"ValDt,omitempty"`
+	AcctSvcrRef  string             `xml:"AcctSvcrRef,omitempty"`
+	BkTxCd       *CamtBankTxCode    `xml:"BkTxCd,omitempty"`
+	NtryDtls     []CamtEntryDetails `xml:"NtryDtls,omitempty"`
+	AddtlNtryInf string             `xml:"AddtlNtryInf,omitempty"`
+}
+
+// CamtStatement: okunan ekstre (Stmt); yalnızca hesap ve kalemler kullanılır
+type CamtStatement struct {
+	Id   string `xml:"Id"`
+	Acct struct {
+		Id  CamtAccountID `xml:"Id"`
+		Ccy string        `xml:"Ccy"`
+	} `xml:"Acct"`
+	Bal  []CamtBalance `xml:"Bal"`
+	Ntry []CamtEntry   `xml:"Ntry"`
+}
+
+// CamtDocument: camt.053 belgesi; etiketler ad alanı içermediğinden 001.02 ve sonraki sürümler okunur
+type CamtDocument struct {
+	XMLName    xml.Name        `xml:"Document"`
+	Statements []CamtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// Reference: kalemin eşleştirmede kullanılan referansı (EndToEndId, yoksa NtryRef / AcctSvcrRef)
+func (e CamtEntry) Reference() string {
+	for _, d := range e.NtryDtls {
+		for _, tx := range d.TxDtls {
+			if tx.Refs != nil && tx.Refs.EndToEndId != "" && tx.Refs.EndToEndId != "NOTPROVIDED" {
+				return tx.Refs.EndToEndId
+			}
+		}
+	}
+	if e.NtryRef != "" {
+		return e.NtryRef
+	}
+	return e.AcctSvcrRef
+}
+
+// Details: yapılandırılmamış açıklamalar ve ilk işlem detayının karşı tarafı (alacak kaleminde borçlu, borç kaleminde alacaklı)
+func (e CamtEntry) Details() (description, name, account string) {
+	var parts []string
+	for _, d := range e.NtryDtls {
+		for _, tx := range d.TxDtls {
+			if tx.RmtInf != nil {
+				parts = append(parts, tx.RmtInf.Ustrd...)
+			}
+			if p := tx.RltdPties; p != nil && name == "" && account == "" {
+				if e.CdtDbtInd == CamtCredit {
+					if p.Dbtr != nil {
+						name = p.Dbtr.Nm
+					}
+					if p.DbtrAcct != nil {
+						account = p.DbtrAcct.Id.String()
+					}
+				} else {
+					if p.Cdtr != nil {
+						name = p.Cdtr.Nm
+					}
+					if p.CdtrAcct != nil {
+						account = p.CdtrAcct.Id.String()
+					}
+				}
+			}
+		}
+	}
+	if e.AddtlNtryInf != "" {
+		parts = append(parts, e.AddtlNtryInf)
+	}
+	return strings.Join(parts, " "), name, account
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BalanceCheckpoint: cüzdanın AsOf anındaki kapanış bakiyesi (created_at < AsOf olan tüm ledger kayıtları).
+// Geçmiş bakiye sorguları en yakın checkpoint'ten başlayıp yalnızca sonraki kayıtları toplar.
+type BalanceCheckpoint struct {
+	UserID    int       `gorm:"column:user_id;primaryKey;autoIncrement:false" db:"user_id" json:"user_id"`
+	Currency  string    `gorm:"column:currency;primaryKey;type:char(3)" db:"currency" json:"currency"`
+	AsOf      time.Time `gorm:"column:as_of;primaryKey" db:"as_of" json:"as_of"`
+	Balance   Money     `gorm:"column:balance;type:numeric(20,4)" db:"balance" json:"balance"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+}
+
+// JSON helper’ları
+func (c *BalanceCheckpoint) ToJSON() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *BalanceCheckpoint) FromJSON(data []byte) error {
+	return json.Unmarshal(data, c)
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Currency: ISO 4217 para birimi ve minor unit (ondalık basamak) kuralı
+type Currency struct {
+	Code     string `json:"code"`
+	Exponent int    `json:"exponent"`
+}
+
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// desteklenen para birimleri (ISO 4217 minor unit değerleri)
+var currencies = map[string]Currency{
+	"TRY": {Code: "TRY", Exponent: 2},
+	"USD": {Code: "USD", Exponent: 2},
+	"EUR": {Code: "EUR", Exponent: 2},
+	"GBP": {Code: "GBP", Exponent: 2},
+	"CHF": {Code: "CHF", Exponent: 2},
+	"CAD": {Code: "CAD", Exponent: 2},
+	"AUD": {Code: "AUD", Exponent: 2},
+	"SEK": {Code: "SEK", Exponent: 2},
+	"NOK": {Code: "NOK", Exponent: 2},
+	"DKK": {Code: "DKK", Exponent: 2},
+	"PLN": {Code: "PLN", Exponent: 2},
+	"AED": {Code: "AED", Exponent: 2},
+	"SAR": {Code: "SAR", Exponent: 2},
+	"CNY": {Code: "CNY", Exponent: 2},
+	"INR": {Code: "INR", Exponent: 2},
+	"JPY": {Code: "JPY", Exponent: 0},
+	"KRW": {Code: "KRW", Exponent: 0},
+	"HUF": {Code: "HUF", Exponent: 2},
+	"BHD": {Code: "BHD", Exponent: 3},
+	"KWD": {Code: "KWD", Exponent: 3},
+	"OMR": {Code: "OMR", Exponent: 3},
+	"JOD": {Code: "JOD", Exponent: 3},
+	"TND": {Code: "TND", Exponent: 3},
+}
+
+// LookupCurrency: kodu normalize eder (büyük harf) ve kayıtlı para birimini döner
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnsupportedCurrency, code)
+	}
+	return c, nil
+}
+
+// CheckPrecision: tutarın para biriminin minor unit kuralına uyduğunu doğrular (ör. JPY küsurat almaz)
+func (c Currency) CheckPrecision(m Money) error {
+	if c.Exponent >= MoneyScale {
+		return nil
+	}
+	if int64(m)%pow10(MoneyScale-c.Exponent) != 0 {
+		return fmt.Errorf("%s amounts support at most %d decimal places", c.Code, c.Exponent)
+	}
+	return nil
+}
+
+// Format: tutarı para biriminin basamak sayısıyla yazar (ör. JPY "1500", KWD "1.250")
+func (c Currency) Format(m Money) string {
+	return m.StringFixed(c.Exponent)
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AccountEscrow: escrow'daki fonların tutulduğu house hesabı
+const AccountEscrow = "system:escrow"
+
+// Escrow işlem tipleri: alıcıdan escrow'a, escrow'dan satıcıya ya da alıcıya geri
+const (
+	TxTypeEscrowFund    = "escrow_fund"
+	TxTypeEscrowRelease = "escrow_release"
+	TxTypeEscrowRefund  = "escrow_refund"
+)
+
+// Escrow durumları
+const (
+	EscrowFunded   = "funded"
+	EscrowDisputed = "disputed" // taraflar farklı sonuç onayladı; yalnızca admin karara bağlar
+	EscrowReleased = "released"
+	EscrowRefunded = "refunded"
+)
+
+// Escrow sonuçları (taraf onayı, admin kararı ya da süre dolumu)
+const (
+	EscrowOutcomeRelease = "release"
+	EscrowOutcomeRefund  = "refund"
+)
+
+// Escrow kapanış yolları
+const (
+	EscrowResolutionMutual      = "mutual"
+	EscrowResolutionArbitration = "arbitration"
+	EscrowResolutionTimeout     = "timeout"
+)
+
+// OriginEscrow: escrow bacaklarının origin tipi (seq 0 = fonlama, 1 = kapanış); escrow başına tek kapanış garanti edilir
+const OriginEscrow = "escrow"
+
+// Escrow: alıcının satıcıya ödemesinin koşullu olarak system:escrow hesabında tutulması.
+// Her iki taraf aynı sonucu onaylarsa, admin karar verirse ya da ExpiresAt'te TimeoutOutcome ile kapanır.
+type Escrow struct {
+	ID                  int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	BuyerID             int        `gorm:"column:buyer_id;index" db:"buyer_id" json:"buyer_id"`
+	SellerID            int        `gorm:"column:seller_id;index" db:"seller_id" json:"seller_id"`
+	Amount              Money      `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency            string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Description         string     `gorm:"column:description" db:"description" json:"description,omitempty"`
+	Status              string     `gorm:"column:status;index" db:"status" json:"status"`
+	BuyerDecision       string     `gorm:"column:buyer_decision" db:"buyer_decision" json:"buyer_decision,omitempty"`
+	SellerDecision      string     `gorm:"column:seller_decision" db:"seller_decision" json:"seller_decision,omitempty"`
+	TimeoutOutcome      string     `gorm:"column:timeout_outcome" db:"timeout_outcome" json:"timeout_outcome"`
+	ExpiresAt           time.Time  `gorm:"column:expires_at" db:"expires_at" json:"expires_at"`
+	FundTransactionID   *int       `gorm:"column:fund_transaction_id" db:"fund_transaction_id" json:"fund_transaction_id,omitempty"`
+	SettleTransactionID *int       `gorm:"column:settle_transaction_id" db:"settle_transaction_id" json:"settle_transaction_id,omitempty"`
+	Resolution          string     `gorm:"column:resolution" db:"resolution" json:"resolution,omitempty"`
+	ResolvedBy          *int       `gorm:"column:resolved_by" db:"resolved_by" json:"resolved_by,omitempty"`
+	ResolvedAt          *time.Time `gorm:"column:resolved_at" db:"resolved_at" json:"resolved_at,omitempty"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// IsValidEscrowOutcome: release ya da refund mı
+func IsValidEscrowOutcome(outcome string) bool {
+	return outcome == EscrowOutcomeRelease || outcome == EscrowOutcomeRefund
+}
+
+// Open: henüz kapanmamış mı (funded ya da disputed)
+func (e *Escrow) Open() bool {
+	return e.Status == EscrowFunded || e.Status == EscrowDisputed
+}
+
+// JSON helper’ları
+func (e *Escrow) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *Escrow) FromJSON(data []byte) error {
+	return json.Unmarshal(data, e)
+}
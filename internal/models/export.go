@@ -0,0 +1,399 @@
+package models
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Dışa aktarma biçimleri
+const (
+	ExportCSV     = "csv"
+	ExportOFX     = "ofx"
+	ExportCamt053 = "camt053"
+)
+
+// ExportLine: dışa aktarılan hareket; ekstre satırına ek olarak işlemin memo ve external_ref alanlarını taşır
+type ExportLine struct {
+	StatementLine
+	Memo        string  `gorm:"column:memo"`
+	ExternalRef *string `gorm:"column:external_ref"`
+}
+
+// ExportHeader: [From, To) aralığının hesabı ve açılış/kapanış bakiyeleri; satırlar akmadan önce bilinir
+type ExportHeader struct {
+	UserID      int
+	Currency    Currency
+	AccountID   string
+	BankID      string
+	BankName    string
+	From        time.Time
+	To          time.Time
+	Opening     Money
+	Closing     Money
+	GeneratedAt time.Time
+}
+
+// ExportWriter: hareketleri sırayla yazan akış; Begin bir kez, Line her satır için, End en sonda çağrılır
+type ExportWriter interface {
+	ContentType() string
+	Extension() string
+	Begin(h ExportHeader) error
+	Line(l ExportLine) error
+	End() error
+}
+
+// NewExportWriter: biçime göre yazıcı; bilinmeyen biçimde hata döner
+func NewExportWriter(format string, w io.Writer) (ExportWriter, error) {
+	switch format {
+	case ExportCSV:
+		return &csvExport{w: csv.NewWriter(w)}, nil
+	case ExportOFX:
+		return &ofxExport{xmlExport: newXMLExport(w)}, nil
+	case ExportCamt053:
+		return &camtExport{xmlExport: newXMLExport(w)}, nil
+	}
+	return nil, errors.New("format must be csv, ofx or camt053")
+}
+
+// csvExport: ilk ve son satırlar açılış/kapanış bakiyesidir, her hareket yürüyen bakiyeyle yazılır
+type csvExport struct {
+	w *csv.Writer
+	h ExportHeader
+}
+
+func (e *csvExport) ContentType() string { return "text/csv" }
+func (e *csvExport) Extension() string   { return "csv" }
+
+func (e *csvExport) Begin(h ExportHeader) error {
+	e.h = h
+	_ = e.w.Write([]string{"posted_at", "transaction_id", "type", "counterparty_id", "amount", "balance", "currency", "memo", "external_ref"})
+	return e.write([]string{h.From.UTC().Format(time.RFC3339), "", "opening_balance", "", "", h.Opening.String(), h.Currency.Code, "", ""})
+}
+
+func (e *csvExport) Line(l ExportLine) error {
+	cp, ref := "", ""
+	if l.CounterpartyID != nil {
+		cp = strconv.Itoa(*l.CounterpartyID)
+	}
+	if l.ExternalRef != nil {
+		ref = *l.ExternalRef
+	}
+	return e.write([]string{l.PostedAt.UTC().Format(time.RFC3339), strconv.Itoa(l.TransactionID), l.Type, cp, l.Amount.String(), l.Balance.String(), e.h.Currency.Code, l.Memo, ref})
+}
+
+func (e *csvExport) End() error {
+	if err := e.write([]string{e.h.To.UTC().Format(time.RFC3339), "", "closing_balance", "", "", e.h.Closing.String(), e.h.Currency.Code, "", ""}); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExport) write(row []string) error {
+	if err := e.w.Write(row); err != nil {
+		return err
+	}
+	return e.w.Error()
+}
+
+// xmlExport: XML biçimleri için açık öğe yığını tutan akış yazıcısı
+type xmlExport struct {
+	buf  *bufio.Writer
+	enc  *xml.Encoder
+	open []string
+	h    ExportHeader
+}
+
+func newXMLExport(w io.Writer) xmlExport {
+	buf := bufio.NewWriter(w)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	return xmlExport{buf: buf, enc: enc}
+}
+
+func (x *xmlExport) ContentType() string { return "application/xml" }
+
+func (x *xmlExport) procInst(target, inst string) error {
+	if err := x.enc.EncodeToken(xml.ProcInst{Target: target, Inst: []byte(inst)}); err != nil {
+		return err
+	}
+	// OFX okuyucuları başlıkları ayrı satırda bekler
+	return x.enc.EncodeToken(xml.CharData("\n"))
+}
+
+func (x *xmlExport) start(name string, attrs ...xml.Attr) error {
+	x.open = append(x.open, name)
+	return x.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (x *xmlExport) end() error {
+	name := x.open[len(x.open)-1]
+	x.open = x.open[:len(x.open)-1]
+	return x.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// leaf: metin içerikli tek öğe
+func (x *xmlExport) leaf(name, text string) error {
+	return x.enc.EncodeElement(text, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// closeAll: açık öğeleri kapatır ve tamponu boşaltır
+func (x *xmlExport) closeAll() error {
+	for len(x.open) > 0 {
+		if err := x.end(); err != nil {
+			return err
+		}
+	}
+	if err := x.enc.Flush(); err != nil {
+		return err
+	}
+	return x.buf.Flush()
+}
+
+// amount: para biriminin basamak sayısında, yuvarlanmış tutar
+func (x *xmlExport) amount(m Money) string {
+	return x.h.Currency.Format(m.Round(x.h.Currency))
+}
+
+// ofxExport: OFX 2.2 banka ekstresi (STMTRS). Açılış bakiyesi BALLIST'te, kapanış LEDGERBAL'dadır;
+// OFX'te hareket başına bakiye alanı olmadığından yürüyen bakiye CSV ve camt.053 çıktılarında yer alır.
+type ofxExport struct{ xmlExport }
+
+func (e *ofxExport) Extension() string { return "ofx" }
+
+// ofxTime: OFX tarih biçimi (YYYYMMDDHHMMSS.XXX[+0:UTC])
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:UTC]"
+}
+
+// ofxTrnType: işlem tipinin OFX karşılığı; eşlenmeyen tipler yöne göre CREDIT/DEBIT olur
+func ofxTrnType(l ExportLine) string {
+	switch l.Type {
+	case "transfer", TxTypeSplit, TxTypeSplitPart, TxTypeEscrowFund, TxTypeEscrowRelease, TxTypeEscrowRefund:
+		return "XFER"
+	case TxTypeFee:
+		return "FEE"
+	case TxTypeInterest, TxTypeOverdraftInterest:
+		return "INT"
+	}
+	if l.Amount.IsNegative() {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+func (e *ofxExport) Begin(h ExportHeader) error {
+	e.h = h
+	if err := e.procInst("xml", `version="1.0" encoding="UTF-8" standalone="no"`); err != nil {
+		return err
+	}
+	if err := e.procInst("OFX", `OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"`); err != nil {
+		return err
+	}
+	steps := []func() error{
+		func() error { return e.start("OFX") },
+		func() error { return e.start("SIGNONMSGSRSV1") },
+		func() error { return e.start("SONRS") },
+		e.status,
+		func() error { return e.leaf("DTSERVER", ofxTime(h.GeneratedAt)) },
+		func() error { return e.leaf("LANGUAGE", "ENG") },
+		e.end, e.end,
+		func() error { return e.start("BANKMSGSRSV1") },
+		func() error { return e.start("STMTTRNRS") },
+		func() error { return e.leaf("TRNUID", "0") },
+		e.status,
+		func() error { return e.start("STMTRS") },
+		func() error { return e.leaf("CURDEF", h.Currency.Code) },
+		func() error { return e.start("BANKACCTFROM") },
+		func() error { return e.leaf("BANKID", h.BankID) },
+		func() error { return e.leaf("ACCTID", h.AccountID) },
+		func() error { return e.leaf("ACCTTYPE", "CHECKING") },
+		e.end,
+		func() error { return e.start("BANKTRANLIST") },
+		func() error { return e.leaf("DTSTART", ofxTime(h.From)) },
+		func() error { return e.leaf("DTEND", ofxTime(h.To)) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ofxExport) status() error {
+	if err := e.start("STATUS"); err != nil {
+		return err
+	}
+	if err := e.leaf("CODE", "0"); err != nil {
+		return err
+	}
+	if err := e.leaf("SEVERITY", "INFO"); err != nil {
+		return err
+	}
+	return e.end()
+}
+
+type ofxStmtTrn struct {
+	XMLName  xml.Name `xml:"STMTTRN"`
+	TrnType  string   `xml:"TRNTYPE"`
+	DtPosted string   `xml:"DTPOSTED"`
+	TrnAmt   string   `xml:"TRNAMT"`
+	FITID    string   `xml:"FITID"`
+	RefNum   string   `xml:"REFNUM,omitempty"`
+	Name     string   `xml:"NAME"`
+	Memo     string   `xml:"MEMO,omitempty"`
+}
+
+func (e *ofxExport) Line(l ExportLine) error {
+	trn := ofxStmtTrn{
+		TrnType: ofxTrnType(l), DtPosted: ofxTime(l.PostedAt), TrnAmt: e.amount(l.Amount),
+		FITID: strconv.Itoa(l.TransactionID), Name: l.Type, Memo: l.Memo,
+	}
+	if l.CounterpartyID != nil {
+		trn.Name = fmt.Sprintf("User %d", *l.CounterpartyID)
+	}
+	if l.ExternalRef != nil {
+		trn.RefNum = *l.ExternalRef
+	}
+	return e.enc.Encode(trn)
+}
+
+type ofxBal struct {
+	XMLName xml.Name `xml:"BAL"`
+	Name    string   `xml:"NAME"`
+	Desc    string   `xml:"DESC"`
+	BalType string   `xml:"BALTYPE"`
+	Value   string   `xml:"VALUE"`
+	DtAsOf  string   `xml:"DTASOF"`
+}
+
+func (e *ofxExport) End() error {
+	if err := e.end(); err != nil { // BANKTRANLIST
+		return err
+	}
+	if err := e.start("LEDGERBAL"); err != nil {
+		return err
+	}
+	if err := e.leaf("BALAMT", e.amount(e.h.Closing)); err != nil {
+		return err
+	}
+	if err := e.leaf("DTASOF", ofxTime(e.h.To)); err != nil {
+		return err
+	}
+	if err := e.end(); err != nil {
+		return err
+	}
+	if err := e.start("BALLIST"); err != nil {
+		return err
+	}
+	if err := e.enc.Encode(ofxBal{Name: "Opening balance", Desc: "Balance at start of period", BalType: "DOLLAR", Value: e.amount(e.h.Opening), DtAsOf: ofxTime(e.h.From)}); err != nil {
+		return err
+	}
+	return e.closeAll()
+}
+
+// camtExport: ISO 20022 camt.053.001.02 hesap ekstresi; OPBD/CLBD bakiyeleri kalemlerden önce,
+// her kalemin sonrasındaki bakiye AddtlNtryInf içinde yazılır
+type camtExport struct{ xmlExport }
+
+func (e *camtExport) Extension() string { return "xml" }
+
+func camtTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// camtSide: işaretli tutarı pozitif tutar ve CRDT/DBIT yönüne ayırır
+func camtSide(m Money) (Money, string) {
+	if m.IsNegative() {
+		return m.Neg(), CamtDebit
+	}
+	return m, CamtCredit
+}
+
+func (e *camtExport) balance(code string, m Money, at time.Time) error {
+	amt, side := camtSide(m)
+	return e.enc.Encode(CamtBalance{
+		Tp:  CamtBalanceType{Cd: code},
+		Amt: CamtAmount{Ccy: e.h.Currency.Code, Value: e.amount(amt)}, CdtDbtInd: side,
+		Dt: CamtDate{Dt: at.UTC().Format("2006-01-02")},
+	})
+}
+
+func (e *camtExport) Begin(h ExportHeader) error {
+	e.h = h
+	if err := e.procInst("xml", `version="1.0" encoding="UTF-8"`); err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s-%s-%s", h.AccountID, h.From.UTC().Format("20060102"), h.To.UTC().Format("20060102"))
+	created := camtTime(h.GeneratedAt)
+	steps := []func() error{
+		func() error {
+			return e.start("Document", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: CamtNamespace})
+		},
+		func() error { return e.start("BkToCstmrStmt") },
+		func() error { return e.start("GrpHdr") },
+		func() error { return e.leaf("MsgId", id) },
+		func() error { return e.leaf("CreDtTm", created) },
+		e.end,
+		func() error { return e.start("Stmt") },
+		func() error { return e.leaf("Id", id) },
+		func() error { return e.leaf("CreDtTm", created) },
+		func() error { return e.start("FrToDt") },
+		func() error { return e.leaf("FrDtTm", camtTime(h.From)) },
+		func() error { return e.leaf("ToDtTm", camtTime(h.To)) },
+		e.end,
+		func() error { return e.start("Acct") },
+		func() error { return e.start("Id") },
+		func() error { return e.start("Othr") },
+		func() error { return e.leaf("Id", h.AccountID) },
+		e.end, e.end,
+		func() error { return e.leaf("Ccy", h.Currency.Code) },
+		func() error { return e.start("Svcr") },
+		func() error { return e.start("FinInstnId") },
+		func() error { return e.leaf("Nm", h.BankName) },
+		func() error { return e.start("Othr") },
+		func() error { return e.leaf("Id", h.BankID) },
+		e.end, e.end, e.end, e.end,
+		func() error { return e.balance(CamtOpeningBooked, h.Opening, h.From) },
+		func() error { return e.balance(CamtClosingBooked, h.Closing, h.To) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *camtExport) Line(l ExportLine) error {
+	amt, side := camtSide(l.Amount)
+	booked := &CamtDate{DtTm: camtTime(l.PostedAt)}
+	ref := "NOTPROVIDED"
+	if l.ExternalRef != nil {
+		ref = *l.ExternalRef
+	}
+	tx := CamtTxDetails{Refs: &CamtRefs{AcctSvcrRef: strconv.Itoa(l.TransactionID), EndToEndId: ref}}
+	if l.Memo != "" {
+		tx.RmtInf = &CamtRemittance{Ustrd: []string{l.Memo}}
+	}
+	code := &CamtBankTxCode{}
+	code.Prtry.Cd, code.Prtry.Issr = l.Type, e.h.BankID
+	return e.enc.Encode(CamtEntry{
+		NtryRef: strconv.Itoa(l.TransactionID),
+		Amt:     CamtAmount{Ccy: e.h.Currency.Code, Value: e.amount(amt)}, CdtDbtInd: side, Sts: CamtBooked,
+		BookgDt: *booked, ValDt: booked, AcctSvcrRef: strconv.Itoa(l.TransactionID), BkTxCd: code,
+		NtryDtls:     []CamtEntryDetails{{TxDtls: []CamtTxDetails{tx}}},
+		AddtlNtryInf: "Balance after entry: " + e.amount(l.Balance),
+	})
+}
+
+func (e *camtExport) End() error {
+	return e.closeAll()
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AccountFees: tahsil edilen işlem ücretlerinin biriktiği house hesabı
+const AccountFees = "system:fees"
+
+// TxTypeFee: ana işleme (ParentID) bağlı ücret tahsilatı
+const TxTypeFee = "fee"
+
+// Ücret hesaplama türleri
+const (
+	FeeKindFlat    = "flat"    // sabit tutar
+	FeeKindPercent = "percent" // tutarın RateBps kadarı (+ opsiyonel FlatAmount)
+	FeeKindTiered  = "tiered"  // tutarın düştüğü kademenin sabit + oransal ücreti
+)
+
+// FeeTier: kademeli ücret basamağı; UpTo boşsa üst sınırsızdır
+type FeeTier struct {
+	UpTo       *Money `json:"up_to,omitempty"`
+	FlatAmount Money  `json:"flat_amount"`
+	RateBps    int    `json:"rate_bps"`
+}
+
+// FeeTiers: kademeler DB'de JSON olarak saklanır
+type FeeTiers []FeeTier
+
+func (t FeeTiers) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(t)
+	return string(b), err
+}
+
+func (t *FeeTiers) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	}
+	return fmt.Errorf("unsupported fee tiers type %T", src)
+}
+
+// FeeSchedule: işlem tipi, rol ve tutar bandına göre seçilen ücret tarifesi.
+// Role boşsa tüm rollere uygulanır; MinAmount dahil, MaxAmount hariç bandı belirler.
+// Aynı işleme birden çok tarife uyuyorsa role özel olan, sonra Priority'si yüksek olan,
+// sonra en yeni EffectiveFrom'lu olan seçilir.
+type FeeSchedule struct {
+	ID            int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	Name          string     `gorm:"column:name" db:"name" json:"name"`
+	TxType        string     `gorm:"column:tx_type;index" db:"tx_type" json:"tx_type"`
+	Role          *string    `gorm:"column:role" db:"role" json:"role,omitempty"`
+	Currency      string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	MinAmount     *Money     `gorm:"column:min_amount;type:numeric(20,4)" db:"min_amount" json:"min_amount,omitempty"`
+	MaxAmount     *Money     `gorm:"column:max_amount;type:numeric(20,4)" db:"max_amount" json:"max_amount,omitempty"`
+	Kind          string     `gorm:"column:kind" db:"kind" json:"kind"`
+	FlatAmount    Money      `gorm:"column:flat_amount;type:numeric(20,4);default:0" db:"flat_amount" json:"flat_amount"`
+	RateBps       int        `gorm:"column:rate_bps;default:0" db:"rate_bps" json:"rate_bps"`
+	Tiers         FeeTiers   `gorm:"column:tiers;type:text" db:"tiers" json:"tiers,omitempty"`
+	MinFee        *Money     `gorm:"column:min_fee;type:numeric(20,4)" db:"min_fee" json:"min_fee,omitempty"`
+	MaxFee        *Money     `gorm:"column:max_fee;type:numeric(20,4)" db:"max_fee" json:"max_fee,omitempty"`
+	Priority      int        `gorm:"column:priority;default:0" db:"priority" json:"priority"`
+	EffectiveFrom time.Time  `gorm:"column:effective_from" db:"effective_from" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"column:effective_to" db:"effective_to" json:"effective_to,omitempty"`
+	UpdatedBy     int        `gorm:"column:updated_by" db:"updated_by" json:"updated_by"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// Validate: tarife alanlarını doğrular ve para birimini normalize eder
+func (f *FeeSchedule) Validate() error {
+	var problems []string
+	if strings.TrimSpace(f.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if f.TxType != "debit" && f.TxType != "transfer" {
+		problems = append(problems, "tx_type must be debit or transfer")
+	}
+	if f.Role != nil && !IsValidRole(*f.Role) {
+		problems = append(problems, "invalid role")
+	}
+	cur, err := LookupCurrency(f.Currency)
+	if err != nil {
+		problems = append(problems, "invalid currency")
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount >= *f.MaxAmount {
+		problems = append(problems, "min_amount must be less than max_amount")
+	}
+	switch f.Kind {
+	case FeeKindFlat, FeeKindPercent:
+		if len(f.Tiers) > 0 {
+			problems = append(problems, "tiers are only allowed for tiered fees")
+		}
+	case FeeKindTiered:
+		if len(f.Tiers) == 0 {
+			problems = append(problems, "tiered fee requires tiers")
+		}
+		for i, t := range f.Tiers {
+			last := i == len(f.Tiers)-1
+			if t.UpTo == nil && !last {
+				problems = append(problems, "only the last tier may omit up_to")
+			}
+			if i > 0 && t.UpTo != nil && f.Tiers[i-1].UpTo != nil && *t.UpTo <= *f.Tiers[i-1].UpTo {
+				problems = append(problems, "tiers must be in ascending up_to order")
+			}
+			if t.FlatAmount.IsNegative() || t.RateBps < 0 {
+				problems = append(problems, "tier values must be >= 0")
+			}
+		}
+	default:
+		problems = append(problems, "kind must be flat, percent or tiered")
+	}
+	if f.FlatAmount.IsNegative() || f.RateBps < 0 || f.RateBps > 10000 {
+		problems = append(problems, "flat_amount must be >= 0 and rate_bps between 0 and 10000")
+	}
+	if (f.MinFee != nil && f.MinFee.IsNegative()) || (f.MaxFee != nil && f.MaxFee.IsNegative()) {
+		problems = append(problems, "min_fee and max_fee must be >= 0")
+	}
+	if f.MinFee != nil && f.MaxFee != nil && *f.MinFee > *f.MaxFee {
+		problems = append(problems, "min_fee must not exceed max_fee")
+	}
+	if f.EffectiveFrom.IsZero() {
+		f.EffectiveFrom = time.Now()
+	}
+	if f.EffectiveTo != nil && !f.EffectiveTo.After(f.EffectiveFrom) {
+		problems = append(problems, "effective_to must be after effective_from")
+	}
+	if err == nil {
+		// ücret bacakları para biriminin minor unit'inden küçük kırılım taşıyamaz
+		checkPrecision := func(field string, m *Money) {
+			if m == nil {
+				return
+			}
+			if perr := cur.CheckPrecision(*m); perr != nil {
+				problems = append(problems, field+": "+perr.Error())
+			}
+		}
+		checkPrecision("flat_amount", &f.FlatAmount)
+		checkPrecision("min_fee", f.MinFee)
+		checkPrecision("max_fee", f.MaxFee)
+		for i := range f.Tiers {
+			checkPrecision(fmt.Sprintf("tiers[%d].flat_amount", i), &f.Tiers[i].FlatAmount)
+			checkPrecision(fmt.Sprintf("tiers[%d].up_to", i), f.Tiers[i].UpTo)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	f.Currency = cur.Code
+	return nil
+}
+
+// Matches: tarife verilen rol, tutar ve anda uygulanabilir mi
+func (f *FeeSchedule) Matches(role string, amount Money, at time.Time) bool {
+	if f.Role != nil && *f.Role != role {
+		return false
+	}
+	if f.MinAmount != nil && amount < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && amount >= *f.MaxAmount {
+		return false
+	}
+	if at.Before(f.EffectiveFrom) || (f.EffectiveTo != nil && !at.Before(*f.EffectiveTo)) {
+		return false
+	}
+	return true
+}
+
+// Compute: tutar için ücreti hesaplar; oransal kısım para biriminin minor unit'ine yarım-yukarı yuvarlanır
+// ve sonuç MinFee/MaxFee arasına sıkıştırılır.
+func (f *FeeSchedule) Compute(amount Money, cur Currency) Money {
+	flat, bps := f.FlatAmount, f.RateBps
+	switch f.Kind {
+	case FeeKindFlat:
+		bps = 0
+	case FeeKindTiered:
+		flat, bps = 0, 0
+		for _, t := range f.Tiers {
+			if t.UpTo == nil || amount <= *t.UpTo {
+				flat, bps = t.FlatAmount, t.RateBps
+				break
+			}
+		}
+	}
+	fee := flat + amount.Prorate(Money(bps), 10000, cur)
+	if f.MinFee != nil && fee < *f.MinFee {
+		fee = *f.MinFee
+	}
+	if f.MaxFee != nil && fee > *f.MaxFee {
+		fee = *f.MaxFee
+	}
+	return fee
+}
+
+// FeeQuote: işlem öncesi hesaplanan ücret; TxOptions.Fee ile repo'ya geçirilir
+type FeeQuote struct {
+	TxType       string `json:"tx_type"`
+	Currency     string `json:"currency"`
+	Amount       Money  `json:"amount"`
+	Fee          Money  `json:"fee"`
+	Total        Money  `json:"total"`
+	ScheduleID   *int   `json:"schedule_id,omitempty"`
+	ScheduleName string `json:"schedule_name,omitempty"`
+}
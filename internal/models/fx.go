@@ -0,0 +1,210 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccountFX: döviz pozisyon hesabı; çapraz kur transferlerinde iki para birimi bacağını dengeler
+const AccountFX = "system:fx"
+
+// Rate: sabit noktalı döviz kuru (10^-RateScale birim, numeric(20,8)).
+// 1 birim Base = Rate birim Quote.
+type Rate int64
+
+// RateScale: kur için ondalık basamak sayısı
+const RateScale = 8
+
+var (
+	ErrInvalidRate   = errors.New("invalid rate")
+	ErrRatePrecision = fmt.Errorf("rate supports at most %d decimal places", RateScale)
+)
+
+// ParseRate: "1.0825", "32.15" gibi kur metnini ayrıştırır
+func ParseRate(s string) (Rate, error) {
+	v, err := parseFixed(s, RateScale)
+	switch err {
+	case nil:
+		return Rate(v), nil
+	case errFixedPrecision:
+		return 0, ErrRatePrecision
+	default:
+		return 0, ErrInvalidRate
+	}
+}
+
+// String: kuru anlamlı basamaklarıyla yazar (en az 1 basamak)
+func (r Rate) String() string {
+	s := formatFixed(int64(r), RateScale, RateScale)
+	for i := RateScale; i > 1 && s[len(s)-1] == '0'; i-- {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// Invert: ters kur (1/r), RateScale basamağa yarım-yukarı yuvarlanır
+func (r Rate) Invert() Rate {
+	if r <= 0 {
+		return 0
+	}
+	num := new(big.Int).Mul(big.NewInt(pow10(RateScale)), big.NewInt(pow10(RateScale)))
+	return Rate(divRoundHalfUp(num, big.NewInt(int64(r))).Int64())
+}
+
+// ApplySpread: orta kurdan müşteri aleyhine spread (baz puan) düşülmüş kuru hesaplar
+func (r Rate) ApplySpread(bps int) Rate {
+	if bps <= 0 {
+		return r
+	}
+	num := new(big.Int).Mul(big.NewInt(int64(r)), big.NewInt(int64(10000-bps)))
+	return Rate(new(big.Int).Quo(num, big.NewInt(10000)).Int64())
+}
+
+// Convert: tutarı kur ile çevirir ve hedef para biriminin minor unit'ine yarım-yukarı yuvarlar
+func (r Rate) Convert(amount Money, to Currency) Money {
+	// amount (scale 4) * rate (scale 8) => scale 12; hedef basamağa indir
+	prod := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(int64(r)))
+	exp := to.Exponent
+	if exp > MoneyScale {
+		exp = MoneyScale
+	}
+	div := big.NewInt(pow10(MoneyScale + RateScale - exp))
+	q := divRoundHalfUp(prod, div)
+	return Money(q.Int64() * pow10(MoneyScale-exp))
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+func (r *Rate) UnmarshalJSON(b []byte) error {
+	s, err := decimalJSONText(b)
+	if err != nil {
+		return ErrInvalidRate
+	}
+	if s == "" {
+		return nil
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func (r *Rate) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*r = 0
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		*r = Rate(v * pow10(RateScale))
+		return nil
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Rate", src)
+	}
+	v, err := ParseRate(s)
+	if err != nil {
+		return fmt.Errorf("scan rate %q: %w", s, err)
+	}
+	*r = v
+	return nil
+}
+
+// FXRate: admin tarafından yönetilen kur kaydı; [ValidFrom, ValidTo) aralığında geçerlidir
+type FXRate struct {
+	ID        int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	Base      string     `gorm:"column:base_currency;type:char(3);index:idx_fx_rates_pair" db:"base_currency" json:"base"`
+	Quote     string     `gorm:"column:quote_currency;type:char(3);index:idx_fx_rates_pair" db:"quote_currency" json:"quote"`
+	Rate      Rate       `gorm:"column:rate;type:numeric(20,8)" db:"rate" json:"rate"`
+	SpreadBps *int       `gorm:"column:spread_bps" db:"spread_bps" json:"spread_bps,omitempty"`
+	ValidFrom time.Time  `gorm:"column:valid_from;index:idx_fx_rates_pair" db:"valid_from" json:"valid_from"`
+	ValidTo   *time.Time `gorm:"column:valid_to" db:"valid_to" json:"valid_to,omitempty"`
+	CreatedBy int        `gorm:"column:created_by" db:"created_by" json:"created_by"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+}
+
+// Validate: kur kaydı için temel kurallar
+func (f *FXRate) Validate() error {
+	var problems []string
+	base, err := LookupCurrency(f.Base)
+	if err != nil {
+		problems = append(problems, "invalid base currency")
+	}
+	quote, err := LookupCurrency(f.Quote)
+	if err != nil {
+		problems = append(problems, "invalid quote currency")
+	}
+	if base.Code != "" && base.Code == quote.Code {
+		problems = append(problems, "base and quote must differ")
+	}
+	if f.Rate <= 0 {
+		problems = append(problems, "rate must be > 0")
+	}
+	if f.SpreadBps != nil && (*f.SpreadBps < 0 || *f.SpreadBps >= 10000) {
+		problems = append(problems, "spread_bps must be between 0 and 9999")
+	}
+	if f.ValidTo != nil && !f.ValidTo.After(f.ValidFrom) {
+		problems = append(problems, "valid_to must be after valid_from")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	f.Base, f.Quote = base.Code, quote.Code
+	return nil
+}
+
+// FXQuote: çapraz kur transferi için hesaplanan teklif; transaction kaydına snapshot olarak yazılır
+type FXQuote struct {
+	FromCurrency  string    `json:"from_currency"`
+	ToCurrency    string    `json:"to_currency"`
+	Amount        Money     `json:"amount"`
+	CounterAmount Money     `json:"counter_amount"`
+	MidRate       Rate      `json:"mid_rate"`
+	Rate          Rate      `json:"rate"`
+	SpreadBps     int       `json:"spread_bps"`
+	RateID        int       `json:"rate_id"`
+	Inverted      bool      `json:"inverted"`
+	At            time.Time `json:"at"`
+}
+
+// JSON helper’ları
+func (f *FXRate) ToJSON() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func (f *FXRate) FromJSON(data []byte) error {
+	return json.Unmarshal(data, f)
+}
+
+// divRoundHalfUp: sıfırdan uzağa yarım yuvarlamalı tam sayı bölme
+func divRoundHalfUp(num, den *big.Int) *big.Int {
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+	if twice.Cmp(new(big.Int).Abs(den)) >= 0 {
+		if (num.Sign() < 0) != (den.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
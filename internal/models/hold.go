@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Hold durumları
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusVoided   = "voided"
+	HoldStatusExpired  = "expired"
+)
+
+// Hold: ödeme öncesi rezerve edilen tutar (authorization).
+// Aktif hold kullanılabilir bakiyeyi (available) düşürür, ledger bakiyesini değiştirmez.
+// Capture anında hold serbest bırakılır ve gerçek debit/transfer yazılır.
+type Hold struct {
+	ID             int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	UserID         int        `gorm:"column:user_id;index" db:"user_id" json:"user_id"`
+	ToUserID       *int       `gorm:"column:to_user_id;index" db:"to_user_id" json:"to_user_id,omitempty"` // capture alıcısı (merchant); boşsa debit
+	Currency       string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Amount         Money      `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	CapturedAmount Money      `gorm:"column:captured_amount;type:numeric(20,4);default:0" db:"captured_amount" json:"captured_amount"`
+	Status         string     `gorm:"column:status;index" db:"status" json:"status"`
+	Reference      string     `gorm:"column:reference" db:"reference" json:"reference,omitempty"`
+	TransactionID  *int       `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	ExpiresAt      time.Time  `gorm:"column:expires_at;index" db:"expires_at" json:"expires_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	ReleasedAt     *time.Time `gorm:"column:released_at" db:"released_at" json:"released_at,omitempty"`
+}
+
+// JSON helper’ları
+func (h *Hold) ToJSON() ([]byte, error) {
+	return json.Marshal(h)
+}
+
+func (h *Hold) FromJSON(data []byte) error {
+	return json.Unmarshal(data, h)
+}
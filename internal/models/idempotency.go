@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyKey: kullanıcı başına saklanan Idempotency-Key kaydı.
+// StatusCode 0 ise istek hâlâ işleniyor demektir; tamamlanınca orijinal yanıt saklanır.
+type IdempotencyKey struct {
+	UserID       int       `gorm:"column:user_id;primaryKey;autoIncrement:false" db:"user_id" json:"user_id"`
+	Key          string    `gorm:"column:idempotency_key;primaryKey" db:"idempotency_key" json:"key"`
+	Method       string    `gorm:"column:method" db:"method" json:"method"`
+	Path         string    `gorm:"column:path" db:"path" json:"path"`
+	Fingerprint  string    `gorm:"column:fingerprint" db:"fingerprint" json:"fingerprint"`
+	StatusCode   int       `gorm:"column:status_code;default:0" db:"status_code" json:"status_code"`
+	ContentType  string    `gorm:"column:content_type" db:"content_type" json:"content_type"`
+	ResponseBody string    `gorm:"column:response_body" db:"response_body" json:"response_body"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	ExpiresAt    time.Time `gorm:"column:expires_at;index" db:"expires_at" json:"expires_at"`
+}
+
+// Completed: orijinal yanıt kaydedilmiş mi
+func (k *IdempotencyKey) Completed() bool { return k.StatusCode != 0 }
+
+// JSON helper’ları
+func (k *IdempotencyKey) ToJSON() ([]byte, error) {
+	return json.Marshal(k)
+}
+
+func (k *IdempotencyKey) FromJSON(data []byte) error {
+	return json.Unmarshal(data, k)
+}
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// AccountInterestExpense: pozitif bakiyelere ödenen mevduat faizinin kaynağı olan house hesabı
+const AccountInterestExpense = "system:interest_expense"
+
+// TxTypeInterest: aylık toplu ödenen mevduat faizi
+const TxTypeInterest = "interest"
+
+// OriginInterestPosting: aylık faiz işleminin origin tipi (id = interest_postings.id)
+const OriginInterestPosting = "interest_posting"
+
+// Gün sayım (day-count) kuralları: bir günlük faizin yıl içindeki payı
+const (
+	DayCountAct365 = "ACT/365" // her gün 1/365
+	DayCountAct360 = "ACT/360" // her gün 1/360
+	DayCountActAct = "ACT/ACT" // her gün 1/(yılın gün sayısı)
+	DayCount30360  = "30/360"  // 30/360 bond basis: her ay 30 gün sayılır (31'i sıfır, şubat sonu eksik günleri toplar)
+)
+
+// IsValidDayCount: desteklenen gün sayım kuralı mı
+func IsValidDayCount(dc string) bool {
+	switch dc {
+	case DayCountAct365, DayCountAct360, DayCountActAct, DayCount30360:
+		return true
+	}
+	return false
+}
+
+// DayFraction: day gününün (UTC) yıl payını num/den olarak döner
+func DayFraction(dayCount string, day time.Time) (num, den int64) {
+	switch dayCount {
+	case DayCountAct360:
+		return 1, 360
+	case DayCountActAct:
+		y := day.Year()
+		if time.Date(y, 12, 31, 0, 0, 0, 0, time.UTC).YearDay() == 366 {
+			return 1, 366
+		}
+		return 1, 365
+	case DayCount30360:
+		return days30360(day, day.AddDate(0, 0, 1)), 360
+	}
+	return 1, 365
+}
+
+// days30360: iki tarih arasındaki gün sayısı (30/360 bond basis)
+func days30360(start, end time.Time) int64 {
+	y1, m1, d1 := start.Date()
+	y2, m2, d2 := end.Date()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return int64(360*(y2-y1) + 30*(int(m2)-int(m1)) + (d2 - d1))
+}
+
+// DailyInterest: balance * rateBps/10000 * gün payı; yuvarlama tahakkukta değil ödemede yapılır, sonuç 4 hanede yarım-yukarı yuvarlanır
+func DailyInterest(balance Money, rateBps int, dayCount string, day time.Time) Money {
+	if !balance.IsPositive() || rateBps <= 0 {
+		return 0
+	}
+	num, den := DayFraction(dayCount, day)
+	n := new(big.Int).Mul(big.NewInt(int64(balance)), big.NewInt(int64(rateBps)*num))
+	d := big.NewInt(10000 * den)
+	return Money(divRoundHalfUp(n, d).Int64())
+}
+
+// InterestRate: mevduat faizi tarifesi. Bir gün için para birimindeki geçerli tarifeler arasından
+// MinBalance'ı günlük kapanış bakiyesine uyan en yüksek kademe, eşitlikte en yeni EffectiveFrom'lu olan seçilir;
+// oran bakiyenin tamamına uygulanır.
+type InterestRate struct {
+	ID            int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	Currency      string     `gorm:"column:currency;type:char(3);index" db:"currency" json:"currency"`
+	MinBalance    Money      `gorm:"column:min_balance;type:numeric(20,4);default:0" db:"min_balance" json:"min_balance"`
+	RateBps       int        `gorm:"column:rate_bps" db:"rate_bps" json:"rate_bps"`
+	DayCount      string     `gorm:"column:day_count" db:"day_count" json:"day_count"`
+	EffectiveFrom time.Time  `gorm:"column:effective_from" db:"effective_from" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"column:effective_to" db:"effective_to" json:"effective_to,omitempty"`
+	UpdatedBy     int        `gorm:"column:updated_by" db:"updated_by" json:"updated_by"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// Validate: tarife alanlarını doğrular; gün sayımı boşsa ACT/365, EffectiveFrom boşsa bugün (UTC) kullanılır
+func (r *InterestRate) Validate() error {
+	var problems []string
+	cur, err := LookupCurrency(r.Currency)
+	if err != nil {
+		problems = append(problems, "invalid currency")
+	}
+	if r.MinBalance.IsNegative() {
+		problems = append(problems, "min_balance must be >= 0")
+	}
+	if r.RateBps < 0 || r.RateBps > 100000 {
+		problems = append(problems, "rate_bps must be between 0 and 100000")
+	}
+	r.DayCount = strings.ToUpper(strings.TrimSpace(r.DayCount))
+	if r.DayCount == "" {
+		r.DayCount = DayCountAct365
+	}
+	if !IsValidDayCount(r.DayCount) {
+		problems = append(problems, "day_count must be ACT/365, ACT/360, ACT/ACT or 30/360")
+	}
+	if r.EffectiveFrom.IsZero() {
+		now := time.Now().UTC()
+		r.EffectiveFrom = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	if r.EffectiveTo != nil && !r.EffectiveTo.After(r.EffectiveFrom) {
+		problems = append(problems, "effective_to must be after effective_from")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	r.Currency = cur.Code
+	return nil
+}
+
+// ActiveOn: tarife day gününün başında geçerli mi
+func (r *InterestRate) ActiveOn(day time.Time) bool {
+	return !day.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || day.Before(*r.EffectiveTo))
+}
+
+// PickInterestRate: day günü ve kapanış bakiyesi için uygulanacak tarife; yoksa nil
+func PickInterestRate(rates []InterestRate, day time.Time, balance Money) *InterestRate {
+	var best *InterestRate
+	for i := range rates {
+		r := &rates[i]
+		if !r.ActiveOn(day) || balance < r.MinBalance {
+			continue
+		}
+		if best == nil || r.MinBalance > best.MinBalance ||
+			(r.MinBalance == best.MinBalance && r.EffectiveFrom.After(best.EffectiveFrom)) {
+			best = r
+		}
+	}
+	return best
+}
+
+// InterestAccrual: bir cüzdanın bir günlük faiz tahakkuku; (user, currency, gün) başına tek kayıt.
+// Interest 4 hanelidir; aylık ödemede toplanıp minor unit'e yuvarlanır. PostedAt ödeme işlenince (tutar sıfır olsa da) dolar.
+type InterestAccrual struct {
+	UserID        int        `gorm:"column:user_id;primaryKey;autoIncrement:false" db:"user_id" json:"user_id"`
+	Currency      string     `gorm:"column:currency;primaryKey;type:char(3)" db:"currency" json:"currency"`
+	AccrualDate   time.Time  `gorm:"column:accrual_date;primaryKey;type:date" db:"accrual_date" json:"accrual_date"`
+	Balance       Money      `gorm:"column:balance;type:numeric(20,4)" db:"balance" json:"balance"`
+	RateID        *int       `gorm:"column:rate_id" db:"rate_id" json:"rate_id,omitempty"`
+	RateBps       int        `gorm:"column:rate_bps" db:"rate_bps" json:"rate_bps"`
+	DayCount      string     `gorm:"column:day_count" db:"day_count" json:"day_count"`
+	Interest      Money      `gorm:"column:interest;type:numeric(20,4)" db:"interest" json:"interest"`
+	TransactionID *int       `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	PostedAt      *time.Time `gorm:"column:posted_at" db:"posted_at" json:"posted_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+}
+
+// WalletDay: bir cüzdanın bir günlük kapanış bakiyesi
+type WalletDay struct {
+	UserID   int       `json:"user_id"`
+	Currency string    `json:"currency"`
+	Day      time.Time `json:"day"`
+	Balance  Money     `json:"balance"`
+}
+
+// InterestPreview: tarih aralığı için yazılmadan hesaplanan tahakkuklar (admin önizlemesi)
+type InterestPreview struct {
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Accruals []InterestAccrual `json:"accruals"`
+	Totals   []CurrencyTotal   `json:"totals"`
+}
+
+// JSON helper’ları
+func (a *InterestAccrual) ToJSON() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *InterestAccrual) FromJSON(data []byte) error {
+	return json.Unmarshal(data, a)
+}
+
+// InterestPosting: bir cüzdan-ayın faiz ödemesi; (user, currency, month) başına tek kayıt.
+// Yuvarlanan tutar sıfırsa TransactionID boştur, ay yine ödenmiş sayılır.
+type InterestPosting struct {
+	ID            int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	UserID        int       `gorm:"column:user_id;uniqueIndex:uq_interest_postings_wallet_month" db:"user_id" json:"user_id"`
+	Currency      string    `gorm:"column:currency;type:char(3);uniqueIndex:uq_interest_postings_wallet_month" db:"currency" json:"currency"`
+	Month         time.Time `gorm:"column:month;type:date;uniqueIndex:uq_interest_postings_wallet_month" db:"month" json:"month"`
+	Amount        Money     `gorm:"column:amount;type:numeric(20,4);default:0" db:"amount" json:"amount"`
+	TransactionID *int      `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+}
+
+// InterestPeriod: ödenmemiş tahakkuku olan cüzdan-ay; ödenmiş aya geç yazılan tahakkuklar sonraki aya taşınır
+type InterestPeriod struct {
+	UserID   int       `gorm:"column:user_id" json:"user_id"`
+	Currency string    `gorm:"column:currency" json:"currency"`
+	Month    time.Time `gorm:"column:month" json:"month"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Sistem (house) hesapları: kullanıcıya ait olmayan karşı taraf hesapları
+const (
+	// AccountSettlement: dış dünya ile para giriş/çıkışı (cash-in / cash-out)
+	AccountSettlement = "system:settlement"
+	// AccountAdjustment: manuel bakiye düzeltmeleri (SetBalance, açılış bakiyesi)
+	AccountAdjustment = "system:adjustment"
+	// AccountInterestIncome: overdraft faiz geliri
+	AccountInterestIncome = "system:interest_income"
+)
+
+// LedgerEntry: çift taraflı muhasebe kaydı (posting).
+// Amount işaretlidir: pozitif = hesaba alacak (bakiye artar), negatif = borç (bakiye azalır).
+// Bir transaction'a ait kayıtların toplamı her para birimi için ayrı ayrı sıfırdır.
+type LedgerEntry struct {
+	ID            int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	TransactionID int       `gorm:"column:transaction_id;index" db:"transaction_id" json:"transaction_id"`
+	Account       string    `gorm:"column:account;index" db:"account" json:"account"`
+	UserID        *int      `gorm:"column:user_id;index" db:"user_id" json:"user_id,omitempty"`
+	Currency      string    `gorm:"column:currency;type:char(3);index" db:"currency" json:"currency"`
+	Amount        Money     `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime;index" db:"created_at" json:"created_at"`
+}
+
+// AccountBalance: bir hesabın belirli bir andaki bakiyesi (trial balance satırı)
+type AccountBalance struct {
+	Account  string `gorm:"column:account" json:"account"`
+	Currency string `gorm:"column:currency" json:"currency"`
+	Balance  Money  `gorm:"column:balance" json:"balance"`
+}
+
+// CurrencyTotal: bir para birimindeki tüm kayıtların toplamı
+type CurrencyTotal struct {
+	Currency string `gorm:"column:currency" json:"currency"`
+	Total    Money  `gorm:"column:total" json:"total"`
+}
+
+// UserAccount: kullanıcı cüzdanının ledger hesap kodu
+func UserAccount(userID int) string { return fmt.Sprintf("user:%d", userID) }
+
+// UserLeg: kullanıcı cüzdanına (user, currency) ait bir kayıt bacağı
+func UserLeg(userID int, currency string, amount Money) LedgerEntry {
+	uid := userID
+	return LedgerEntry{Account: UserAccount(userID), UserID: &uid, Currency: currency, Amount: amount}
+}
+
+// SystemLeg: sistem hesabına ait bir kayıt bacağı
+func SystemLeg(account, currency string, amount Money) LedgerEntry {
+	return LedgerEntry{Account: account, Currency: currency, Amount: amount}
+}
+
+// JSON helper’ları
+func (e *LedgerEntry) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func (e *LedgerEntry) FromJSON(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+// TrialBalance: denetim raporu; her para biriminin toplamı her an sıfır olmalıdır
+type TrialBalance struct {
+	At       time.Time        `json:"at"`
+	Accounts []AccountBalance `json:"accounts"`
+	Totals   []CurrencyTotal  `json:"totals"`
+	Balanced bool             `json:"balanced"`
+}
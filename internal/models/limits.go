@@ -0,0 +1,119 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LimitPolicy: giden işlem limitleri. Role doluysa rol varsayılanı, UserID doluysa kullanıcıya özel override'dır.
+// Boş (nil) alanlar sınırsızdır; override'da boş alan rol varsayılanına düşer.
+// Tutarlar Currency cinsindendir; ilgili para biriminde politika yoksa limit uygulanmaz.
+type LimitPolicy struct {
+	ID                int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	Role              *string   `gorm:"column:role" db:"role" json:"role,omitempty"`
+	UserID            *int      `gorm:"column:user_id;index" db:"user_id" json:"user_id,omitempty"`
+	Currency          string    `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	PerTransactionMax *Money    `gorm:"column:per_transaction_max;type:numeric(20,4)" db:"per_transaction_max" json:"per_transaction_max,omitempty"`
+	DailyMax          *Money    `gorm:"column:daily_max;type:numeric(20,4)" db:"daily_max" json:"daily_max,omitempty"`
+	MonthlyMax        *Money    `gorm:"column:monthly_max;type:numeric(20,4)" db:"monthly_max" json:"monthly_max,omitempty"`
+	HourlyTransferMax *int      `gorm:"column:hourly_transfer_max" db:"hourly_transfer_max" json:"hourly_transfer_max,omitempty"`
+	UpdatedBy         int       `gorm:"column:updated_by" db:"updated_by" json:"updated_by"`
+	CreatedAt         time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// Validate: politika kapsamı ve değer kuralları
+func (p *LimitPolicy) Validate() error {
+	var problems []string
+	if (p.Role == nil) == (p.UserID == nil) {
+		problems = append(problems, "exactly one of role or user_id is required")
+	}
+	if p.Role != nil && !IsValidRole(*p.Role) {
+		problems = append(problems, "invalid role")
+	}
+	cur, err := LookupCurrency(p.Currency)
+	if err != nil {
+		problems = append(problems, "invalid currency")
+	}
+	for name, m := range map[string]*Money{"per_transaction_max": p.PerTransactionMax, "daily_max": p.DailyMax, "monthly_max": p.MonthlyMax} {
+		if m != nil && m.IsNegative() {
+			problems = append(problems, name+" must be >= 0")
+		}
+	}
+	if p.HourlyTransferMax != nil && *p.HourlyTransferMax < 0 {
+		problems = append(problems, "hourly_transfer_max must be >= 0")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	p.Currency = cur.Code
+	return nil
+}
+
+// EffectiveLimit: rol varsayılanı ve kullanıcı override'ı birleştirilmiş limitler
+type EffectiveLimit struct {
+	UserID            int    `json:"user_id"`
+	Currency          string `json:"currency"`
+	PerTransactionMax *Money `json:"per_transaction_max,omitempty"`
+	DailyMax          *Money `json:"daily_max,omitempty"`
+	MonthlyMax        *Money `json:"monthly_max,omitempty"`
+	HourlyTransferMax *int   `json:"hourly_transfer_max,omitempty"`
+}
+
+// MergeLimits: override'daki dolu alanlar rol varsayılanını ezer
+func MergeLimits(userID int, currency string, roleDefault, override *LimitPolicy) *EffectiveLimit {
+	eff := &EffectiveLimit{UserID: userID, Currency: currency}
+	for _, p := range []*LimitPolicy{roleDefault, override} {
+		if p == nil {
+			continue
+		}
+		if p.PerTransactionMax != nil {
+			eff.PerTransactionMax = p.PerTransactionMax
+		}
+		if p.DailyMax != nil {
+			eff.DailyMax = p.DailyMax
+		}
+		if p.MonthlyMax != nil {
+			eff.MonthlyMax = p.MonthlyMax
+		}
+		if p.HourlyTransferMax != nil {
+			eff.HourlyTransferMax = p.HourlyTransferMax
+		}
+	}
+	return eff
+}
+
+// Limit türleri (LimitExceededError.Limit)
+const (
+	LimitPerTransaction = "per_transaction"
+	LimitDaily          = "daily"
+	LimitMonthly        = "monthly"
+	LimitHourlyCount    = "hourly_transfer_count"
+)
+
+// ErrCodeLimitExceeded: API yanıtındaki ayırt edici hata kodu
+const ErrCodeLimitExceeded = "limit_exceeded"
+
+// LimitExceededError: giden işlem limiti aşıldı
+type LimitExceededError struct {
+	Limit    string `json:"limit"`
+	Currency string `json:"currency"`
+	Max      string `json:"max"`
+	Current  string `json:"current"` // pencere içindeki mevcut kullanım (işlem hariç)
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit exceeded (max %s, used %s)", e.Limit, e.Max, e.Current)
+}
+
+// JSON helper’ları
+func (p *LimitPolicy) ToJSON() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *LimitPolicy) FromJSON(data []byte) error {
+	return json.Unmarshal(data, p)
+}
@@ -0,0 +1,270 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money: sabit noktalı para tutarı (10^-MoneyScale birim cinsinden int64).
+// float64 yuvarlama kaymasını önlemek için JSON decode'dan SQL'e kadar bu tip taşınır.
+// API'de string olarak ("12.34") serileştirilir; DB tarafında numeric sütunlara yazılır.
+type Money int64
+
+// MoneyScale: dahili ondalık basamak sayısı (numeric(20,4) ile uyumlu).
+// Para birimine özel minor unit kuralı Currency.CheckPrecision ile ayrıca uygulanır.
+const MoneyScale = 4
+
+var moneyFactor = pow10(MoneyScale)
+
+var (
+	ErrInvalidMoney   = errors.New("invalid amount")
+	ErrMoneyPrecision = fmt.Errorf("amount supports at most %d decimal places", MoneyScale)
+	ErrMoneyOverflow  = errors.New("amount out of range")
+)
+
+// ParseMoney: "12.34", "-5", "0.5" gibi ondalık metni ayrıştırır.
+// MoneyScale'den fazla basamak içeren tutarlar reddedilir (sessizce yuvarlanmaz).
+func ParseMoney(s string) (Money, error) {
+	v, err := parseFixed(s, MoneyScale)
+	switch err {
+	case nil:
+		return Money(v), nil
+	case errFixedPrecision:
+		return 0, ErrMoneyPrecision
+	case errFixedOverflow:
+		return 0, ErrMoneyOverflow
+	default:
+		return 0, ErrInvalidMoney
+	}
+}
+
+var (
+	errFixedInvalid   = errors.New("invalid decimal")
+	errFixedPrecision = errors.New("too many decimal places")
+	errFixedOverflow  = errors.New("decimal out of range")
+)
+
+// parseFixed: ondalık metni 10^-scale birim cinsinden int64'e çevirir (Money ve Rate ortak ayrıştırıcısı)
+func parseFixed(s string, scale int) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errFixedInvalid
+	}
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, errFixedInvalid
+	}
+	if hasDot && fracPart == "" {
+		return 0, errFixedInvalid
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, errFixedInvalid
+	}
+	// sondaki sıfırlar hassasiyet ihlali sayılmaz ("1.500" == "1.50")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > scale {
+		return 0, errFixedPrecision
+	}
+	fracPart += strings.Repeat("0", scale-len(fracPart))
+
+	factor := pow10(scale)
+	var whole, frac int64
+	var err error
+	if intPart != "" {
+		if whole, err = strconv.ParseInt(intPart, 10, 64); err != nil {
+			return 0, errFixedOverflow
+		}
+	}
+	if fracPart != "" {
+		if frac, err = strconv.ParseInt(fracPart, 10, 64); err != nil {
+			return 0, errFixedInvalid
+		}
+	}
+	if whole > (1<<63-1-frac)/factor {
+		return 0, errFixedOverflow
+	}
+	v := whole*factor + frac
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// MustParseMoney: sabitler için; hatalı girdide panic
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// String: "1234.50" biçiminde ondalık gösterim (en az 2 basamak; anlamlı ek basamaklar korunur)
+func (m Money) String() string {
+	s := m.StringFixed(MoneyScale)
+	for i := MoneyScale; i > 2 && s[len(s)-1] == '0'; i-- {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// StringFixed: tam olarak places basamakla yazar; fazla basamaklar kesilir (çağıran yuvarlamadan sorumlu)
+func (m Money) StringFixed(places int) string {
+	return formatFixed(int64(m), MoneyScale, places)
+}
+
+// formatFixed: 10^-scale birimli değeri places basamakla yazar
+func formatFixed(v int64, scale, places int) string {
+	sign := ""
+	// math.MinInt64 negatiflenemez; uint64 üzerinden çalış
+	u := uint64(v)
+	if v < 0 {
+		sign = "-"
+		u = uint64(-(v + 1)) + 1
+	}
+	f := uint64(pow10(scale))
+	if places <= 0 {
+		return fmt.Sprintf("%s%d", sign, u/f)
+	}
+	if places > scale {
+		places = scale
+	}
+	frac := (u % f) / uint64(pow10(scale-places))
+	return fmt.Sprintf("%s%d.%0*d", sign, u/f, places, frac)
+}
+
+func (m Money) Add(o Money) Money { return m + o }
+func (m Money) Sub(o Money) Money { return m - o }
+func (m Money) Neg() Money        { return -m }
+func (m Money) IsZero() bool      { return m == 0 }
+func (m Money) IsPositive() bool  { return m > 0 }
+func (m Money) IsNegative() bool  { return m < 0 }
+
+// Float64: metrikler için yaklaşık değer; para hesaplarında kullanılmamalıdır
+func (m Money) Float64() float64 { return float64(m) / float64(moneyFactor) }
+
+// Prorate: m * part / whole oranını hesaplar ve para biriminin minor unit'ine yarım-yukarı yuvarlar (kısmi iade vb.)
+func (m Money) Prorate(part, whole Money, cur Currency) Money {
+	if whole == 0 {
+		return 0
+	}
+	exp := cur.Exponent
+	if exp > MoneyScale {
+		exp = MoneyScale
+	}
+	unit := big.NewInt(pow10(MoneyScale - exp))
+	num := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(part)))
+	den := new(big.Int).Mul(big.NewInt(int64(whole)), unit)
+	return Money(divRoundHalfUp(num, den).Int64() * unit.Int64())
+}
+
+// Round: tutarı para biriminin minor unit'ine yarım-yukarı yuvarlar
+func (m Money) Round(cur Currency) Money {
+	return m.Prorate(1, 1, cur)
+}
+
+// MarshalJSON: tutarı string olarak yazar ("12.34")
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON: hem "12.34" (string) hem 12.34 (number) kabul eder.
+// Sayılar float64'e çevrilmeden metin olarak ayrıştırılır.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s, err := decimalJSONText(b)
+	if err != nil {
+		return ErrInvalidMoney
+	}
+	if s == "" {
+		return nil
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// decimalJSONText: JSON string ya da number değerini ondalık metin olarak döner; null için boş string
+func decimalJSONText(b []byte) (string, error) {
+	s := strings.TrimSpace(string(b))
+	if s == "null" {
+		return "", nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unq, err := strconv.Unquote(s)
+		if err != nil || strings.TrimSpace(unq) == "" {
+			return "", errFixedInvalid
+		}
+		return unq, nil
+	}
+	// üstel gösterim desteklenmez (12e2 gibi)
+	if strings.ContainsAny(s, "eE") {
+		return "", errFixedInvalid
+	}
+	return s, nil
+}
+
+// Value: driver.Valuer; numeric sütunlara ondalık metin olarak yazılır
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan: sql.Scanner; numeric sütunlardan okur
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case string:
+		return m.scanString(v)
+	case []byte:
+		return m.scanString(string(v))
+	case int64:
+		*m = Money(v * moneyFactor)
+		return nil
+	case float64:
+		return m.scanString(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+func (m *Money) scanString(s string) error {
+	v, err := ParseMoney(s)
+	if err != nil {
+		return fmt.Errorf("scan money %q: %w", s, err)
+	}
+	*m = v
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) int64 {
+	v := int64(1)
+	for i := 0; i < n; i++ {
+		v *= 10
+	}
+	return v
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{"12.34", 123400, nil},
+		{"0.5", 5000, nil},
+		{".5", 5000, nil},
+		{"5", 50000, nil},
+		{"+7.25", 72500, nil},
+		{"-5", -50000, nil},
+		{"-0.0001", -1, nil},
+		{" 1.5 ", 15000, nil},
+		{"1.500000", 15000, nil}, // sondaki sıfırlar hassasiyet ihlali değildir
+		{"0", 0, nil},
+		{"922337203685477.5807", Money(1<<63 - 1), nil},
+		{"1.23456", 0, ErrMoneyPrecision},
+		{"-0.00001", 0, ErrMoneyPrecision},
+		{"922337203685477.5808", 0, ErrMoneyOverflow},
+		{"99999999999999999999", 0, ErrMoneyOverflow},
+		{"", 0, ErrInvalidMoney},
+		{"-", 0, ErrInvalidMoney},
+		{".", 0, ErrInvalidMoney},
+		{"1.", 0, ErrInvalidMoney},
+		{"1,5", 0, ErrInvalidMoney},
+		{"1e3", 0, ErrInvalidMoney},
+		{"--1", 0, ErrInvalidMoney},
+		{"abc", 0, ErrInvalidMoney},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseMoney(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{123400, "12.34"},
+		{50000, "5.00"},
+		{12345, "1.2345"},
+		{12340, "1.234"},
+		{-1, "-0.0001"},
+		{-50000, "-5.00"},
+		{0, "0.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	usd := Currency{Code: "USD", Exponent: 2}
+	jpy := Currency{Code: "JPY", Exponent: 0}
+	kwd := Currency{Code: "KWD", Exponent: 3}
+	tests := []struct {
+		in   string
+		cur  Currency
+		want string
+	}{
+		{"1.2345", usd, "1.23"},
+		{"1.2350", usd, "1.24"}, // yarım yukarı
+		{"1.2349", usd, "1.23"},
+		{"-1.2350", usd, "-1.24"}, // negatiflerde yarım sıfırdan uzağa
+		{"-1.2349", usd, "-1.23"},
+		{"0.0049", usd, "0.00"},
+		{"0.005", usd, "0.01"},
+		{"1499.5", jpy, "1500"},
+		{"1499.4999", jpy, "1499"},
+		{"-0.5", jpy, "-1"},
+		{"1.2345", kwd, "1.235"},
+		{"1.2344", kwd, "1.234"},
+		{"12.34", Currency{Code: "XXX", Exponent: 6}, "12.34"}, // MoneyScale'den hassas para birimi değişmez
+	}
+	for _, tt := range tests {
+		got := MustParseMoney(tt.in).Round(tt.cur)
+		if want := MustParseMoney(tt.want); got != want {
+			t.Errorf("Round(%s, %s) = %s, want %s", tt.in, tt.cur.Code, got, want)
+		}
+	}
+}
+
+func TestMoneyProrate(t *testing.T) {
+	usd := Currency{Code: "USD", Exponent: 2}
+	tests := []struct {
+		name           string
+		m, part, whole string
+		want           string
+	}{
+		{"half", "100", "1", "2", "50.00"},
+		{"third rounds down", "100", "1", "3", "33.33"},
+		{"two thirds rounds up", "100", "2", "3", "66.67"},
+		{"negative amount", "-100", "2", "3", "-66.67"},
+		{"negative tie away from zero", "-0.01", "1", "2", "-0.01"},
+		{"positive tie away from zero", "0.01", "1", "2", "0.01"},
+		{"whole", "12.34", "5", "5", "12.34"},
+		{"zero part", "12.34", "0", "5", "0"},
+		{"zero whole", "12.34", "1", "0", "0"},
+		{"large values do not overflow", "900000000000", "900000000000", "900000000000", "900000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParseMoney(tt.m).Prorate(MustParseMoney(tt.part), MustParseMoney(tt.whole), usd)
+			if want := MustParseMoney(tt.want); got != want {
+				t.Errorf("Prorate(%s, %s/%s) = %s, want %s", tt.m, tt.part, tt.whole, got, want)
+			}
+		})
+	}
+}
+
+// Kümülatif prorate farkları (kısmi iadeler) toplamı orijinal tutara tam eşitlenir
+func TestMoneyProrateCumulative(t *testing.T) {
+	usd := Currency{Code: "USD", Exponent: 2}
+	total := MustParseMoney("100")
+	whole := MustParseMoney("3")
+	var sum, before Money
+	for _, step := range []string{"1", "1", "1"} {
+		after := before + MustParseMoney(step)
+		sum += total.Prorate(after, whole, usd) - total.Prorate(before, whole, usd)
+		before = after
+	}
+	if sum != total {
+		t.Fatalf("cumulative prorate = %s, want %s", sum, total)
+	}
+}
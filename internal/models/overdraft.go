@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OverdraftAccrual: bir cüzdan için bir günün overdraft faiz tahakkuku; (user, currency, gün) başına tek kayıt
+type OverdraftAccrual struct {
+	UserID        int       `gorm:"column:user_id;primaryKey;autoIncrement:false" db:"user_id" json:"user_id"`
+	Currency      string    `gorm:"column:currency;primaryKey;type:char(3)" db:"currency" json:"currency"`
+	AccrualDate   time.Time `gorm:"column:accrual_date;primaryKey;type:date" db:"accrual_date" json:"accrual_date"`
+	Balance       Money     `gorm:"column:balance;type:numeric(20,4)" db:"balance" json:"balance"`
+	RateBps       int       `gorm:"column:rate_bps" db:"rate_bps" json:"rate_bps"`
+	Interest      Money     `gorm:"column:interest;type:numeric(20,4)" db:"interest" json:"interest"`
+	TransactionID *int      `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+}
+
+// JSON helper’ları
+func (a *OverdraftAccrual) ToJSON() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *OverdraftAccrual) FromJSON(data []byte) error {
+	return json.Unmarshal(data, a)
+}
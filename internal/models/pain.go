@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// ISO 20022 pain.001 (CustomerCreditTransferInitiation) ve pain.002 (CustomerPaymentStatusReport) öğeleri;
+// yalnızca kullanılan alt küme tanımlıdır
+
+const (
+	Pain001MessageName = "pain.001.001.03"
+	Pain002Namespace   = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+)
+
+// pain.002 grup ve kalem durum kodları
+const (
+	PainStatusAccepted = "ACTC" // teknik doğrulama geçti, çalıştırma sürüyor
+	PainStatusPending  = "PDNG" // kalem kuyrukta
+	PainStatusSettled  = "ACSC" // kalem (ya da tüm grup) gerçekleşti
+	PainStatusPartial  = "PART" // grubun bir kısmı reddedildi
+	PainStatusRejected = "RJCT"
+)
+
+const (
+	PainTransferMethod = "TRF"         // desteklenen tek ödeme yöntemi (PmtMtd)
+	PainNotProvided    = "NOTPROVIDED" // EndToEndId verilmediğinde kullanılan değer
+	PainMaxIDLength    = 35            // MsgId, PmtInfId, InstrId, EndToEndId
+)
+
+// ISO harici durum neden kodları (ExternalStatusReason1Code)
+const (
+	PainReasonFormat         = "FF01" // dosya biçimi geçersiz
+	PainReasonDebtorAccount  = "AC02" // borçlu hesabı geçersiz
+	PainReasonCreditorAcct   = "AC01" // alacaklı tanımlayıcısı çözülemedi
+	PainReasonCreditorWallet = "AC03" // alacaklının bu para biriminde cüzdanı yok
+	PainReasonForbidden      = "AG01" // borçlu hesabı gönderene ait değil
+	PainReasonZeroAmount     = "AM01"
+	PainReasonAmount         = "AM02" // tutar izin verilmiyor (hassasiyet, limit)
+	PainReasonCurrency       = "AM03"
+	PainReasonFunds          = "AM04"
+	PainReasonDuplicate      = "AM05"
+	PainReasonControlSum     = "AM10"
+	PainReasonTxCount        = "AM18"
+	PainReasonDuplicateMsg   = "DU01"
+	PainReasonNarrative      = "NARR"
+)
+
+// Pain001Error: dosyanın tamamını reddeden doğrulama hatası
+type Pain001Error struct {
+	Code   string `json:"reason_code"`
+	Reason string `json:"error"`
+}
+
+func (e *Pain001Error) Error() string { return e.Reason }
+
+// Pain001Party: alacaklı; tanımlayıcı hesap yerine kurum ya da kişi kimliğinde de gelebilir
+type Pain001Party struct {
+	Nm         string `xml:"Nm"`
+	OrgOthrId  string `xml:"Id>OrgId>Othr>Id"`
+	PrvtOthrId string `xml:"Id>PrvtId>Othr>Id"`
+}
+
+type Pain001Tx struct {
+	PmtId struct {
+		InstrId    string `xml:"InstrId"`
+		EndToEndId string `xml:"EndToEndId"`
+	} `xml:"PmtId"`
+	InstdAmt CamtAmount   `xml:"Amt>InstdAmt"`
+	Cdtr     Pain001Party `xml:"Cdtr"`
+	CdtrAcct *struct {
+		Id CamtAccountID `xml:"Id"`
+	} `xml:"CdtrAcct"`
+	RmtInf *CamtRemittance `xml:"RmtInf"`
+}
+
+// CreditorIDs: alacaklı tanımlayıcıları öncelik sırasıyla (hesap, kurum kimliği, kişi kimliği)
+func (t Pain001Tx) CreditorIDs() []string {
+	var ids []string
+	if t.CdtrAcct != nil {
+		ids = append(ids, t.CdtrAcct.Id.String())
+	}
+	ids = append(ids, t.Cdtr.OrgOthrId, t.Cdtr.PrvtOthrId)
+	out := ids[:0]
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// Remittance: yapılandırılmamış ödeme açıklaması
+func (t Pain001Tx) Remittance() string {
+	if t.RmtInf == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(t.RmtInf.Ustrd, " "))
+}
+
+type Pain001PaymentInfo struct {
+	PmtInfId string       `xml:"PmtInfId"`
+	PmtMtd   string       `xml:"PmtMtd"`
+	NbOfTxs  string       `xml:"NbOfTxs"`
+	CtrlSum  string       `xml:"CtrlSum"`
+	Dbtr     Pain001Party `xml:"Dbtr"`
+	DbtrAcct struct {
+		Id  CamtAccountID `xml:"Id"`
+		Ccy string        `xml:"Ccy"`
+	} `xml:"DbtrAcct"`
+	CdtTrfTxInf []Pain001Tx `xml:"CdtTrfTxInf"`
+}
+
+type Pain001GroupHeader struct {
+	MsgId    string       `xml:"MsgId"`
+	CreDtTm  string       `xml:"CreDtTm"`
+	NbOfTxs  string       `xml:"NbOfTxs"`
+	CtrlSum  string       `xml:"CtrlSum"`
+	InitgPty Pain001Party `xml:"InitgPty"`
+}
+
+// Pain001Document: pain.001 belgesi; etiketler ad alanı içermediğinden 001.001.03 ve sonraki sürümler okunur
+type Pain001Document struct {
+	GrpHdr Pain001GroupHeader   `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	PmtInf []Pain001PaymentInfo `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+type Pain002Reason struct {
+	Cd       string `xml:"Rsn>Cd"`
+	AddtlInf string `xml:"AddtlInf,omitempty"`
+}
+
+type Pain002TxStatus struct {
+	StsId           string         `xml:"StsId"`
+	OrgnlInstrId    string         `xml:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndId string         `xml:"OrgnlEndToEndId"`
+	TxSts           string         `xml:"TxSts"`
+	StsRsnInf       *Pain002Reason `xml:"StsRsnInf,omitempty"`
+	InstdAmt        CamtAmount     `xml:"OrgnlTxRef>Amt>InstdAmt"`
+}
+
+type Pain002PaymentInfoStatus struct {
+	OrgnlPmtInfId string            `xml:"OrgnlPmtInfId"`
+	PmtInfSts     string            `xml:"PmtInfSts"`
+	TxInfAndSts   []Pain002TxStatus `xml:"TxInfAndSts"`
+}
+
+type Pain002GroupStatus struct {
+	OrgnlMsgId   string `xml:"OrgnlMsgId"`
+	OrgnlMsgNmId string `xml:"OrgnlMsgNmId"`
+	OrgnlNbOfTxs int    `xml:"OrgnlNbOfTxs"`
+	OrgnlCtrlSum string `xml:"OrgnlCtrlSum"`
+	GrpSts       string `xml:"GrpSts"`
+}
+
+// Pain002Document: müşteri ödeme durum raporu
+type Pain002Document struct {
+	XMLName xml.Name `xml:"Document"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	GrpHdr  struct {
+		MsgId   string `xml:"MsgId"`
+		CreDtTm string `xml:"CreDtTm"`
+	} `xml:"CstmrPmtStsRpt>GrpHdr"`
+	OrgnlGrpInfAndSts Pain002GroupStatus         `xml:"CstmrPmtStsRpt>OrgnlGrpInfAndSts"`
+	OrgnlPmtInfAndSts []Pain002PaymentInfoStatus `xml:"CstmrPmtStsRpt>OrgnlPmtInfAndSts"`
+}
+
+// PainGroupStatus: kalem durumlarından grup durumu; bekleyen kalem varken reddedilen yoksa ACTC, varsa PART
+func PainGroupStatus(statuses []string) string {
+	var rejected, pending int
+	for _, s := range statuses {
+		if s == PainStatusRejected {
+			rejected++
+		} else if s != PainStatusSettled {
+			pending++
+		}
+	}
+	switch {
+	case rejected == len(statuses):
+		return PainStatusRejected
+	case rejected > 0:
+		return PainStatusPartial
+	case pending > 0:
+		return PainStatusAccepted
+	}
+	return PainStatusSettled
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Toplu ödeme talimatı çalıştırma durumları
+const (
+	PaymentInitiationProcessing = "processing"
+	PaymentInitiationCompleted  = "completed"
+)
+
+// OriginPaymentInitiation: pain.001 kalemlerinin ürettiği transferlerin origin tipi (seq = dosyadaki kalem sırası)
+const OriginPaymentInitiation = "payment_initiation"
+
+// PaymentInitiation: kurumsal müşterinin yüklediği pain.001 dosyası; GroupStatus pain.002 GrpSts değeridir.
+// Aynı gönderenin aynı MsgId'li ikinci dosyası reddedilir.
+type PaymentInitiation struct {
+	ID            int                   `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	SubmittedBy   int                   `gorm:"column:submitted_by;index" db:"submitted_by" json:"submitted_by"`
+	MsgID         string                `gorm:"column:msg_id" db:"msg_id" json:"msg_id"`
+	Filename      string                `gorm:"column:filename" db:"filename" json:"filename,omitempty"`
+	InitiatedBy   string                `gorm:"column:initiated_by" db:"initiated_by" json:"initiated_by,omitempty"` // InitgPty/Nm
+	TxCount       int                   `gorm:"column:tx_count" db:"tx_count" json:"tx_count"`
+	ControlSum    Money                 `gorm:"column:control_sum;type:numeric(20,4)" db:"control_sum" json:"control_sum"`
+	Status        string                `gorm:"column:status;index" db:"status" json:"status"`
+	GroupStatus   string                `gorm:"column:group_status" db:"group_status" json:"group_status"`
+	SettledCount  int                   `gorm:"column:settled_count;default:0" db:"settled_count" json:"settled_count"`
+	RejectedCount int                   `gorm:"column:rejected_count;default:0" db:"rejected_count" json:"rejected_count"`
+	CreatedAt     time.Time             `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	CompletedAt   *time.Time            `gorm:"column:completed_at" db:"completed_at" json:"completed_at,omitempty"`
+	Txs           []PaymentInitiationTx `gorm:"-" json:"transactions,omitempty"`
+}
+
+// PaymentInitiationTx: pain.001 CdtTrfTxInf kalemi; doğrulamada reddedilen kalemler çalıştırılmaz (RJCT),
+// diğerleri PDNG olarak kuyruğa girer ve transfer sonucuyla ACSC ya da RJCT olur
+type PaymentInitiationTx struct {
+	ID              int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	InitiationID    int       `gorm:"column:initiation_id;index" db:"initiation_id" json:"initiation_id"`
+	Seq             int       `gorm:"column:seq" db:"seq" json:"seq"`
+	PmtInfID        string    `gorm:"column:pmt_inf_id" db:"pmt_inf_id" json:"pmt_inf_id"`
+	InstrID         string    `gorm:"column:instr_id" db:"instr_id" json:"instr_id,omitempty"`
+	EndToEndID      string    `gorm:"column:end_to_end_id" db:"end_to_end_id" json:"end_to_end_id"`
+	FromUserID      int       `gorm:"column:from_user_id" db:"from_user_id" json:"from_user_id"`
+	ToUserID        *int      `gorm:"column:to_user_id" db:"to_user_id" json:"to_user_id,omitempty"`
+	CreditorName    string    `gorm:"column:creditor_name" db:"creditor_name" json:"creditor_name,omitempty"`
+	CreditorAccount string    `gorm:"column:creditor_account" db:"creditor_account" json:"creditor_account,omitempty"`
+	Amount          Money     `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency        string    `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Remittance      string    `gorm:"column:remittance" db:"remittance" json:"remittance,omitempty"`
+	Status          string    `gorm:"column:status" db:"status" json:"status"`
+	ReasonCode      string    `gorm:"column:reason_code" db:"reason_code" json:"reason_code,omitempty"`
+	Reason          string    `gorm:"column:reason" db:"reason" json:"reason,omitempty"`
+	TransactionID   *int      `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// Options: kalemin transfer seçenekleri; origin tekrar çalıştırmada ikinci transferi, external_ref (EndToEndId)
+// aynı gönderenin başka dosyada tekrarlanan ödemesini engeller
+func (t PaymentInitiationTx) Options() TxOptions {
+	meta := &TxMeta{Memo: t.Remittance, Category: "payout"}
+	if t.EndToEndID != "" && t.EndToEndID != PainNotProvided {
+		ref := t.EndToEndID
+		meta.ExternalRef = &ref
+	}
+	return TxOptions{Origin: &TxOrigin{Type: OriginPaymentInitiation, ID: t.InitiationID, Seq: t.Seq}, Meta: meta}
+}
+
+// Tally: kalem durumlarından sayaçları ve grup durumunu hesaplar
+func (p *PaymentInitiation) Tally() {
+	p.SettledCount, p.RejectedCount = 0, 0
+	statuses := make([]string, len(p.Txs))
+	for i, t := range p.Txs {
+		statuses[i] = t.Status
+		switch t.Status {
+		case PainStatusSettled:
+			p.SettledCount++
+		case PainStatusRejected:
+			p.RejectedCount++
+		}
+	}
+	p.GroupStatus = PainGroupStatus(statuses)
+}
+
+// Pain002: talimatın güncel durumunu pain.002 raporu olarak döner; kalemler PmtInfId'ye göre dosyadaki sırayla gruplanır
+func (p *PaymentInitiation) Pain002(now time.Time) Pain002Document {
+	doc := Pain002Document{Xmlns: Pain002Namespace}
+	doc.GrpHdr.MsgId = "STS-" + strconv.Itoa(p.ID) + "-" + now.UTC().Format("20060102150405")
+	doc.GrpHdr.CreDtTm = now.UTC().Format("2006-01-02T15:04:05Z")
+	doc.OrgnlGrpInfAndSts = Pain002GroupStatus{
+		OrgnlMsgId: p.MsgID, OrgnlMsgNmId: Pain001MessageName, OrgnlNbOfTxs: p.TxCount,
+		OrgnlCtrlSum: p.ControlSum.String(), GrpSts: p.GroupStatus,
+	}
+	index := map[string]int{}
+	groups := map[string][]string{}
+	for _, t := range p.Txs {
+		i, ok := index[t.PmtInfID]
+		if !ok {
+			i = len(doc.OrgnlPmtInfAndSts)
+			index[t.PmtInfID] = i
+			doc.OrgnlPmtInfAndSts = append(doc.OrgnlPmtInfAndSts, Pain002PaymentInfoStatus{OrgnlPmtInfId: t.PmtInfID})
+		}
+		st := Pain002TxStatus{
+			StsId: strconv.Itoa(t.ID), OrgnlInstrId: t.InstrID, OrgnlEndToEndId: t.EndToEndID, TxSts: t.Status,
+			InstdAmt: CamtAmount{Ccy: t.Currency, Value: t.Amount.String()},
+		}
+		if t.ReasonCode != "" {
+			st.StsRsnInf = &Pain002Reason{Cd: t.ReasonCode, AddtlInf: t.Reason}
+		}
+		doc.OrgnlPmtInfAndSts[i].TxInfAndSts = append(doc.OrgnlPmtInfAndSts[i].TxInfAndSts, st)
+		groups[t.PmtInfID] = append(groups[t.PmtInfID], t.Status)
+	}
+	for i := range doc.OrgnlPmtInfAndSts {
+		doc.OrgnlPmtInfAndSts[i].PmtInfSts = PainGroupStatus(groups[doc.OrgnlPmtInfAndSts[i].OrgnlPmtInfId])
+	}
+	return doc
+}
+
+// WritePain002: pain.002 raporunu XML belgesi olarak yazar
+func (p *PaymentInitiation) WritePain002(w io.Writer, now time.Time) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(p.Pain002(now))
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Ödeme isteği durumları
+const (
+	PaymentRequestPending   = "pending"
+	PaymentRequestAccepting = "accepting" // ödeyen kabul etti, transfer sürüyor
+	PaymentRequestAccepted  = "accepted"
+	PaymentRequestDeclined  = "declined"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired"
+)
+
+// OriginPaymentRequest: kabul edilen ödeme isteğinin transferi için origin tipi (istek başına tek transfer)
+const OriginPaymentRequest = "payment_request"
+
+// PaymentRequest: RequesterID'nin PayerID'den para istemesi.
+// Ödeyen kabul eder ya da reddeder, isteyen iptal eder; ExpiresAt'e kadar yanıtlanmazsa süresi dolar.
+type PaymentRequest struct {
+	ID            int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	RequesterID   int        `gorm:"column:requester_id;index" db:"requester_id" json:"requester_id"`
+	PayerID       int        `gorm:"column:payer_id;index" db:"payer_id" json:"payer_id"`
+	Amount        Money      `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency      string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Note          string     `gorm:"column:note" db:"note" json:"note,omitempty"`
+	Status        string     `gorm:"column:status;index" db:"status" json:"status"`
+	TransactionID *int       `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at" db:"expires_at" json:"expires_at"`
+	RespondedAt   *time.Time `gorm:"column:responded_at" db:"responded_at" json:"responded_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
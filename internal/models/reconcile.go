@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ReconcileItem: bir cüzdanın ledger'dan yeniden hesaplanan (Expected) ve balances'taki (Actual) bakiyesi.
+// Delta = Actual - Expected; sıfırdan farklıysa projeksiyon ledger'dan kopmuştur.
+type ReconcileItem struct {
+	UserID        int    `gorm:"column:user_id" json:"user_id"`
+	Currency      string `gorm:"column:currency" json:"currency"`
+	Expected      Money  `gorm:"column:expected" json:"expected"`
+	Actual        Money  `gorm:"column:actual" json:"actual"`
+	Delta         Money  `gorm:"column:delta" json:"delta"`
+	Corrected     bool   `gorm:"-" json:"corrected"`
+	TransactionID *int   `gorm:"-" json:"transaction_id,omitempty"`
+}
+
+// İşlem mutabakatı uyuşmazlık nedenleri
+const (
+	TxMismatchUnbalanced  = "unbalanced"   // bacaklar para biriminde sıfıra toplanmıyor
+	TxMismatchMissingLegs = "missing_legs" // işlemin hiç ledger bacağı yok
+	TxMismatchWalletLeg   = "wallet_leg"   // cüzdan bacağı işlemin from/to/amount alanlarıyla uyuşmuyor
+)
+
+// TxReconcileItem: ledger bacaklarıyla uyuşmayan işlem. UserID boşsa uyuşmazlık para biriminin tamamındadır;
+// Expected işlem kaydından beklenen, Actual ledger'daki toplamdır.
+type TxReconcileItem struct {
+	TransactionID int    `gorm:"column:transaction_id" json:"transaction_id"`
+	Type          string `gorm:"column:type" json:"type"`
+	Reason        string `gorm:"column:reason" json:"reason"`
+	UserID        *int   `gorm:"column:user_id" json:"user_id,omitempty"`
+	Currency      string `gorm:"column:currency" json:"currency"`
+	Expected      Money  `gorm:"column:expected" json:"expected"`
+	Actual        Money  `gorm:"column:actual" json:"actual"`
+}
+
+// CurrencyDrift: para birimi bazında toplam sapma (mutlak değer) ve sapan cüzdan sayısı
+type CurrencyDrift struct {
+	Currency string `json:"currency"`
+	Wallets  int    `json:"wallets"`
+	AbsDelta Money  `json:"abs_delta"`
+}
+
+// ReconcileReport: mutabakat çalıştırmasının sonucu
+// İşlem uyuşmazlıkları otomatik düzeltilmez; Applied yalnızca cüzdan sapmalarını kapsar.
+type ReconcileReport struct {
+	At           time.Time         `json:"at"`
+	Wallets      int64             `json:"wallets"`
+	Mismatches   []ReconcileItem   `json:"mismatches"`
+	Drift        []CurrencyDrift   `json:"drift"`
+	Transactions int64             `json:"transactions"`
+	TxMismatches []TxReconcileItem `json:"transaction_mismatches"`
+	Applied      bool              `json:"applied"`
+}
+
+// WriteCSV: uyuşmazlıkları CSV olarak yazar (user_id,currency,expected,actual,delta,corrected,transaction_id)
+func (r *ReconcileReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user_id", "currency", "expected", "actual", "delta", "corrected", "transaction_id"}); err != nil {
+		return err
+	}
+	for _, m := range r.Mismatches {
+		txID := ""
+		if m.TransactionID != nil {
+			txID = strconv.Itoa(*m.TransactionID)
+		}
+		if err := cw.Write([]string{strconv.Itoa(m.UserID), m.Currency, m.Expected.String(), m.Actual.String(), m.Delta.String(), strconv.FormatBool(m.Corrected), txID}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Zamanlanmış transfer durumları
+const (
+	ScheduledPending    = "pending"
+	ScheduledProcessing = "processing"
+	ScheduledCompleted  = "completed"
+	ScheduledFailed     = "failed"
+	ScheduledCancelled  = "cancelled"
+)
+
+// OriginScheduledTransfer: zamanlanmış transferin ürettiği işlemlerin origin tipi
+const OriginScheduledTransfer = "scheduled_transfer"
+
+// ScheduledTransfer: ileri tarihli transfer talimatı.
+// Scheduler NextRunAt geldiğinde kaydı kilitleyip (SKIP LOCKED) işler; LockedUntil süresi dolan
+// "processing" kayıtlar (çöken replika) tekrar alınabilir.
+type ScheduledTransfer struct {
+	ID            int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	UserID        int        `gorm:"column:user_id;index" db:"user_id" json:"user_id"`
+	ToUserID      int        `gorm:"column:to_user_id" db:"to_user_id" json:"to_user_id"`
+	Amount        Money      `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency      string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	ExecuteAt     time.Time  `gorm:"column:execute_at" db:"execute_at" json:"execute_at"`
+	NextRunAt     time.Time  `gorm:"column:next_run_at;index" db:"next_run_at" json:"next_run_at"`
+	Status        string     `gorm:"column:status;index" db:"status" json:"status"`
+	Attempts      int        `gorm:"column:attempts;default:0" db:"attempts" json:"attempts"`
+	LastError     string     `gorm:"column:last_error" db:"last_error" json:"last_error,omitempty"`
+	TransactionID *int       `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	LockedUntil   *time.Time `gorm:"column:locked_until" db:"locked_until" json:"-"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// ScheduledTransferRun: tek bir çalıştırma denemesinin sonucu
+type ScheduledTransferRun struct {
+	ID                  int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	ScheduledTransferID int       `gorm:"column:scheduled_transfer_id;index" db:"scheduled_transfer_id" json:"scheduled_transfer_id"`
+	Attempt             int       `gorm:"column:attempt" db:"attempt" json:"attempt"`
+	Status              string    `gorm:"column:status" db:"status" json:"status"` // succeeded | retry | failed
+	Error               string    `gorm:"column:error" db:"error" json:"error,omitempty"`
+	TransactionID       *int      `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	ExecutedAt          time.Time `gorm:"column:executed_at" db:"executed_at" json:"executed_at"`
+}
+
+// Çalıştırma sonuçları
+const (
+	RunSucceeded = "succeeded"
+	RunRetry     = "retry"
+	RunFailed    = "failed"
+)
+
+// JSON helper’ları
+func (s *ScheduledTransfer) ToJSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+func (s *ScheduledTransfer) FromJSON(data []byte) error {
+	return json.Unmarshal(data, s)
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// Bölünmüş ödeme tipleri: ebeveyn işlem gönderenden tek seferde düşer, her alıcı payı ParentID ile bağlı çocuk kayıttır
+const (
+	TxTypeSplit     = "split"
+	TxTypeSplitPart = "split_part"
+)
+
+// SplitPart: bölünmüş ödemenin bir alıcısı. Amount ya da Percent (ör. "33.3333") verilir; karışık kullanılamaz.
+type SplitPart struct {
+	ToUserID int    `json:"to_user_id"`
+	Amount   Money  `json:"amount,omitempty"`
+	Percent  *Money `json:"percent,omitempty"`
+}
+
+// oneHundred: Percent alanında %100
+var oneHundred = Money(100 * moneyFactor)
+
+// ResolveSplit: payların tutarlarını hesaplar ve doğrular.
+// Tutarla verilen paylar toplamı total'e eşit olmalıdır (total sıfırsa paylar toplamı kullanılır).
+// Yüzdeyle verilen paylar %100'e tamamlanmalıdır; her pay minor unit'e aşağı yuvarlanır ve kalan birimler
+// en büyük küsurat sırasıyla (eşitlikte listedeki sıra) dağıtılır, böylece sonuç deterministik ve toplam tamdır.
+func ResolveSplit(total Money, parts []SplitPart, cur Currency) (Money, error) {
+	if len(parts) == 0 {
+		return 0, errors.New("recipients must not be empty")
+	}
+	byPercent := parts[0].Percent != nil
+	for _, p := range parts {
+		if (p.Percent != nil) != byPercent {
+			return 0, errors.New("recipients must all use amount or all use percent")
+		}
+	}
+	if !byPercent {
+		var sum Money
+		for _, p := range parts {
+			if !p.Amount.IsPositive() {
+				return 0, errors.New("recipient amount must be positive")
+			}
+			if err := cur.CheckPrecision(p.Amount); err != nil {
+				return 0, err
+			}
+			sum += p.Amount
+		}
+		if total.IsZero() {
+			total = sum
+		}
+		if sum != total {
+			return 0, errors.New("recipient amounts must add up to amount")
+		}
+		return total, nil
+	}
+
+	if !total.IsPositive() {
+		return 0, errors.New("amount must be positive")
+	}
+	var pct Money
+	for _, p := range parts {
+		if !p.Percent.IsPositive() {
+			return 0, errors.New("recipient percent must be positive")
+		}
+		pct += *p.Percent
+	}
+	if pct != oneHundred {
+		return 0, errors.New("recipient percents must add up to 100")
+	}
+	exp := cur.Exponent
+	if exp > MoneyScale {
+		exp = MoneyScale
+	}
+	unit := pow10(MoneyScale - exp)
+	type share struct {
+		idx int
+		rem *big.Int
+	}
+	shares := make([]share, len(parts))
+	var allocated Money
+	den := new(big.Int).Mul(big.NewInt(int64(oneHundred)), big.NewInt(unit))
+	for i, p := range parts {
+		num := new(big.Int).Mul(big.NewInt(int64(total)), big.NewInt(int64(*p.Percent)))
+		q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+		parts[i].Amount = Money(q.Int64() * unit)
+		allocated += parts[i].Amount
+		shares[i] = share{idx: i, rem: rem}
+	}
+	sort.SliceStable(shares, func(a, b int) bool { return shares[a].rem.Cmp(shares[b].rem) > 0 })
+	for i := 0; allocated < total; i = (i + 1) % len(shares) {
+		parts[shares[i].idx].Amount += Money(unit)
+		allocated += Money(unit)
+	}
+	for _, p := range parts {
+		if !p.Amount.IsPositive() {
+			return 0, errors.New("amount too small to split")
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,98 @@
+package models
+
+import "testing"
+
+func pct(s string) *Money {
+	m := MustParseMoney(s)
+	return &m
+}
+
+func TestResolveSplitPercent(t *testing.T) {
+	usd := Currency{Code: "USD", Exponent: 2}
+	jpy := Currency{Code: "JPY", Exponent: 0}
+	tests := []struct {
+		name     string
+		total    string
+		cur      Currency
+		percents []string
+		want     []string
+	}{
+		{"even", "100", usd, []string{"50", "50"}, []string{"50.00", "50.00"}},
+		{"thirds: remainder to first on tie", "100", usd, []string{"33.3333", "33.3333", "33.3334"}, []string{"33.33", "33.33", "33.34"}},
+		{"equal thirds of one cent", "0.10", usd, []string{"33.3333", "33.3333", "33.3334"}, []string{"0.03", "0.03", "0.04"}},
+		{"largest remainder wins", "10", usd, []string{"33.335", "33.335", "33.33"}, []string{"3.34", "3.33", "3.33"}},
+		{"ties keep list order", "1", usd, []string{"50", "25", "25"}, []string{"0.50", "0.25", "0.25"}},
+		{"zero-exponent currency", "100", jpy, []string{"33.3333", "33.3333", "33.3334"}, []string{"33", "33", "34"}},
+		{"remainder spread over many parts", "1", usd, []string{"14.2857", "14.2857", "14.2857", "14.2857", "14.2857", "14.2857", "14.2858"}, []string{"0.15", "0.14", "0.14", "0.14", "0.14", "0.14", "0.15"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := make([]SplitPart, len(tt.percents))
+			for i, p := range tt.percents {
+				parts[i] = SplitPart{ToUserID: i + 1, Percent: pct(p)}
+			}
+			total := MustParseMoney(tt.total)
+			got, err := ResolveSplit(total, parts, tt.cur)
+			if err != nil {
+				t.Fatalf("ResolveSplit error = %v", err)
+			}
+			if got != total {
+				t.Fatalf("ResolveSplit total = %s, want %s", got, total)
+			}
+			var sum Money
+			for i, p := range parts {
+				sum += p.Amount
+				if want := MustParseMoney(tt.want[i]); p.Amount != want {
+					t.Errorf("part %d = %s, want %s", i, p.Amount, want)
+				}
+				if err := tt.cur.CheckPrecision(p.Amount); err != nil {
+					t.Errorf("part %d: %v", i, err)
+				}
+			}
+			if sum != total {
+				t.Errorf("sum of parts = %s, want %s", sum, total)
+			}
+		})
+	}
+}
+
+func TestResolveSplitAmounts(t *testing.T) {
+	usd := Currency{Code: "USD", Exponent: 2}
+	parts := []SplitPart{{ToUserID: 1, Amount: MustParseMoney("10")}, {ToUserID: 2, Amount: MustParseMoney("2.5")}}
+	got, err := ResolveSplit(0, parts, usd)
+	if err != nil || got != MustParseMoney("12.5") {
+		t.Fatalf("ResolveSplit(0) = %s, %v; want 12.50", got, err)
+	}
+	if got, err := ResolveSplit(MustParseMoney("12.5"), parts, usd); err != nil || got != MustParseMoney("12.5") {
+		t.Fatalf("ResolveSplit(12.5) = %s, %v; want 12.50", got, err)
+	}
+}
+
+func TestResolveSplitErrors(t *testing.T) {
+	usd := Currency{Code: "USD", Exponent: 2}
+	tests := []struct {
+		name  string
+		total string
+		parts []SplitPart
+		want  string
+	}{
+		{"no recipients", "10", nil, "recipients must not be empty"},
+		{"mixed", "10", []SplitPart{{ToUserID: 1, Amount: MustParseMoney("5")}, {ToUserID: 2, Percent: pct("50")}}, "recipients must all use amount or all use percent"},
+		{"amounts do not add up", "10", []SplitPart{{ToUserID: 1, Amount: MustParseMoney("5")}, {ToUserID: 2, Amount: MustParseMoney("4")}}, "recipient amounts must add up to amount"},
+		{"zero amount", "0", []SplitPart{{ToUserID: 1, Amount: 0}}, "recipient amount must be positive"},
+		{"negative amount", "0", []SplitPart{{ToUserID: 1, Amount: MustParseMoney("-1")}}, "recipient amount must be positive"},
+		{"sub-cent amount", "0", []SplitPart{{ToUserID: 1, Amount: MustParseMoney("0.001")}}, "USD amounts support at most 2 decimal places"},
+		{"percent without total", "0", []SplitPart{{ToUserID: 1, Percent: pct("100")}}, "amount must be positive"},
+		{"percents under 100", "10", []SplitPart{{ToUserID: 1, Percent: pct("50")}, {ToUserID: 2, Percent: pct("49.9999")}}, "recipient percents must add up to 100"},
+		{"zero percent", "10", []SplitPart{{ToUserID: 1, Percent: pct("100")}, {ToUserID: 2, Percent: pct("0")}}, "recipient percent must be positive"},
+		{"too small to split", "0.01", []SplitPart{{ToUserID: 1, Percent: pct("50")}, {ToUserID: 2, Percent: pct("25")}, {ToUserID: 3, Percent: pct("25")}}, "amount too small to split"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResolveSplit(MustParseMoney(tt.total), tt.parts, usd)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ResolveSplit error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
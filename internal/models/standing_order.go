@@ -0,0 +1,145 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Talimat sıklıkları
+const (
+	FrequencyWeekly          = "weekly"            // StartAt'in haftanın gününde, haftada bir
+	FrequencyMonthly         = "monthly"           // her ayın DayOfMonth günü (ay daha kısaysa ayın son günü)
+	FrequencyLastBusinessDay = "last_business_day" // her ayın son iş günü (Pzt-Cum)
+)
+
+// Talimat durumları
+const (
+	StandingOrderActive    = "active"
+	StandingOrderPaused    = "paused"
+	StandingOrderCancelled = "cancelled"
+	StandingOrderCompleted = "completed"
+)
+
+// OriginStandingOrder: düzenli talimatın ürettiği işlemlerin origin tipi (seq = occurrence numarası)
+const OriginStandingOrder = "standing_order"
+
+// StandingOrder: düzenli (tekrarlayan) transfer talimatı.
+// Çalıştırma saati StartAt'in saatidir (UTC). EndAt ya da MaxOccurrences'a ulaşınca tamamlanır.
+type StandingOrder struct {
+	ID             int        `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	UserID         int        `gorm:"column:user_id;index" db:"user_id" json:"user_id"`
+	ToUserID       int        `gorm:"column:to_user_id" db:"to_user_id" json:"to_user_id"`
+	Amount         Money      `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency       string     `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	Frequency      string     `gorm:"column:frequency" db:"frequency" json:"frequency"`
+	DayOfMonth     int        `gorm:"column:day_of_month" db:"day_of_month" json:"day_of_month,omitempty"`
+	StartAt        time.Time  `gorm:"column:start_at" db:"start_at" json:"start_at"`
+	EndAt          *time.Time `gorm:"column:end_at" db:"end_at" json:"end_at,omitempty"`
+	MaxOccurrences *int       `gorm:"column:max_occurrences" db:"max_occurrences" json:"max_occurrences,omitempty"`
+	Occurrences    int        `gorm:"column:occurrences;default:0" db:"occurrences" json:"occurrences"`
+	NextRunAt      *time.Time `gorm:"column:next_run_at;index" db:"next_run_at" json:"next_run_at,omitempty"`
+	Status         string     `gorm:"column:status;index" db:"status" json:"status"`
+	LastError      string     `gorm:"column:last_error" db:"last_error" json:"last_error,omitempty"`
+	LockedUntil    *time.Time `gorm:"column:locked_until" db:"locked_until" json:"-"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" db:"updated_at" json:"updated_at"`
+}
+
+// StandingOrderRun: bir occurrence'ın sonucu. Skipped, kesinti sırasında kaçırılıp tek seferde telafi edilen occurrence sayısıdır.
+type StandingOrderRun struct {
+	ID              int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	StandingOrderID int       `gorm:"column:standing_order_id;index" db:"standing_order_id" json:"standing_order_id"`
+	Seq             int       `gorm:"column:seq" db:"seq" json:"seq"`
+	ScheduledFor    time.Time `gorm:"column:scheduled_for" db:"scheduled_for" json:"scheduled_for"`
+	Status          string    `gorm:"column:status" db:"status" json:"status"` // succeeded | failed
+	Error           string    `gorm:"column:error" db:"error" json:"error,omitempty"`
+	Skipped         int       `gorm:"column:skipped;default:0" db:"skipped" json:"skipped,omitempty"`
+	TransactionID   *int      `gorm:"column:transaction_id" db:"transaction_id" json:"transaction_id,omitempty"`
+	ExecutedAt      time.Time `gorm:"column:executed_at" db:"executed_at" json:"executed_at"`
+}
+
+// Validate: sıklık kuralları
+func (o *StandingOrder) Validate() error {
+	var problems []string
+	switch o.Frequency {
+	case FrequencyWeekly, FrequencyLastBusinessDay:
+	case FrequencyMonthly:
+		if o.DayOfMonth < 1 || o.DayOfMonth > 31 {
+			problems = append(problems, "day_of_month must be between 1 and 31")
+		}
+	default:
+		problems = append(problems, "frequency must be weekly, monthly or last_business_day")
+	}
+	if o.EndAt != nil && !o.EndAt.After(o.StartAt) {
+		problems = append(problems, "end_at must be after start_at")
+	}
+	if o.MaxOccurrences != nil && *o.MaxOccurrences < 1 {
+		problems = append(problems, "max_occurrences must be > 0")
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// OccurrenceAfter: t'den sonraki (t hariç) ilk çalıştırma zamanı; StartAt'ten önce olamaz
+func (o *StandingOrder) OccurrenceAfter(t time.Time) time.Time {
+	start := o.StartAt.UTC()
+	ref := t.UTC()
+	if ref.Before(start) {
+		ref = start.Add(-time.Nanosecond)
+	}
+	if o.Frequency == FrequencyWeekly {
+		const week = 7 * 24 * time.Hour
+		k := ref.Sub(start)/week + 1
+		if ref.Before(start) {
+			k = 0
+		}
+		return start.Add(k * week)
+	}
+	for y, m := ref.Year(), ref.Month(); ; m++ {
+		if c := o.occurrenceIn(y, m); c.After(ref) && !c.Before(start) {
+			return c
+		}
+	}
+}
+
+// Due: verilen andan sonra çalıştırılacak occurrence kaldı mı (EndAt / MaxOccurrences)
+func (o *StandingOrder) Due(next time.Time) bool {
+	if o.MaxOccurrences != nil && o.Occurrences >= *o.MaxOccurrences {
+		return false
+	}
+	return o.EndAt == nil || !next.After(*o.EndAt)
+}
+
+// occurrenceIn: y/m ayındaki occurrence (time.Date ay taşmasını normalize eder)
+func (o *StandingOrder) occurrenceIn(y int, m time.Month) time.Time {
+	start := o.StartAt.UTC()
+	lastDay := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC)
+	day := lastDay.Day()
+	switch o.Frequency {
+	case FrequencyMonthly:
+		if o.DayOfMonth < day {
+			day = o.DayOfMonth
+		}
+	case FrequencyLastBusinessDay:
+		switch lastDay.Weekday() {
+		case time.Saturday:
+			day--
+		case time.Sunday:
+			day -= 2
+		}
+	}
+	return time.Date(lastDay.Year(), lastDay.Month(), day, start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+}
+
+// JSON helper’ları
+func (o *StandingOrder) ToJSON() ([]byte, error) {
+	return json.Marshal(o)
+}
+
+func (o *StandingOrder) FromJSON(data []byte) error {
+	return json.Unmarshal(data, o)
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StatementLine: ekstre dönemindeki bir işlemin cüzdana etkisi ve sonrasındaki bakiye.
+// Amount işaretlidir (giriş pozitif, çıkış negatif); ücret gibi bağlı işlemler ayrı satırdır.
+type StatementLine struct {
+	TransactionID  int       `gorm:"column:transaction_id" json:"transaction_id"`
+	PostedAt       time.Time `gorm:"column:posted_at" json:"posted_at"`
+	Type           string    `gorm:"column:type" json:"type"`
+	CounterpartyID *int      `gorm:"column:counterparty_id" json:"counterparty_id,omitempty"`
+	Amount         Money     `gorm:"column:amount" json:"amount"`
+	Balance        Money     `gorm:"-" json:"balance"`
+}
+
+// StatementTotal: dönem içinde bir işlem tipinin adedi, girişleri ve çıkışları
+type StatementTotal struct {
+	Type    string `json:"type"`
+	Count   int    `json:"count"`
+	Credits Money  `json:"credits"`
+	Debits  Money  `json:"debits"`
+}
+
+// Statement: bir cüzdanın dönem ekstresi. [PeriodStart, PeriodEnd) aralığını kapsar.
+// Content, ekstre gövdesinin (bakiyeler, satırlar, toplamlar) üretildiği andaki JSON'udur ve ContentHash
+// onun SHA-256'sıdır; kayıt bir kez yazılır, sonradan değiştirilmez.
+type Statement struct {
+	ID             int              `gorm:"column:id;primaryKey" db:"id" json:"id"`
+	UserID         int              `gorm:"column:user_id;index" db:"user_id" json:"user_id"`
+	Currency       string           `gorm:"column:currency;type:char(3)" db:"currency" json:"currency"`
+	PeriodStart    time.Time        `gorm:"column:period_start;type:date" db:"period_start" json:"period_start"`
+	PeriodEnd      time.Time        `gorm:"column:period_end;type:date" db:"period_end" json:"period_end"`
+	OpeningBalance Money            `gorm:"column:opening_balance;type:numeric(20,4)" db:"opening_balance" json:"opening_balance"`
+	ClosingBalance Money            `gorm:"column:closing_balance;type:numeric(20,4)" db:"closing_balance" json:"closing_balance"`
+	LineCount      int              `gorm:"column:line_count" db:"line_count" json:"line_count"`
+	Content        string           `gorm:"column:content;type:text" db:"content" json:"-"`
+	ContentHash    string           `gorm:"column:content_hash;type:char(64)" db:"content_hash" json:"content_hash"`
+	GeneratedAt    time.Time        `gorm:"column:generated_at" db:"generated_at" json:"generated_at"`
+	Lines          []StatementLine  `gorm:"-" json:"lines,omitempty"`
+	Totals         []StatementTotal `gorm:"-" json:"totals,omitempty"`
+}
+
+// statementBody: hash'lenen ve saklanan ekstre içeriği
+type statementBody struct {
+	UserID         int              `json:"user_id"`
+	Currency       string           `json:"currency"`
+	PeriodStart    string           `json:"period_start"`
+	PeriodEnd      string           `json:"period_end"`
+	OpeningBalance Money            `json:"opening_balance"`
+	ClosingBalance Money            `json:"closing_balance"`
+	Lines          []StatementLine  `json:"lines"`
+	Totals         []StatementTotal `json:"totals"`
+}
+
+// BuildStatement: açılış bakiyesinden başlayarak satırların yürüyen bakiyesini, kapanışı ve tip toplamlarını hesaplar
+func BuildStatement(userID int, currency string, start, end time.Time, opening Money, lines []StatementLine) *Statement {
+	s := &Statement{UserID: userID, Currency: currency, PeriodStart: start, PeriodEnd: end, OpeningBalance: opening, Lines: lines}
+	byType := make(map[string]*StatementTotal)
+	balance := opening
+	for i := range s.Lines {
+		l := &s.Lines[i]
+		balance += l.Amount
+		l.Balance = balance
+		t := byType[l.Type]
+		if t == nil {
+			t = &StatementTotal{Type: l.Type}
+			byType[l.Type] = t
+		}
+		t.Count++
+		if l.Amount.IsNegative() {
+			t.Debits += l.Amount.Neg()
+		} else {
+			t.Credits += l.Amount
+		}
+	}
+	s.ClosingBalance, s.LineCount = balance, len(s.Lines)
+	s.Totals = make([]StatementTotal, 0, len(byType))
+	for _, t := range byType {
+		s.Totals = append(s.Totals, *t)
+	}
+	sort.Slice(s.Totals, func(i, j int) bool { return s.Totals[i].Type < s.Totals[j].Type })
+	return s
+}
+
+// Seal: gövdeyi JSON'a yazar ve içerik hash'ini hesaplar
+func (s *Statement) Seal() error {
+	lines := s.Lines
+	if lines == nil {
+		lines = []StatementLine{}
+	}
+	b, err := json.Marshal(statementBody{
+		UserID: s.UserID, Currency: s.Currency,
+		PeriodStart: s.PeriodStart.Format("2006-01-02"), PeriodEnd: s.PeriodEnd.Format("2006-01-02"),
+		OpeningBalance: s.OpeningBalance, ClosingBalance: s.ClosingBalance, Lines: lines, Totals: s.Totals,
+	})
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(b)
+	s.Content, s.ContentHash = string(b), hex.EncodeToString(sum[:])
+	return nil
+}
+
+// Unseal: saklanan içerikten satırları ve toplamları yükler
+func (s *Statement) Unseal() error {
+	var body statementBody
+	if err := json.Unmarshal([]byte(s.Content), &body); err != nil {
+		return err
+	}
+	s.Lines, s.Totals = body.Lines, body.Totals
+	return nil
+}
+
+// Verify: saklanan içerik hash'le uyuşuyor mu
+func (s *Statement) Verify() bool {
+	sum := sha256.Sum256([]byte(s.Content))
+	return hex.EncodeToString(sum[:]) == s.ContentHash
+}
+
+// WriteCSV: satırları yürüyen bakiyeyle yazar; ilk ve son satırlar açılış/kapanış bakiyesidir
+func (s *Statement) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{
+		{"posted_at", "transaction_id", "type", "counterparty_id", "amount", "balance"},
+		{s.PeriodStart.Format(time.RFC3339), "", "opening_balance", "", "", s.OpeningBalance.String()},
+	}
+	for _, l := range s.Lines {
+		cp := ""
+		if l.CounterpartyID != nil {
+			cp = strconv.Itoa(*l.CounterpartyID)
+		}
+		rows = append(rows, []string{l.PostedAt.UTC().Format(time.RFC3339), strconv.Itoa(l.TransactionID), l.Type, cp, l.Amount.String(), l.Balance.String()})
+	}
+	rows = append(rows, []string{s.PeriodEnd.Format(time.RFC3339), "", "closing_balance", "", "", s.ClosingBalance.String()})
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+var statementHTML = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.UTC().Format("2006-01-02") },
+	"ts":   func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") },
+	"last": func(t time.Time) string { return t.AddDate(0, 0, -1).Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Statement {{.Currency}} {{date .PeriodStart}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; margin: 24px; }
+table { border-collapse: collapse; width: 100%; margin-top: 12px; }
+th, td { border-bottom: 1px solid #ddd; padding: 4px 6px; text-align: left; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+footer { margin-top: 16px; color: #666; font-size: 10px; word-break: break-all; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Account statement</h1>
+<p>User {{.UserID}} &middot; {{.Currency}} &middot; {{date .PeriodStart}} to {{last .PeriodEnd}}</p>
+<table>
+<tr><th>Opening balance</th><td class="num">{{.OpeningBalance}}</td></tr>
+<tr><th>Closing balance</th><td class="num">{{.ClosingBalance}}</td></tr>
+</table>
+<h2>Transactions</h2>
+<table>
+<tr><th>Date</th><th>ID</th><th>Type</th><th>Counterparty</th><th class="num">Amount</th><th class="num">Balance</th></tr>
+{{range .Lines}}<tr><td>{{ts .PostedAt}}</td><td>{{.TransactionID}}</td><td>{{.Type}}</td><td>{{if .CounterpartyID}}{{.CounterpartyID}}{{end}}</td><td class="num">{{.Amount}}</td><td class="num">{{.Balance}}</td></tr>
+{{else}}<tr><td colspan="6">No transactions in this period.</td></tr>
+{{end}}</table>
+<h2>Totals by type</h2>
+<table>
+<tr><th>Type</th><th class="num">Count</th><th class="num">Credits</th><th class="num">Debits</th></tr>
+{{range .Totals}}<tr><td>{{.Type}}</td><td class="num">{{.Count}}</td><td class="num">{{.Credits}}</td><td class="num">{{.Debits}}</td></tr>
+{{end}}</table>
+<footer>Statement #{{.ID}} generated {{ts .GeneratedAt}} UTC &middot; SHA-256 {{.ContentHash}}</footer>
+</body>
+</html>
+`))
+
+// WriteHTML: yazdırılabilir HTML ekstre
+func (s *Statement) WriteHTML(w io.Writer) error {
+	return statementHTML.Execute(w, s)
+}
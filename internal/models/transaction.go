@@ -5,14 +5,117 @@ import (
 	"time"
 )
 
+// Transaction durumları
+const (
+	TxStatusCompleted         = "completed"
+	TxStatusReversed          = "reversed"
+	TxStatusPartiallyRefunded = "partially_refunded"
+)
+
+// Bakiye düzeltme tipleri: manuel düzeltme (SetBalance) ve cüzdan açılış bakiyesi
+const (
+	TxTypeAdjustment     = "adjustment"
+	TxTypeOpeningBalance = "opening_balance"
+)
+
+// TxTypeOverdraftInterest: kullanılan kredi (overdraft) için günlük tahakkuk eden faiz
+const TxTypeOverdraftInterest = "overdraft_interest"
+
+// Telafi (compensating) transaction tipleri; ParentID orijinal işlemi gösterir
+const (
+	TxTypeReversal = "reversal"
+	TxTypeRefund   = "refund"
+)
+
 type Transaction struct {
 	ID        int       `gorm:"column:id;primaryKey" db:"id" json:"id"`
 	FromUser  int       `gorm:"column:from_user_id;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" db:"from_user_id" json:"from_user_id"`
 	ToUser    int       `gorm:"column:to_user_id;index;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" db:"to_user_id" json:"to_user_id"`
-	Amount    float64   `gorm:"column:amount;type:numeric(18,2)" db:"amount" json:"amount"`
+	Amount    Money     `gorm:"column:amount;type:numeric(20,4)" db:"amount" json:"amount"`
+	Currency  string    `gorm:"column:currency;type:char(3);default:USD;index" db:"currency" json:"currency"`
 	Type      string    `gorm:"column:type;index" db:"type" json:"type"`
 	Status    string    `gorm:"column:status;index" db:"status" json:"status"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime;index" db:"created_at" json:"created_at"`
+
+	// ParentID: telafi işlemlerinde (reversal/refund) orijinal transaction
+	ParentID *int `gorm:"column:parent_id;index" db:"parent_id" json:"parent_id,omitempty"`
+	// RefundedAmount: orijinal işlemde şimdiye kadar iade edilen tutar (Currency cinsinden)
+	RefundedAmount Money `gorm:"column:refunded_amount;type:numeric(20,4);default:0" db:"refunded_amount" json:"refunded_amount"`
+
+	// Origin: işlemi başlatan kaynak (zamanlanmış transfer vb.); (type, id, seq) benzersizdir
+	OriginType *string `gorm:"column:origin_type" db:"origin_type" json:"origin_type,omitempty"`
+	OriginID   *int    `gorm:"column:origin_id" db:"origin_id" json:"origin_id,omitempty"`
+	OriginSeq  *int    `gorm:"column:origin_seq" db:"origin_seq" json:"origin_seq,omitempty"`
+
+	// Çapraz kur transferi snapshot'ı (aynı para birimli hareketlerde boş).
+	// Kur tablosu sonradan değişse de bakiye/ekstre hesapları bu değerlerle tekrarlanabilir kalır.
+	CounterAmount   *Money  `gorm:"column:counter_amount;type:numeric(20,4)" db:"counter_amount" json:"counter_amount,omitempty"`
+	CounterCurrency *string `gorm:"column:counter_currency;type:char(3)" db:"counter_currency" json:"counter_currency,omitempty"`
+	FXRate          *Rate   `gorm:"column:fx_rate;type:numeric(20,8)" db:"fx_rate" json:"fx_rate,omitempty"`
+	FXMidRate       *Rate   `gorm:"column:fx_mid_rate;type:numeric(20,8)" db:"fx_mid_rate" json:"fx_mid_rate,omitempty"`
+	FXSpreadBps     *int    `gorm:"column:fx_spread_bps" db:"fx_spread_bps" json:"fx_spread_bps,omitempty"`
+	FXRateID        *int    `gorm:"column:fx_rate_id" db:"fx_rate_id" json:"fx_rate_id,omitempty"`
+
+	// Kullanıcı metadata'sı (TxMeta): memo, kategori ve etiketler sonradan sahibi tarafından düzenlenebilir
+	Memo        string  `gorm:"column:memo" db:"memo" json:"memo,omitempty"`
+	Category    string  `gorm:"column:category" db:"category" json:"category,omitempty"`
+	Tags        Tags    `gorm:"column:tags;type:jsonb" db:"tags" json:"tags,omitempty"`
+	ExternalRef *string `gorm:"column:external_ref" db:"external_ref" json:"external_ref,omitempty"`
+
+	// Legs: gruplanmış geçmişte ebeveyne bağlı çocuk kayıtlar (ör. bölünmüş ödeme payları); DB'de tutulmaz
+	Legs []*Transaction `gorm:"-" db:"-" json:"legs,omitempty"`
+}
+
+// CreditedAmount: alıcı tarafına geçen tutar ve para birimi (FX transferlerinde karşı tutar)
+func (t *Transaction) CreditedAmount() (Money, string) {
+	if t.CounterAmount != nil && t.CounterCurrency != nil {
+		return *t.CounterAmount, *t.CounterCurrency
+	}
+	return t.Amount, t.Currency
+}
+
+// TxOrigin: işlemi başlatan kaynak kayıt. Aynı kaynak aynı Seq ile ikinci kez işlem üretemez
+// (scheduler yeniden denemelerinde çift transferi önler).
+type TxOrigin struct {
+	Type string
+	ID   int
+	Seq  int
+}
+
+// TxOptions: atomik repo işlemlerine geçirilen opsiyonel bağlam
+type TxOptions struct {
+	Origin *TxOrigin
+	// Limit: gönderen için geçerli limitler; repo DB transaction'ı içinde kullanıcı kilidi altında uygular
+	Limit *EffectiveLimit
+	// Fee: ana işlemle aynı DB transaction'ında tahsil edilecek ücret (nil ya da sıfırsa ücret yok)
+	Fee *FeeQuote
+	// Meta: kullanıcı metadata'sı (normalize edilmiş olmalı)
+	Meta *TxMeta
+}
+
+// Apply: seçenekleri yeni transaction kaydına uygular
+func (o TxOptions) Apply(t *Transaction) {
+	if o.Origin != nil {
+		typ, id, seq := o.Origin.Type, o.Origin.ID, o.Origin.Seq
+		t.OriginType, t.OriginID, t.OriginSeq = &typ, &id, &seq
+	}
+	if m := o.Meta; m != nil {
+		t.Memo, t.Category, t.Tags, t.ExternalRef = m.Memo, m.Category, m.Tags, m.ExternalRef
+	}
+}
+
+// Reversible: telafi işlemiyle geri alınabilir mi
+func (t *Transaction) Reversible() bool {
+	switch t.Type {
+	case "credit", "debit", "transfer", TxTypeFee, TxTypeSplit:
+		return t.Status == TxStatusCompleted || t.Status == TxStatusPartiallyRefunded
+	}
+	return false
+}
+
+// RemainingRefundable: henüz iade edilmemiş tutar
+func (t *Transaction) RemainingRefundable() Money {
+	return t.Amount - t.RefundedAmount
 }
 
 // JSON helper’ları
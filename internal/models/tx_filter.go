@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// İşlem geçmişi sayfa boyutları
+const (
+	DefaultTxPageSize = 50
+	MaxTxPageSize     = 200
+)
+
+// Yön filtresi: kullanıcının ledger bacağının işaretine göre (para girişi / çıkışı)
+const (
+	TxDirectionIn  = "in"
+	TxDirectionOut = "out"
+)
+
+// TxFilter: işlem geçmişi filtreleri; boş alanlar uygulanmaz. Sonuçlar (created_at, id) sırasındadır.
+type TxFilter struct {
+	Category    string // tam eşleşme
+	Tag         string // etiketlerden biri
+	ExternalRef string
+	Query       string // memo içinde tam metin arama
+
+	From         *time.Time // created_at >= From
+	To           *time.Time // created_at < To
+	Types        []string
+	Status       string
+	MinAmount    *Money
+	MaxAmount    *Money
+	Counterparty int    // karşı taraf kullanıcı
+	Direction    string // in | out
+
+	Ascending bool // varsayılan en yeni önce
+	Limit     int
+	Cursor    *TxCursor
+}
+
+// Normalize: metin filtrelerini normalize eder, sayfa boyutunu sınırlar ve tutarlılığı doğrular
+func (f *TxFilter) Normalize() error {
+	f.Category = strings.ToLower(strings.TrimSpace(f.Category))
+	f.Tag = strings.ToLower(strings.TrimSpace(f.Tag))
+	f.ExternalRef = strings.TrimSpace(f.ExternalRef)
+	f.Query = strings.TrimSpace(f.Query)
+	f.Status = strings.ToLower(strings.TrimSpace(f.Status))
+	f.Direction = strings.ToLower(strings.TrimSpace(f.Direction))
+	types := f.Types[:0]
+	for _, t := range f.Types {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	f.Types = types
+	if f.Limit <= 0 {
+		f.Limit = DefaultTxPageSize
+	}
+	if f.Limit > MaxTxPageSize {
+		f.Limit = MaxTxPageSize
+	}
+	if f.Direction != "" && f.Direction != TxDirectionIn && f.Direction != TxDirectionOut {
+		return errors.New("direction must be in or out")
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return errors.New("from must be before to")
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return errors.New("min_amount must not exceed max_amount")
+	}
+	return nil
+}
+
+// TxCursor: keyset sayfalama konumu; sayfanın son kaydının (created_at, id) değeri
+type TxCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// Encode: istemciye verilen opak next_cursor değeri
+func (c TxCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTxCursor: Encode çıktısını çözer
+func ParseTxCursor(s string) (*TxCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errors.New("invalid cursor")
+	}
+	nanos, err1 := strconv.ParseInt(ts, 10, 64)
+	n, err2 := strconv.Atoi(id)
+	if err1 != nil || err2 != nil || n <= 0 {
+		return nil, errors.New("invalid cursor")
+	}
+	return &TxCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: n}, nil
+}
+
+// TxPage: işlem geçmişinin bir sayfası; NextCursor boşsa son sayfadır
+type TxPage struct {
+	Transactions []*Transaction `json:"transactions"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
+}
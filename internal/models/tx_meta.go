@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Metadata sınırları
+const (
+	MaxMemoLength        = 280
+	MaxCategoryLength    = 64
+	MaxTags              = 10
+	MaxTagLength         = 32
+	MaxExternalRefLength = 128
+)
+
+// Tags: işlem etiketleri; DB'de JSON dizi olarak saklanır (GIN indeksli)
+type Tags []string
+
+func (t Tags) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal([]string(t))
+	return string(b), err
+}
+
+func (t *Tags) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(t))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(t))
+	}
+	return fmt.Errorf("unsupported tags type %T", src)
+}
+
+// TxMeta: kullanıcının işleme eklediği açıklama bilgileri. ExternalRef istemcinin kendi referansıdır,
+// işlemi başlatan kullanıcı için benzersizdir ve sonradan değiştirilemez.
+type TxMeta struct {
+	Memo        string  `json:"memo,omitempty"`
+	Category    string  `json:"category,omitempty"`
+	Tags        Tags    `json:"tags,omitempty"`
+	ExternalRef *string `json:"external_ref,omitempty"`
+}
+
+// Normalize: boşlukları kırpar, kategori ve etiketleri küçük harfe çevirir, tekrar eden etiketleri atar ve sınırları doğrular
+func (m *TxMeta) Normalize() error {
+	m.Memo = strings.TrimSpace(m.Memo)
+	m.Category = strings.ToLower(strings.TrimSpace(m.Category))
+	tags, err := normalizeTags(m.Tags)
+	if err != nil {
+		return err
+	}
+	m.Tags = tags
+	if m.ExternalRef != nil {
+		ref := strings.TrimSpace(*m.ExternalRef)
+		if ref == "" {
+			m.ExternalRef = nil
+		} else {
+			m.ExternalRef = &ref
+		}
+	}
+	var problems []string
+	if utf8.RuneCountInString(m.Memo) > MaxMemoLength {
+		problems = append(problems, fmt.Sprintf("memo must be at most %d characters", MaxMemoLength))
+	}
+	if utf8.RuneCountInString(m.Category) > MaxCategoryLength {
+		problems = append(problems, fmt.Sprintf("category must be at most %d characters", MaxCategoryLength))
+	}
+	if m.ExternalRef != nil && utf8.RuneCountInString(*m.ExternalRef) > MaxExternalRefLength {
+		problems = append(problems, fmt.Sprintf("external_ref must be at most %d characters", MaxExternalRefLength))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func normalizeTags(in Tags) (Tags, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(in))
+	out := make(Tags, 0, len(in))
+	for _, tag := range in {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tags must be at most %d characters", MaxTagLength)
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	if len(out) > MaxTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", MaxTags)
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// TxMetaPatch: sahibin sonradan düzenleyebildiği alanlar; nil alan değişmez
+type TxMetaPatch struct {
+	Memo     *string `json:"memo"`
+	Category *string `json:"category"`
+	Tags     *Tags   `json:"tags"`
+}
+
+// Apply: yamayı işleme uygular ve sonucu doğrular
+func (p TxMetaPatch) Apply(t *Transaction) error {
+	m := TxMeta{Memo: t.Memo, Category: t.Category, Tags: t.Tags}
+	if p.Memo != nil {
+		m.Memo = *p.Memo
+	}
+	if p.Category != nil {
+		m.Category = *p.Category
+	}
+	if p.Tags != nil {
+		m.Tags = *p.Tags
+	}
+	if err := m.Normalize(); err != nil {
+		return err
+	}
+	t.Memo, t.Category, t.Tags = m.Memo, m.Category, m.Tags
+	return nil
+}
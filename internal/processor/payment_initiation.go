@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"log/slog"
+	"sync/atomic"
+
+	"insider-go-backend/internal/models"
+	"insider-go-backend/internal/services"
+)
+
+// RunPaymentInitiation: pain.001 talimatının bekleyen (PDNG) kalemlerini transfer işi olarak kuyruğa koyar ve beklemeden döner.
+// Her sonuç kaleme yazılır; son kalem işlendiğinde grup durumu hesaplanıp talimat kapatılır.
+func (p *TransactionProcessor) RunPaymentInitiation(pi *models.PaymentInitiation) {
+	var pending []models.PaymentInitiationTx
+	for _, t := range pi.Txs {
+		if t.Status == models.PainStatusPending && t.ToUserID != nil {
+			pending = append(pending, t)
+		}
+	}
+	id := pi.ID
+	finish := func() {
+		if _, err := services.FinishPaymentInitiation(id); err != nil {
+			slog.Error("txproc.pain001.finish_failed", "id", id, "err", err)
+		}
+	}
+	if len(pending) == 0 {
+		finish()
+		return
+	}
+	remaining := int64(len(pending))
+	slog.Info("txproc.pain001.start", "id", id, "txs", len(pending))
+	go func() {
+		for _, t := range pending {
+			t := t
+			job := TxJob{
+				Op: OpTransfer, UserID: t.FromUserID, ToUserID: *t.ToUserID, Amount: t.Amount, Currency: t.Currency,
+				Opts: t.Options(),
+				Done: func(tx *models.Transaction, err error) {
+					services.RecordPaymentInitiationTx(t, tx, err)
+					if atomic.AddInt64(&remaining, -1) == 0 {
+						finish()
+					}
+				},
+			}
+			if err := p.Enqueue(job); err != nil {
+				job.Done(nil, err)
+			}
+		}
+	}()
+}
+
+// ResumePaymentInitiations: yeniden başlatmadan önce tamamlanmamış talimatları kuyruğa geri verir.
+// Gerçekleşmiş kalemler origin çakışmasıyla tanınır; ikinci transfer oluşmaz.
+func (p *TransactionProcessor) ResumePaymentInitiations() (int, error) {
+	list, err := services.PendingPaymentInitiations()
+	if err != nil {
+		return 0, err
+	}
+	for _, pi := range list {
+		p.RunPaymentInitiation(pi)
+	}
+	return len(list), nil
+}
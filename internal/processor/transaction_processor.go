@@ -8,6 +8,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"insider-go-backend/internal/models"
 	"insider-go-backend/internal/services"
 )
 
@@ -24,8 +25,15 @@ const (
 type TxJob struct {
 	Op       TxOp
 	UserID   int
-	ToUserID int     // transfer için hedef kullanıcı
-	Amount   float64 // miktar (>0)
+	ToUserID int          // transfer için hedef kullanıcı
+	Amount   models.Money // miktar (>0)
+	Currency string       // ISO 4217; boşsa varsayılan para birimi
+	// ToCurrency: transfer için alıcı para birimi; Currency'den farklıysa kur tablosuyla çevrilir
+	ToCurrency string
+	// Opts: origin ve açıklama gibi işlem seçenekleri (ör. pain.001 kalemi)
+	Opts models.TxOptions
+	// Done: iş işlendikten sonra sonuçla çağrılır (kur çevrimli transferde tx nil'dir)
+	Done func(tx *models.Transaction, err error)
 }
 
 // TxStats: atomik sayaçlar
@@ -154,25 +162,41 @@ func (p *TransactionProcessor) Stats() (enq, proc, ok, fail int64) { return p.st
 // handle: tek bir işi işler ve sayaçları günceller
 func (p *TransactionProcessor) handle(job TxJob) {
 	atomic.AddInt64(&p.stats.processed, 1)
-	var err error
 	start := time.Now()
+	tx, err := execute(job)
+	if job.Done != nil {
+		job.Done(tx, err)
+	}
+	if err != nil {
+		slog.Error("txproc.job.failed", "op", string(job.Op), "user", job.UserID, "to", job.ToUserID, "amount", job.Amount, "currency", job.Currency, "err", err, "took", time.Since(start))
+		atomic.AddInt64(&p.stats.failed, 1)
+		return
+	}
+	slog.Info("txproc.job.ok", "op", string(job.Op), "user", job.UserID, "to", job.ToUserID, "amount", job.Amount, "currency", job.Currency, "took", time.Since(start))
+	atomic.AddInt64(&p.stats.succeeded, 1)
+}
+
+// execute: tek bir işi ilgili servis çağrısına yönlendirir
+func execute(job TxJob) (*models.Transaction, error) {
+	var (
+		tx  *models.Transaction
+		err error
+	)
 	switch job.Op {
 	case OpCredit:
-		_, err = services.Credit(job.UserID, job.Amount)
+		_, tx, err = services.CreditWith(job.UserID, job.Amount, job.Currency, job.Opts)
 	case OpDebit:
-		_, err = services.Debit(job.UserID, job.Amount)
+		_, tx, err = services.DebitWith(job.UserID, job.Amount, job.Currency, job.Opts)
 	case OpTransfer:
-		_, _, err = services.Transfer(job.UserID, job.ToUserID, job.Amount)
+		if services.IsCrossCurrency(job.Currency, job.ToCurrency) {
+			_, _, _, tx, err = services.TransferFXWith(job.UserID, job.ToUserID, job.Amount, job.Currency, job.ToCurrency, job.Opts)
+		} else {
+			_, _, tx, err = services.TransferWith(job.UserID, job.ToUserID, job.Amount, job.Currency, job.Opts)
+		}
 	default:
 		err = errors.New("unknown op")
 	}
-	if err != nil {
-		slog.Error("txproc.job.failed", "op", string(job.Op), "user", job.UserID, "to", job.ToUserID, "amount", job.Amount, "err", err, "took", time.Since(start))
-		atomic.AddInt64(&p.stats.failed, 1)
-		return
-	}
-	slog.Info("txproc.job.ok", "op", string(job.Op), "user", job.UserID, "to", job.ToUserID, "amount", job.Amount, "took", time.Since(start))
-	atomic.AddInt64(&p.stats.succeeded, 1)
+	return tx, err
 }
 
 // ProcessBatchConcurrently: geçici bir worker pool ile verilen işleri eşzamanlı işler ve tamamlanınca döner
@@ -197,18 +221,7 @@ func ProcessBatchConcurrently(ctx context.Context, jobs []TxJob, concurrency int
 					if !okc {
 						return
 					}
-					var err error
-					switch j.Op {
-					case OpCredit:
-						_, err = services.Credit(j.UserID, j.Amount)
-					case OpDebit:
-						_, err = services.Debit(j.UserID, j.Amount)
-					case OpTransfer:
-						_, _, err = services.Transfer(j.UserID, j.ToUserID, j.Amount)
-					default:
-						err = errors.New("unknown op")
-					}
-					if err != nil {
+					if _, err := execute(j); err != nil {
 						atomic.AddInt64(&fail, 1)
 					} else {
 						atomic.AddInt64(&ok, 1)
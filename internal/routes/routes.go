@@ -3,6 +3,7 @@ package routes
 import (
 	"insider-go-backend/internal/handlers"
 	"insider-go-backend/internal/middleware"
+	"insider-go-backend/internal/models"
 	"insider-go-backend/internal/processor"
 
 	"github.com/gin-gonic/gin"
@@ -31,17 +32,102 @@ func RegisterRoutes(r *gin.Engine) {
 			users.GET("/:id", handlers.GetUserHandler)
 			users.PUT("/:id", handlers.UpdateUserHandler)
 			users.DELETE("/:id", handlers.DeleteUserHandler)
+			users.PUT("/:id/wallets/:currency/credit-line", handlers.SetCreditLineHandler)
 		}
 
 		// Transaction endpoints (auth gerekli)
 		transactions := api.Group("/transactions")
 		transactions.Use(middleware.AuthMiddleware())
 		{
-			transactions.POST("/credit", handlers.CreditHandler)
-			transactions.POST("/debit", handlers.DebitHandler)
-			transactions.POST("/transfer", handlers.TransferHandler)
+			// para hareketi yapan uç noktalar Idempotency-Key destekler
+			transactions.POST("/credit", middleware.Idempotency(), handlers.CreditHandler)
+			transactions.POST("/debit", middleware.Idempotency(), handlers.DebitHandler)
+			transactions.POST("/transfer", middleware.Idempotency(), handlers.TransferHandler)
+			transactions.POST("/split", middleware.Idempotency(), handlers.SplitTransferHandler)
 			transactions.GET("/history", handlers.TransactionHistoryHandler)
+			transactions.GET("/export", handlers.ExportTransactionsHandler)
+			// toplu işlem: all_or_nothing (tek DB transaction'ı) ya da best_effort (kalem başına)
+			transactions.POST("/batch", middleware.Idempotency(), handlers.SubmitBatchHandler)
+			transactions.GET("/batches", handlers.ListBatchesHandler)
+			transactions.GET("/batches/:id", handlers.GetBatchHandler)
+			// authorization hold'ları: authorize -> capture | void (süresi dolanlar sweeper ile serbest kalır)
+			transactions.POST("/authorize", middleware.Idempotency(), handlers.AuthorizeHandler)
+			transactions.GET("/holds", handlers.ListHoldsHandler)
+			transactions.POST("/holds/:id/capture", middleware.Idempotency(), handlers.CaptureHoldHandler)
+			transactions.POST("/holds/:id/void", handlers.VoidHoldHandler)
+			transactions.GET("/fx-quote", handlers.FXQuoteHandler)
+			transactions.GET("/fee-quote", handlers.FeeQuoteHandler)
 			transactions.GET("/:id", handlers.GetTransactionHandler)
+			transactions.PATCH("/:id", handlers.UpdateTransactionMetaHandler)
+			// telafi işlemleri (admin rolü gerekli)
+			transactions.POST("/:id/reverse", middleware.RequireRole("admin"), middleware.Idempotency(), handlers.ReverseTransactionHandler)
+			transactions.POST("/:id/refund", middleware.RequireRole("admin"), middleware.Idempotency(), handlers.RefundTransactionHandler)
+		}
+
+		// Zamanlanmış transferler (auth gerekli); vadesi gelenleri arka plandaki scheduler çalıştırır
+		scheduled := api.Group("/transfers/scheduled")
+		scheduled.Use(middleware.AuthMiddleware())
+		{
+			scheduled.POST("", middleware.Idempotency(), handlers.CreateScheduledTransferHandler)
+			scheduled.GET("", handlers.ListScheduledTransfersHandler)
+			scheduled.GET("/:id", handlers.GetScheduledTransferHandler)
+			scheduled.PUT("/:id", handlers.UpdateScheduledTransferHandler)
+			scheduled.DELETE("/:id", handlers.CancelScheduledTransferHandler)
+		}
+
+		// Düzenli transfer talimatları (auth gerekli)
+		standing := api.Group("/standing-orders")
+		standing.Use(middleware.AuthMiddleware())
+		{
+			standing.POST("", middleware.Idempotency(), handlers.CreateStandingOrderHandler)
+			standing.GET("", handlers.ListStandingOrdersHandler)
+			standing.GET("/:id", handlers.GetStandingOrderHandler)
+			standing.GET("/:id/runs", handlers.StandingOrderRunsHandler)
+			standing.POST("/:id/pause", handlers.PauseStandingOrderHandler)
+			standing.POST("/:id/resume", handlers.ResumeStandingOrderHandler)
+			standing.DELETE("/:id", handlers.CancelStandingOrderHandler)
+		}
+
+		// Ödeme istekleri: isteyen oluşturur/iptal eder, ödeyen kabul eder/reddeder
+		paymentRequests := api.Group("/payment-requests")
+		paymentRequests.Use(middleware.AuthMiddleware())
+		{
+			paymentRequests.POST("", middleware.Idempotency(), handlers.CreatePaymentRequestHandler)
+			paymentRequests.GET("", handlers.ListPaymentRequestsHandler)
+			paymentRequests.GET("/:id", handlers.GetPaymentRequestHandler)
+			paymentRequests.POST("/:id/accept", middleware.Idempotency(), handlers.AcceptPaymentRequestHandler)
+			paymentRequests.POST("/:id/decline", handlers.DeclinePaymentRequestHandler)
+			paymentRequests.POST("/:id/cancel", handlers.CancelPaymentRequestHandler)
+		}
+
+		// Escrow: alıcı fonlar; iki tarafın aynı onayı, admin kararı ya da süre dolumu ile kapanır
+		escrows := api.Group("/escrows")
+		escrows.Use(middleware.AuthMiddleware())
+		{
+			escrows.POST("", middleware.Idempotency(), handlers.OpenEscrowHandler)
+			escrows.GET("", handlers.ListEscrowsHandler)
+			escrows.GET("/:id", handlers.GetEscrowHandler)
+			escrows.POST("/:id/confirm", middleware.Idempotency(), handlers.ConfirmEscrowHandler)
+			escrows.POST("/:id/arbitrate", middleware.RequireRole("admin"), middleware.Idempotency(), handlers.ArbitrateEscrowHandler)
+		}
+
+		// Ekstreler: ay sonu job'u üretir, kapanmış aylar istekle de üretilebilir; JSON, CSV ve HTML indirilebilir
+		statements := api.Group("/statements")
+		statements.Use(middleware.AuthMiddleware())
+		{
+			statements.GET("", handlers.ListStatementsHandler)
+			statements.POST("", handlers.GenerateStatementHandler)
+			statements.GET("/:id", handlers.GetStatementHandler)
+		}
+
+		// Toplu ödeme (pain.001): dosya doğrulanır, kalemler işlemcide arka planda çalışır, sonuç pain.002 raporudur
+		paymentInitiations := api.Group("/payment-initiations")
+		paymentInitiations.Use(middleware.AuthMiddleware())
+		{
+			paymentInitiations.POST("", middleware.Idempotency(), handlers.SubmitPaymentInitiationHandler)
+			paymentInitiations.GET("", handlers.ListPaymentInitiationsHandler)
+			paymentInitiations.GET("/:id", handlers.GetPaymentInitiationHandler)
+			paymentInitiations.GET("/:id/report", handlers.GetPaymentInitiationReportHandler)
 		}
 
 		// Balance endpoints (auth gerekli)
@@ -49,10 +135,76 @@ func RegisterRoutes(r *gin.Engine) {
 		balances.Use(middleware.AuthMiddleware())
 		{
 			balances.GET("/current", handlers.CurrentBalanceHandler)
+			balances.POST("/wallets", handlers.OpenWalletHandler)
 			balances.GET("/historical", handlers.HistoricalBalanceHandler)
 			balances.GET("/at-time", handlers.BalanceAtTimeHandler)
 		}
 
+		// Ledger: çift taraflı kayıtlar ve denetim raporu (admin rolü gerekli)
+		ledger := api.Group("/ledger")
+		ledger.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			ledger.GET("/trial-balance", handlers.TrialBalanceHandler)
+			ledger.GET("/transactions/:id/entries", handlers.TransactionLedgerEntriesHandler)
+			ledger.GET("/accounts/:account/entries", handlers.AccountLedgerEntriesHandler)
+			ledger.GET("/reconcile", handlers.ReconcileHandler)
+			ledger.POST("/reconcile", handlers.ReconcileHandler)
+		}
+
+		// FX: kur tablosu yönetimi (admin rolü gerekli)
+		fx := api.Group("/fx")
+		fx.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			fx.POST("/rates", handlers.CreateFXRateHandler)
+			fx.POST("/rates/bulk", handlers.BulkUploadFXRatesHandler)
+			fx.GET("/rates", handlers.ListFXRatesHandler)
+		}
+
+		// Limitler: rol varsayılanları ve kullanıcı override'ları (admin rolü gerekli)
+		limits := api.Group("/limits")
+		limits.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			limits.GET("", handlers.ListLimitPoliciesHandler)
+			limits.PUT("", handlers.UpsertLimitPolicyHandler)
+			limits.GET("/effective", handlers.EffectiveLimitsHandler)
+			limits.DELETE("/:id", handlers.DeleteLimitPolicyHandler)
+		}
+
+		// Mevduat faizi: tarifeler ve tahakkuk önizlemesi (admin rolü gerekli)
+		interest := api.Group("/interest")
+		interest.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			interest.GET("/rates", handlers.ListInterestRatesHandler)
+			interest.POST("/rates", handlers.CreateInterestRateHandler)
+			interest.GET("/rates/:id", handlers.GetInterestRateHandler)
+			interest.PUT("/rates/:id", handlers.UpdateInterestRateHandler)
+			interest.DELETE("/rates/:id", handlers.DeleteInterestRateHandler)
+			interest.GET("/preview", handlers.PreviewInterestHandler)
+		}
+
+		// Banka ekstresi mutabakatı: camt.053/CSV içe aktarma, otomatik eşleştirme ve istisna raporu (admin rolü gerekli)
+		bankStatements := api.Group("/bank-statements")
+		bankStatements.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			bankStatements.POST("", handlers.ImportBankStatementHandler)
+			bankStatements.GET("", handlers.ListBankStatementsHandler)
+			bankStatements.GET("/:id", handlers.GetBankStatementHandler)
+			bankStatements.GET("/:id/report", handlers.GetBankStatementReportHandler)
+			bankStatements.POST("/:id/rematch", handlers.RematchBankStatementHandler)
+			bankStatements.POST("/lines/:line_id/credit", middleware.Idempotency(), handlers.CreditBankStatementLineHandler)
+		}
+
+		// Ücret tarifeleri: işlem tipi/rol/tutar bandına göre, geçerlilik tarihli (admin rolü gerekli)
+		fees := api.Group("/fees")
+		fees.Use(middleware.AuthMiddleware(), middleware.RequireRole("admin"))
+		{
+			fees.GET("", handlers.ListFeeSchedulesHandler)
+			fees.POST("", handlers.CreateFeeScheduleHandler)
+			fees.GET("/:id", handlers.GetFeeScheduleHandler)
+			fees.PUT("/:id", handlers.UpdateFeeScheduleHandler)
+			fees.DELETE("/:id", handlers.DeleteFeeScheduleHandler)
+		}
+
 		// Ops: işlemci kuyruğu ve istatistik (admin rolü gerekli olabilir)
 		ops := api.Group("/ops")
 		ops.Use(middleware.AuthMiddleware())
@@ -62,12 +214,14 @@ func RegisterRoutes(r *gin.Engine) {
 				c.JSON(200, stats)
 			})
 
-			ops.POST("/enqueue", func(c *gin.Context) {
+			ops.POST("/enqueue", middleware.Idempotency(), func(c *gin.Context) {
 				var r struct {
-					Op       string  `json:"op"`
-					UserID   int     `json:"user_id"`
-					ToUserID int     `json:"to_user_id"`
-					Amount   float64 `json:"amount"`
+					Op         string       `json:"op"`
+					UserID     int          `json:"user_id"`
+					ToUserID   int          `json:"to_user_id"`
+					Amount     models.Money `json:"amount"`
+					Currency   string       `json:"currency"`
+					ToCurrency string       `json:"to_currency"`
 				}
 				if err := c.ShouldBindJSON(&r); err != nil {
 					c.JSON(400, gin.H{"error": err.Error()})
@@ -78,7 +232,7 @@ func RegisterRoutes(r *gin.Engine) {
 					c.JSON(503, gin.H{"error": "processor not running"})
 					return
 				}
-				job := processor.TxJob{Op: processor.TxOp(r.Op), UserID: r.UserID, ToUserID: r.ToUserID, Amount: r.Amount}
+				job := processor.TxJob{Op: processor.TxOp(r.Op), UserID: r.UserID, ToUserID: r.ToUserID, Amount: r.Amount, Currency: r.Currency, ToCurrency: r.ToCurrency}
 				if ok := p.TryEnqueue(job); !ok {
 					c.JSON(429, gin.H{"error": "queue full"})
 					return
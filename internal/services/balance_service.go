@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"insider-go-backend/internal/database"
 	"insider-go-backend/internal/models"
 	"log/slog"
@@ -8,101 +9,139 @@ import (
 )
 
 // Bakiye ekleme/güncelleme
-func AddOrUpdateBalance(userID int, amount float64) error {
-	slog.Info("service.balance.add_or_update.start", "user_id", userID, "delta", amount)
-	balance, err := database.BalanceRepo().GetBalanceByUserID(userID)
+func AddOrUpdateBalance(userID int, currency string, amount models.Money) error {
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return err
+	}
+	slog.Info("service.balance.add_or_update.start", "user_id", userID, "currency", currency, "delta", amount)
+	balance, err := database.BalanceRepo().GetBalance(userID, currency)
 	if err != nil || balance == nil {
 		// Bakiye yoksa oluştur
 		newBalance := &models.Balance{
 			UserID:      userID,
+			Currency:    currency,
 			Amount:      amount,
 			LastUpdated: time.Now(),
 		}
 		if err := database.BalanceRepo().CreateBalance(newBalance); err != nil {
-			slog.Error("service.balance.create_failed", "user_id", userID, "err", err)
+			slog.Error("service.balance.create_failed", "user_id", userID, "currency", currency, "err", err)
 			return err
 		}
-		slog.Info("service.balance.created", "user_id", userID, "amount", amount)
+		slog.Info("service.balance.created", "user_id", userID, "currency", currency, "amount", amount)
 		return nil
 	}
 
 	// Var olan bakiyeyi güncelle
-	balance.Amount += amount
-	if err := database.BalanceRepo().UpdateBalance(userID, balance.Amount); err != nil {
-		slog.Error("service.balance.update_failed", "user_id", userID, "err", err)
+	if err := database.BalanceRepo().AdjustBalance(userID, currency, amount); err != nil {
+		slog.Error("service.balance.update_failed", "user_id", userID, "currency", currency, "err", err)
 		return err
 	}
-	slog.Info("service.balance.updated", "user_id", userID, "amount", balance.Amount)
+	slog.Info("service.balance.updated", "user_id", userID, "currency", currency, "delta", amount)
 	return nil
 }
 
 // Kullanıcı bakiyesi çekme
-func GetUserBalance(userID int) (float64, error) {
-	slog.Debug("service.balance.get_user_balance", "user_id", userID)
-	balance, err := database.BalanceRepo().GetBalanceByUserID(userID)
+func GetUserBalance(userID int, currency string) (models.Money, error) {
+	slog.Debug("service.balance.get_user_balance", "user_id", userID, "currency", currency)
+	balance, err := GetBalance(userID, currency)
 	if err != nil || balance == nil {
 		return 0, err
 	}
 	return balance.Amount, nil
 }
 
-// GetBalance: kullanıcı bakiyesini getirir
-func GetBalance(userID int) (*models.Balance, error) {
-	slog.Debug("service.balance.get", "user_id", userID)
-	return database.BalanceRepo().GetBalanceByUserID(userID)
+// GetBalance: kullanıcının verilen para birimindeki cüzdanını getirir (boşsa varsayılan para birimi)
+func GetBalance(userID int, currency string) (*models.Balance, error) {
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("service.balance.get", "user_id", userID, "currency", cur.Code)
+	return database.BalanceRepo().GetBalance(userID, cur.Code)
+}
+
+// GetBalances: kullanıcının sahip olduğu tüm para birimi cüzdanları
+func GetBalances(userID int) ([]models.Balance, error) {
+	slog.Debug("service.balance.list", "user_id", userID)
+	return database.BalanceRepo().GetBalancesByUserID(userID)
+}
+
+// OpenWallet: kullanıcı için yeni bir para birimi cüzdanı açar (sıfır bakiyeyle)
+func OpenWallet(userID int, currency string) (*models.Balance, error) {
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("service.balance.open_wallet", "user_id", userID, "currency", cur.Code)
+	if existing, _ := database.BalanceRepo().GetBalance(userID, cur.Code); existing != nil {
+		return nil, errors.New("wallet already exists")
+	}
+	if err := CreateBalanceForUser(userID, cur.Code, 0); err != nil {
+		return nil, err
+	}
+	return database.BalanceRepo().GetBalance(userID, cur.Code)
 }
 
 // SetBalance: kullanıcı bakiyesini belirli bir değere ayarlar (varsa günceller, yoksa oluşturur)
-func SetBalance(userID int, amount float64) (*models.Balance, error) {
-	slog.Info("service.balance.set", "user_id", userID, "amount", amount)
-	b, err := database.BalanceRepo().GetBalanceByUserID(userID)
+func SetBalance(userID int, currency string, amount models.Money) (*models.Balance, error) {
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("service.balance.set", "user_id", userID, "currency", currency, "amount", amount)
+	b, err := database.BalanceRepo().GetBalance(userID, currency)
 	if err != nil || b == nil {
 		newBalance := &models.Balance{
 			UserID:      userID,
+			Currency:    currency,
 			Amount:      amount,
 			LastUpdated: time.Now(),
 		}
 		if err := database.BalanceRepo().CreateBalance(newBalance); err != nil {
-			slog.Error("service.balance.create_failed", "user_id", userID, "err", err)
+			slog.Error("service.balance.create_failed", "user_id", userID, "currency", currency, "err", err)
 			return nil, err
 		}
 		return newBalance, nil
 	}
-	if err := database.BalanceRepo().UpdateBalance(userID, amount); err != nil {
-		slog.Error("service.balance.update_failed", "user_id", userID, "err", err)
+	if err := database.BalanceRepo().UpdateBalance(userID, currency, amount); err != nil {
+		slog.Error("service.balance.update_failed", "user_id", userID, "currency", currency, "err", err)
 		return nil, err
 	}
 	// Güncellenmiş bakiyeyi tekrar çek
-	return database.BalanceRepo().GetBalanceByUserID(userID)
+	return database.BalanceRepo().GetBalance(userID, currency)
 }
 
-// CalculateBalanceAt: belirli bir zamandaki bakiyeyi hesaplar
-func CalculateBalanceAt(userID int, at time.Time) (float64, error) {
-	slog.Info("service.balance.calculate_at.start", "user_id", userID, "at", at)
-	txs, err := database.TransactionRepo().GetTransactionsByUser(userID)
+// CalculateBalanceAt: belirli bir zamandaki bakiyeyi hesaplar (tek para birimi cüzdanı için).
+// En yakın checkpoint'ten başlar ve yalnızca sonrasındaki ledger kayıtlarını SQL'de toplar;
+// açılış bakiyeleri ve düzeltmeler de ledger'da olduğundan sonuç balances projeksiyonuyla birebir tutarlıdır.
+func CalculateBalanceAt(userID int, currency string, at time.Time) (models.Money, error) {
+	cur, err := resolveCurrency(currency)
 	if err != nil {
-		slog.Error("service.balance.calculate_at.fetch_failed", "user_id", userID, "err", err)
 		return 0, err
 	}
-	bal := 0.0
-	for _, tx := range txs {
-		if tx.CreatedAt.After(at) {
-			continue
-		}
-		switch tx.Type {
-		case "credit":
-			bal += tx.Amount
-		case "debit":
-			bal -= tx.Amount
-		case "transfer":
-			if tx.FromUser == userID {
-				bal -= tx.Amount
-			}
-			if tx.ToUser == userID {
-				bal += tx.Amount
-			}
-		}
+	slog.Info("service.balance.calculate_at.start", "user_id", userID, "currency", cur.Code, "at", at)
+	bal, err := database.CheckpointRepo().BalanceAt(userID, cur.Code, at)
+	if err != nil {
+		slog.Error("service.balance.calculate_at.fetch_failed", "user_id", userID, "err", err)
+		return 0, err
 	}
-	slog.Info("service.balance.calculate_at.success", "user_id", userID, "balance", bal)
+	slog.Info("service.balance.calculate_at.success", "user_id", userID, "currency", cur.Code, "balance", bal)
 	return bal, nil
 }
+
+// WriteBalanceCheckpoints: son kapanmış günün (UTC) kapanış bakiyelerini yazar.
+// Gün sonu, geç commit edilen işlemlerin checkpoint'e girebilmesi için lag kadar beklenir.
+func WriteBalanceCheckpoints(now time.Time, lag time.Duration) (int64, error) {
+	settled := now.UTC().Add(-lag)
+	asOf := time.Date(settled.Year(), settled.Month(), settled.Day(), 0, 0, 0, 0, time.UTC)
+	n, err := database.CheckpointRepo().WriteCheckpoints(asOf)
+	if err != nil {
+		slog.Error("service.balance.checkpoint_failed", "as_of", asOf, "err", err)
+		return 0, err
+	}
+	if n > 0 {
+		slog.Info("service.balance.checkpoint_written", "as_of", asOf, "count", n)
+	}
+	return n, nil
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/models"
+	"io"
+	"strings"
+	"time"
+)
+
+// parseCamt053: camt.053 belgesindeki tüm ekstrelerin kalemlerini okur; hesap kimliği ilk ekstreninkidir.
+// Kayda geçmemiş (Sts != BOOK) kalemler istisna olarak işaretlenir.
+func parseCamt053(data []byte) (string, []models.BankStatementLine, error) {
+	var doc models.CamtDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return "", nil, fmt.Errorf("invalid camt.053: %v", err)
+	}
+	if len(doc.Statements) == 0 {
+		return "", nil, errors.New("camt.053 contains no statements")
+	}
+	account := doc.Statements[0].Acct.Id.String()
+	var lines []models.BankStatementLine
+	for _, st := range doc.Statements {
+		for _, e := range st.Ntry {
+			no := len(lines) + 1
+			l := models.BankStatementLine{LineNo: no, Currency: strings.ToUpper(strings.TrimSpace(e.Amt.Ccy))}
+			if l.Currency == "" {
+				l.Currency = strings.ToUpper(strings.TrimSpace(st.Acct.Ccy))
+			}
+			amt, err := models.ParseMoney(strings.TrimSpace(e.Amt.Value))
+			if err != nil || amt.IsNegative() {
+				return "", nil, fmt.Errorf("entry %d: invalid amount %q", no, e.Amt.Value)
+			}
+			switch e.CdtDbtInd {
+			case models.CamtCredit:
+				l.Amount = amt
+			case models.CamtDebit:
+				l.Amount = amt.Neg()
+			default:
+				return "", nil, fmt.Errorf("entry %d: invalid CdtDbtInd %q", no, e.CdtDbtInd)
+			}
+			day, err := camtDay(e.BookgDt)
+			if err != nil && e.ValDt != nil {
+				day, err = camtDay(*e.ValDt)
+			}
+			if err != nil {
+				return "", nil, fmt.Errorf("entry %d: %v", no, err)
+			}
+			l.BookingDate = day
+			l.Reference = e.Reference()
+			l.Description, l.CounterpartyName, l.CounterpartyAccount = e.Details()
+			if e.Sts != "" && e.Sts != models.CamtBooked {
+				l.Status, l.Reason = models.BankLineException, "entry not booked ("+e.Sts+")"
+			}
+			lines = append(lines, l)
+		}
+	}
+	return account, lines, nil
+}
+
+// camtDay: Dt ya da DtTm değerinin UTC günü
+func camtDay(d models.CamtDate) (time.Time, error) {
+	if d.Dt != "" {
+		return time.Parse("2006-01-02", d.Dt)
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, d.DtTm); err == nil {
+			t = t.UTC()
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, errors.New("booking date missing or invalid")
+}
+
+// parseBankCSV: başlık satırlı banka CSV'sini sütun eşlemesine göre okur
+func parseBankCSV(data []byte, m models.BankCSVMapping) ([]models.BankStatementLine, error) {
+	if err := m.Normalize(); err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	cr.Comma = []rune(m.Delimiter)[0]
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.New("csv header required")
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, name := range []string{m.Date, m.Amount, m.Credit, m.Debit, m.Currency, m.Reference, m.Description, m.CounterpartyName, m.CounterpartyAccount} {
+		if name == "" {
+			continue
+		}
+		if _, ok := col[strings.ToLower(name)]; !ok {
+			return nil, fmt.Errorf("csv column %q not found", name)
+		}
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := col[strings.ToLower(name)]; ok && name != "" && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var lines []models.BankStatementLine
+	for row := 2; ; row++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", row, err)
+		}
+		if strings.TrimSpace(strings.Join(rec, "")) == "" {
+			continue
+		}
+		l := models.BankStatementLine{
+			LineNo:              len(lines) + 1,
+			Reference:           field(rec, m.Reference),
+			Description:         field(rec, m.Description),
+			CounterpartyName:    field(rec, m.CounterpartyName),
+			CounterpartyAccount: field(rec, m.CounterpartyAccount),
+		}
+		if l.BookingDate, err = time.Parse(m.DateFormat, field(rec, m.Date)); err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", row, field(rec, m.Date))
+		}
+		if m.Amount != "" {
+			l.Amount, err = m.ParseAmount(field(rec, m.Amount))
+		} else {
+			var credit, debit models.Money
+			if credit, err = m.ParseAmount(field(rec, m.Credit)); err == nil {
+				debit, err = m.ParseAmount(field(rec, m.Debit))
+			}
+			if debit.IsNegative() {
+				debit = debit.Neg()
+			}
+			l.Amount = credit - debit
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount", row)
+		}
+		if l.Currency = strings.ToUpper(field(rec, m.Currency)); l.Currency == "" {
+			l.Currency = strings.ToUpper(strings.TrimSpace(m.DefaultCurrency))
+		}
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"insider-go-backend/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+const camtHead = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt>`
+
+func camtStmt(iban, ccy, entries string) string {
+	return `<Stmt><Id>S1</Id><Acct><Id><IBAN>` + iban + `</IBAN></Id><Ccy>` + ccy + `</Ccy></Acct>` + entries + `</Stmt>`
+}
+
+func camtDoc(stmts ...string) []byte {
+	return []byte(camtHead + strings.Join(stmts, "") + `</BkToCstmrStmt></Document>`)
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestParseCamt053(t *testing.T) {
+	credit := `<Ntry><NtryRef>R1</NtryRef><Amt Ccy="usd">125.50</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>` +
+		`<BookgDt><Dt>2026-03-02</Dt></BookgDt><NtryDtls><TxDtls><Refs><EndToEndId>E2E-1</EndToEndId></Refs>` +
+		`<RltdPties><Dbtr><Nm>Ada</Nm></Dbtr><DbtrAcct><Id><IBAN>DE02</IBAN></Id></DbtrAcct></RltdPties>` +
+		`<RmtInf><Ustrd>42-USD</Ustrd></RmtInf></TxDtls></NtryDtls></Ntry>`
+	debit := `<Ntry><NtryRef>R2</NtryRef><Amt>10</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>` +
+		`<BookgDt><DtTm>2026-03-02T23:30:00-02:00</DtTm></BookgDt></Ntry>`
+	pending := `<Ntry><Amt Ccy="EUR">5</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>PDNG</Sts>` +
+		`<BookgDt></BookgDt><ValDt><Dt>2026-03-04</Dt></ValDt><AcctSvcrRef>SVC-3</AcctSvcrRef></Ntry>`
+
+	account, lines, err := parseCamt053(camtDoc(camtStmt("DE89", "USD", credit+debit), camtStmt("DE90", "EUR", pending)))
+	if err != nil {
+		t.Fatalf("parseCamt053 error = %v", err)
+	}
+	if account != "DE89" {
+		t.Errorf("account = %q, want DE89", account)
+	}
+	want := []models.BankStatementLine{
+		{LineNo: 1, BookingDate: day("2026-03-02"), Amount: models.MustParseMoney("125.50"), Currency: "USD",
+			Reference: "E2E-1", Description: "42-USD", CounterpartyName: "Ada", CounterpartyAccount: "DE02"},
+		{LineNo: 2, BookingDate: day("2026-03-03"), Amount: models.MustParseMoney("-10"), Currency: "USD", Reference: "R2"},
+		{LineNo: 3, BookingDate: day("2026-03-04"), Amount: models.MustParseMoney("5"), Currency: "EUR", Reference: "SVC-3",
+			Status: models.BankLineException, Reason: "entry not booked (PDNG)"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(want))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i+1, lines[i], want[i])
+		}
+	}
+}
+
+func TestParseCamt053Errors(t *testing.T) {
+	entry := func(amt, ind, booking string) string {
+		return `<Ntry><Amt Ccy="USD">` + amt + `</Amt><CdtDbtInd>` + ind + `</CdtDbtInd><Sts>BOOK</Sts><BookgDt>` + booking + `</BookgDt></Ntry>`
+	}
+	date := `<Dt>2026-03-02</Dt>`
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"malformed xml", []byte(camtHead + `<Stmt>`), "invalid camt.053"},
+		{"not xml", []byte("date,amount\n"), "invalid camt.053"},
+		{"no statements", camtDoc(), "camt.053 contains no statements"},
+		{"bad amount", camtDoc(camtStmt("DE89", "USD", entry("1,5", "CRDT", date))), `entry 1: invalid amount "1,5"`},
+		{"negative amount", camtDoc(camtStmt("DE89", "USD", entry("-1", "CRDT", date))), `entry 1: invalid amount "-1"`},
+		{"bad indicator", camtDoc(camtStmt("DE89", "USD", entry("1", "CRDT", date)+entry("1", "XXXX", date))), `entry 2: invalid CdtDbtInd "XXXX"`},
+		{"missing booking date", camtDoc(camtStmt("DE89", "USD", entry("1", "DBIT", ""))), "entry 1: booking date missing or invalid"},
+		{"bad booking date", camtDoc(camtStmt("DE89", "USD", entry("1", "DBIT", `<Dt>02.03.2026</Dt>`))), "entry 1: parsing time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseCamt053(tt.data)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("parseCamt053 error = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBankCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping models.BankCSVMapping
+		want    []models.BankStatementLine
+	}{
+		{
+			name:    "default columns with BOM",
+			data:    "\xef\xbb\xbfDate,Amount,Currency\n2026-03-02,\"1,250.50\",usd\n\n2026-03-03,-3,\n",
+			mapping: models.BankCSVMapping{Currency: "currency", DefaultCurrency: "eur"},
+			want: []models.BankStatementLine{
+				{LineNo: 1, BookingDate: day("2026-03-02"), Amount: models.MustParseMoney("1250.50"), Currency: "USD"},
+				{LineNo: 2, BookingDate: day("2026-03-03"), Amount: models.MustParseMoney("-3"), Currency: "EUR"},
+			},
+		},
+		{
+			name: "credit/debit columns, decimal comma and custom date",
+			data: "Buchung;Haben;Soll;Verwendung;Name;Konto;Ref\n02.03.2026;1.000,25;;42-USD;Ada;DE02;R1\n03.03.2026;;-7,5;fee;;;R2\n",
+			mapping: models.BankCSVMapping{Date: "Buchung", Credit: "Haben", Debit: "Soll", Description: "Verwendung",
+				CounterpartyName: "Name", CounterpartyAccount: "Konto", Reference: "Ref", DefaultCurrency: "USD",
+				DateFormat: "02.01.2006", Delimiter: ";", DecimalComma: true},
+			want: []models.BankStatementLine{
+				{LineNo: 1, BookingDate: day("2026-03-02"), Amount: models.MustParseMoney("1000.25"), Currency: "USD",
+					Reference: "R1", Description: "42-USD", CounterpartyName: "Ada", CounterpartyAccount: "DE02"},
+				{LineNo: 2, BookingDate: day("2026-03-03"), Amount: models.MustParseMoney("-7.5"), Currency: "USD",
+					Reference: "R2", Description: "fee"},
+			},
+		},
+		{
+			name:    "header only",
+			data:    "date,amount\n",
+			mapping: models.BankCSVMapping{DefaultCurrency: "USD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := parseBankCSV([]byte(tt.data), tt.mapping)
+			if err != nil {
+				t.Fatalf("parseBankCSV error = %v", err)
+			}
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d", len(lines), len(tt.want))
+			}
+			for i := range tt.want {
+				if lines[i] != tt.want[i] {
+					t.Errorf("line %d = %+v, want %+v", i+1, lines[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseBankCSVErrors(t *testing.T) {
+	usd := models.BankCSVMapping{DefaultCurrency: "USD"}
+	tests := []struct {
+		name    string
+		data    string
+		mapping models.BankCSVMapping
+		want    string
+	}{
+		{"empty file", "", usd, "csv header required"},
+		{"missing column", "date,value\n2026-03-02,1\n", usd, `csv column "amount" not found`},
+		{"missing mapped column", "date,amount\n", models.BankCSVMapping{Currency: "ccy"}, `csv column "ccy" not found`},
+		{"no currency", "date,amount\n", models.BankCSVMapping{}, "currency column or default_currency required"},
+		{"bad delimiter", "date,amount\n", models.BankCSVMapping{DefaultCurrency: "USD", Delimiter: ";;"}, "delimiter must be a single character"},
+		{"amount and credit", "date,amount\n", models.BankCSVMapping{DefaultCurrency: "USD", Amount: "amount", Credit: "amount"}, "use either amount or credit/debit columns"},
+		{"bad date", "date,amount\n2026-03-02,1\n02.03.2026,1\n", usd, `line 3: invalid date "02.03.2026"`},
+		{"bad amount", "date,amount\n2026-03-02,abc\n", usd, "line 2: invalid amount"},
+		{"bad debit", "date,credit,debit\n2026-03-02,1,x\n", models.BankCSVMapping{DefaultCurrency: "USD", Credit: "credit", Debit: "debit"}, "line 2: invalid amount"},
+		{"malformed quoting", "date,amount\n2026-03-02,\"1\n", usd, "line 2: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseBankCSV([]byte(tt.data), tt.mapping)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("parseBankCSV error = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
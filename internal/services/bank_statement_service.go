@@ -0,0 +1,304 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// ImportBankStatement: camt.053 ya da eşlemeli CSV banka ekstresini okur, kalemleri credit işlemleriyle eşleştirir ve saklar.
+// Aynı dosya ikinci kez içe aktarılamaz; sonuç eşleşmeyen ve istisna kalemlerin raporudur.
+func ImportBankStatement(actorID int, source, filename string, data []byte, mapping *models.BankCSVMapping) (*models.BankStatementReport, error) {
+	var (
+		account string
+		lines   []models.BankStatementLine
+		err     error
+	)
+	switch source {
+	case models.BankSourceCamt053:
+		account, lines, err = parseCamt053(data)
+	case models.BankSourceCSV:
+		if mapping == nil {
+			mapping = &models.BankCSVMapping{}
+		}
+		lines, err = parseBankCSV(data, *mapping)
+	default:
+		return nil, errors.New("format must be camt053 or csv")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("bank statement contains no entries")
+	}
+	sum := sha256.Sum256(data)
+	stmt := &models.BankStatement{Source: source, Filename: filename, FileHash: hex.EncodeToString(sum[:]), AccountID: account, LineCount: len(lines), UploadedBy: actorID}
+	slog.Info("service.bank_statement.import.start", "source", source, "lines", len(lines), "actor_id", actorID)
+
+	used := make(map[int]bool)
+	for i := range lines {
+		if lines[i].Status == models.BankLineException {
+			continue // okuma sırasında istisna işaretlenmiş (ör. kayda geçmemiş kalem)
+		}
+		if err := matchBankLine(&lines[i], used, true); err != nil {
+			slog.Error("service.bank_statement.match_failed", "line_no", lines[i].LineNo, "err", err)
+			return nil, err
+		}
+	}
+	if err := database.BankStatementRepo().Create(stmt, lines); err != nil {
+		if errors.Is(err, database.ErrDuplicateBankStatement) {
+			slog.Warn("service.bank_statement.duplicate", "file_hash", stmt.FileHash)
+		} else {
+			slog.Error("service.bank_statement.import_failed", "err", err)
+		}
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, l := range lines {
+		counts[l.Status]++
+	}
+	_ = LogAction("bank_statement", stmt.ID, "import", fmt.Sprintf("%s statement %q with %d lines by user %d: %d matched, %d unmatched, %d exceptions",
+		source, filename, len(lines), actorID, counts[models.BankLineMatched], counts[models.BankLineUnmatched], counts[models.BankLineException]))
+	slog.Info("service.bank_statement.imported", "id", stmt.ID, "matched", counts[models.BankLineMatched], "unmatched", counts[models.BankLineUnmatched], "exceptions", counts[models.BankLineException])
+	return GetBankStatementReport(stmt.ID)
+}
+
+// matchBankLine: kalemin durumunu belirler. Yatırım kalemleri (pozitif tutar) tarih penceresindeki aynı tutarlı credit
+// işlemleriyle karşılaştırılır: referans external_ref ile eşleşirse ya da referanstan çözülen kullanıcının ya da tek bir
+// adayın işlemi varsa eşleşir; birden çok aday istisna, hiç aday yoksa eşleşmeyen olur. used bu çalıştırmada bağlanan işlemlerdir.
+func matchBankLine(l *models.BankStatementLine, used map[int]bool, checkDuplicate bool) error {
+	l.MatchedBy, l.TransactionID = "", nil
+	exception := func(reason string) error {
+		l.Status, l.Reason = models.BankLineException, reason
+		return nil
+	}
+	cur, err := resolveCurrency(l.Currency)
+	if err != nil {
+		return exception("unsupported currency")
+	}
+	l.Currency = cur.Code
+	if !l.Amount.IsPositive() {
+		return exception("not a deposit")
+	}
+	if checkDuplicate {
+		dup, err := database.BankStatementRepo().DuplicateLine(*l)
+		if err != nil {
+			return err
+		}
+		if dup != nil {
+			return exception(fmt.Sprintf("duplicate of line %d in bank statement %d", dup.LineNo, dup.StatementID))
+		}
+	}
+	l.SuggestedUserID = resolveDepositUser(l)
+
+	window := time.Duration(config.GetBankImport().MatchWindowDays) * 24 * time.Hour
+	day := time.Date(l.BookingDate.Year(), l.BookingDate.Month(), l.BookingDate.Day(), 0, 0, 0, 0, time.UTC)
+	found, err := database.BankStatementRepo().CreditCandidates(l.Currency, l.Amount, day.Add(-window), day.Add(window+24*time.Hour))
+	if err != nil {
+		return err
+	}
+	var candidates []models.Transaction
+	for _, t := range found {
+		if !used[t.ID] {
+			candidates = append(candidates, t)
+		}
+	}
+	match := func(t models.Transaction, by string) error {
+		id := t.ID
+		used[id] = true
+		l.Status, l.Reason, l.MatchedBy, l.TransactionID = models.BankLineMatched, "", by, &id
+		return nil
+	}
+	if l.Reference != "" {
+		for _, t := range candidates {
+			if t.ExternalRef != nil && *t.ExternalRef == l.Reference {
+				return match(t, models.BankMatchReference)
+			}
+		}
+	}
+	if l.SuggestedUserID != nil {
+		var own []models.Transaction
+		for _, t := range candidates {
+			if t.ToUser == *l.SuggestedUserID {
+				own = append(own, t)
+			}
+		}
+		candidates = own
+	}
+	switch {
+	case len(candidates) == 1 && l.SuggestedUserID != nil:
+		return match(candidates[0], models.BankMatchUser)
+	case len(candidates) == 1:
+		return match(candidates[0], models.BankMatchAmountDate)
+	case len(candidates) > 1:
+		return exception(fmt.Sprintf("ambiguous: %d candidate credits", len(candidates)))
+	}
+	l.Status, l.Reason = models.BankLineUnmatched, "no matching credit"
+	if l.SuggestedUserID == nil {
+		l.Reason += "; user unknown"
+	}
+	return nil
+}
+
+// resolveDepositUser: havale açıklamasındaki cüzdan hesap kimliğinden (ör. "42-USD") ya da referans olarak yazılan
+// kullanıcı adından, kalemin para biriminde cüzdanı olan kullanıcıyı bulur
+func resolveDepositUser(l *models.BankStatementLine) *int {
+	if id, cur, ok := models.FindWalletAccountID(l.Reference + " " + l.Description); ok && cur == l.Currency {
+		if _, err := database.BalanceRepo().GetBalance(id, cur); err == nil {
+			return &id
+		}
+	}
+	if l.Reference == "" {
+		return nil
+	}
+	u, err := database.UserRepo().GetUserByUsername(l.Reference)
+	if err != nil {
+		return nil
+	}
+	if _, err := database.BalanceRepo().GetBalance(u.ID, l.Currency); err != nil {
+		return nil
+	}
+	return &u.ID
+}
+
+// RematchBankStatement: eşleşmeyen ve istisna kalemleri (kayda geçmemiş ve mükerrer kalemler hariç) yeniden eşleştirir;
+// sonradan girilen credit'ler böylece bağlanır
+func RematchBankStatement(actorID, id int) (*models.BankStatementReport, error) {
+	repo := database.BankStatementRepo()
+	if _, err := repo.Get(id); err != nil {
+		return nil, errors.New("bank statement not found")
+	}
+	lines, err := repo.Lines(id, "")
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[int]bool)
+	matched := 0
+	for i := range lines {
+		l := &lines[i]
+		if l.Status != models.BankLineUnmatched && (l.Status != models.BankLineException || !rematchable(l)) {
+			continue
+		}
+		from := l.Status
+		if err := matchBankLine(l, used, false); err != nil {
+			return nil, err
+		}
+		err := repo.TransitionLine(l.ID, []string{from}, l.Status, map[string]interface{}{
+			"reason": l.Reason, "matched_by": l.MatchedBy, "suggested_user_id": l.SuggestedUserID, "transaction_id": l.TransactionID,
+		})
+		if err != nil {
+			// eşzamanlı credit ya da aynı işleme başka kalemin bağlanması; kalem olduğu gibi kalır
+			slog.Warn("service.bank_statement.rematch_skipped", "line_id", l.ID, "err", err)
+			continue
+		}
+		if l.Status == models.BankLineMatched {
+			matched++
+		}
+	}
+	_ = LogAction("bank_statement", id, "rematch", fmt.Sprintf("Rematched by user %d: %d newly matched", actorID, matched))
+	slog.Info("service.bank_statement.rematched", "id", id, "matched", matched)
+	return GetBankStatementReport(id)
+}
+
+// rematchable: yalnızca eşleştirme sonucu oluşan istisnalar yeniden denenir
+func rematchable(l *models.BankStatementLine) bool {
+	return strings.HasPrefix(l.Reason, "ambiguous")
+}
+
+// CreditBankStatementLine: eşleşmeyen yatırım kalemi için kullanıcıya credit yapar (userID 0 ise önerilen kullanıcı).
+// Kalem önce crediting durumuna alınır; credit kalem origin'iyle yazıldığından yeniden deneme ikinci credit oluşturmaz.
+func CreditBankStatementLine(actorID, lineID, userID int) (*models.BankStatementLine, *models.Transaction, error) {
+	repo := database.BankStatementRepo()
+	l, err := repo.GetLine(lineID)
+	if err != nil {
+		return nil, nil, errors.New("bank statement line not found")
+	}
+	if userID == 0 && l.SuggestedUserID != nil {
+		userID = *l.SuggestedUserID
+	}
+	if userID == 0 {
+		return nil, nil, errors.New("user_id required")
+	}
+	if l.Status != models.BankLineUnmatched && l.Status != models.BankLineCrediting {
+		return nil, nil, errors.New("bank statement line is not unmatched")
+	}
+	if err := repo.TransitionLine(l.ID, []string{models.BankLineUnmatched, models.BankLineCrediting}, models.BankLineCrediting, nil); err != nil {
+		return nil, nil, errors.New("bank statement line is not unmatched")
+	}
+	opts := models.TxOptions{
+		Origin: &models.TxOrigin{Type: models.OriginBankStatementLine, ID: l.ID},
+		Meta:   &models.TxMeta{Memo: "Bank deposit " + l.Reference, Category: "deposit"},
+	}
+	_, tx, err := CreditWith(userID, l.Amount, l.Currency, opts)
+	if errors.Is(err, database.ErrDuplicateOrigin) {
+		tx, err = database.TransactionRepo().GetTransactionByOrigin(*opts.Origin)
+	}
+	if err != nil {
+		_ = repo.TransitionLine(l.ID, []string{models.BankLineCrediting}, models.BankLineUnmatched, nil)
+		slog.Error("service.bank_statement.credit_failed", "line_id", l.ID, "user_id", userID, "err", err)
+		return nil, nil, err
+	}
+	now := time.Now()
+	err = repo.TransitionLine(l.ID, []string{models.BankLineCrediting}, models.BankLineCredited, map[string]interface{}{
+		"reason": "", "matched_by": models.BankMatchCredit, "suggested_user_id": userID, "transaction_id": tx.ID,
+		"resolved_by": actorID, "resolved_at": now,
+	})
+	if err != nil {
+		slog.Error("service.bank_statement.mark_credited_failed", "line_id", l.ID, "transaction_id", tx.ID, "err", err)
+		return nil, nil, err
+	}
+	_ = LogAction("bank_statement_line", l.ID, "credit", fmt.Sprintf("Credited %s %s to user %d as transaction %d by user %d", l.Amount, l.Currency, userID, tx.ID, actorID))
+	slog.Info("service.bank_statement.credited", "line_id", l.ID, "user_id", userID, "transaction_id", tx.ID)
+	l, err = repo.GetLine(lineID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, tx, nil
+}
+
+// GetBankStatementReport: durum toplamları, eşleşmeyen ve istisna kalemler
+func GetBankStatementReport(id int) (*models.BankStatementReport, error) {
+	repo := database.BankStatementRepo()
+	stmt, err := repo.Get(id)
+	if err != nil {
+		return nil, errors.New("bank statement not found")
+	}
+	report := &models.BankStatementReport{Statement: stmt}
+	if report.Totals, err = repo.Totals(id); err != nil {
+		return nil, err
+	}
+	if report.Unmatched, err = repo.Lines(id, models.BankLineUnmatched); err != nil {
+		return nil, err
+	}
+	if report.Exceptions, err = repo.Lines(id, models.BankLineException); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetBankStatement: ekstre ve kalemleri (status boşsa hepsi)
+func GetBankStatement(id int, status string) (*models.BankStatement, []models.BankStatementLine, error) {
+	stmt, err := database.BankStatementRepo().Get(id)
+	if err != nil {
+		return nil, nil, errors.New("bank statement not found")
+	}
+	lines, err := database.BankStatementRepo().Lines(id, status)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stmt, lines, nil
+}
+
+func ListBankStatements(limit int) ([]models.BankStatement, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return database.BankStatementRepo().List(limit)
+}
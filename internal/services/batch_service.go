@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+)
+
+// SubmitBatch: kullanıcının credit/debit/transfer kalemlerini tek istekte çalıştırır ve batch'i kaydeder.
+// Kalem hataları (yetersiz bakiye, limit vb.) batch'e yazılır; yalnızca istek geçersizse ya da batch
+// kaydedilemezse hata döner.
+func SubmitBatch(userID int, mode string, items []models.BatchItem) (*models.Batch, error) {
+	if mode == "" {
+		mode = models.BatchBestEffort
+	}
+	if mode != models.BatchAllOrNothing && mode != models.BatchBestEffort {
+		return nil, errors.New("mode must be all_or_nothing or best_effort")
+	}
+	if len(items) == 0 {
+		return nil, errors.New("items must not be empty")
+	}
+	if max := config.GetBatchMaxItems(); len(items) > max {
+		return nil, fmt.Errorf("too many items (max %d)", max)
+	}
+	for i := range items {
+		if err := validateBatchItem(userID, &items[i]); err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		items[i].Seq = i
+	}
+
+	b := &models.Batch{UserID: userID, Mode: mode, Status: models.BatchProcessing, ItemCount: len(items), Items: items}
+	if err := database.BatchRepo().CreateBatch(b); err != nil {
+		slog.Error("service.batch.create_failed", "user_id", userID, "err", err)
+		return nil, err
+	}
+	slog.Info("service.batch.start", "batch_id", b.ID, "user_id", userID, "mode", mode, "items", len(items))
+
+	// limit ve ücretler kalem başına çalıştırmadan önce çözülür; para birimi başına limit bir kez okunur
+	limits := map[string]*models.EffectiveLimit{}
+	opts := make([]models.TxOptions, len(items))
+	var prepErr []error
+	for i := range b.Items {
+		it := &b.Items[i]
+		opts[i].Origin = &models.TxOrigin{Type: models.OriginBatch, ID: b.ID, Seq: it.Seq}
+		prepErr = append(prepErr, prepareBatchItem(userID, it, &opts[i], limits))
+	}
+
+	if mode == models.BatchAllOrNothing {
+		for i, err := range prepErr {
+			if err != nil {
+				// geçersiz kalem çalıştırmadan tüm batch'i düşürür
+				for j := range b.Items {
+					b.Items[j].Status = models.BatchItemRolledBack
+				}
+				b.Items[i].Status, b.Items[i].Error = models.BatchItemFailed, err.Error()
+				return finishBatch(b, database.BatchRepo().SaveItems(b))
+			}
+		}
+		err := database.BatchRepo().RunAllOrNothing(b, opts)
+		for i := range b.Items {
+			if b.Items[i].Status == models.BatchItemFailed {
+				auditLimitViolation(userID, "batch."+b.Items[i].Type, b.Items[i].Amount, err)
+			}
+		}
+		return finishBatch(b, nil)
+	}
+
+	for i := range b.Items {
+		it := &b.Items[i]
+		if prepErr[i] != nil {
+			it.Status, it.Error = models.BatchItemFailed, prepErr[i].Error()
+			continue
+		}
+		if err := database.BatchRepo().RunItem(b, i, opts[i]); err != nil {
+			if !auditLimitViolation(userID, "batch."+it.Type, it.Amount, err) {
+				slog.Warn("service.batch.item_failed", "batch_id", b.ID, "seq", it.Seq, "type", it.Type, "err", err)
+			}
+		}
+	}
+	return finishBatch(b, database.BatchRepo().SaveItems(b))
+}
+
+// GetBatch: batch ve kalemleri (yalnızca sahibi ya da admin görebilir)
+func GetBatch(userID int, isAdmin bool, id int) (*models.Batch, error) {
+	b, err := database.BatchRepo().GetBatch(id)
+	if err != nil || (!isAdmin && b.UserID != userID) {
+		return nil, errors.New("batch not found")
+	}
+	return b, nil
+}
+
+// ListBatches: kullanıcının son batch'leri (kalemler olmadan)
+func ListBatches(userID, limit int) ([]models.Batch, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return database.BatchRepo().ListBatches(userID, limit)
+}
+
+// validateBatchItem: yapısal doğrulama (tip, tutar, para birimi, alıcı)
+func validateBatchItem(userID int, it *models.BatchItem) error {
+	// sunucu tarafı alanlar istemciden alınmaz
+	it.ID, it.BatchID, it.TransactionID, it.Status, it.Error = 0, 0, nil, "", ""
+	it.Type = strings.ToLower(strings.TrimSpace(it.Type))
+	switch it.Type {
+	case "credit", "debit":
+		it.ToUserID = nil
+	case "transfer":
+		if it.ToUserID == nil || *it.ToUserID == 0 || *it.ToUserID == userID {
+			return errors.New("invalid recipient")
+		}
+	default:
+		return errors.New("type must be credit, debit or transfer")
+	}
+	if !it.Amount.IsPositive() {
+		return errors.New("amount must be positive")
+	}
+	code, err := resolveAmount(it.Currency, it.Amount)
+	if err != nil {
+		return err
+	}
+	it.Currency = code
+	return nil
+}
+
+// prepareBatchItem: giden kalemler için limit ve ücret seçeneklerini doldurur
+func prepareBatchItem(userID int, it *models.BatchItem, opts *models.TxOptions, limits map[string]*models.EffectiveLimit) error {
+	if it.Type == "credit" {
+		return nil
+	}
+	lim, ok := limits[it.Currency]
+	if !ok {
+		var err error
+		if lim, err = ResolveLimits(userID, it.Currency); err != nil {
+			return err
+		}
+		limits[it.Currency] = lim
+	}
+	fee, err := QuoteFee(userID, it.Type, it.Amount, it.Currency)
+	if err != nil {
+		return err
+	}
+	opts.Limit, opts.Fee = lim, fee
+	return nil
+}
+
+// finishBatch: sayaçları ve durumu yazar, başarılı kalemler için audit kaydı açar
+func finishBatch(b *models.Batch, runErr error) (*models.Batch, error) {
+	if runErr != nil {
+		slog.Error("service.batch.run_failed", "batch_id", b.ID, "err", runErr)
+	}
+	if err := database.BatchRepo().FinishBatch(b); err != nil {
+		slog.Error("service.batch.finish_failed", "batch_id", b.ID, "err", err)
+		return nil, err
+	}
+	for _, it := range b.Items {
+		if it.Status == models.BatchItemSucceeded && it.TransactionID != nil {
+			_ = LogAction("transaction", *it.TransactionID, it.Type, fmt.Sprintf("Batch %d item %d: %s %s", b.ID, it.Seq, it.Amount, it.Currency))
+		}
+	}
+	_ = LogAction("batch", b.ID, b.Status, fmt.Sprintf("%s batch of %d items by user %d: %d succeeded, %d failed", b.Mode, b.ItemCount, b.UserID, b.SucceededCount, b.FailedCount))
+	slog.Info("service.batch.done", "batch_id", b.ID, "status", b.Status, "succeeded", b.SucceededCount, "failed", b.FailedCount)
+	return b, nil
+}
@@ -1,29 +1,79 @@
 package services
 
 import (
+	"context"
 	"insider-go-backend/internal/models"
+	"io"
 	"time"
 )
 
 // Varsayılan servis örnekleri (fonksiyonları mevcut global fonksiyonlara delege eder)
 var (
-	defaultUserService        UserService        = userServiceImpl{}
-	defaultBalanceService     BalanceService     = balanceServiceImpl{}
-	defaultTransactionService TransactionService = transactionServiceImpl{}
-	defaultAuditLogService    AuditLogService    = auditLogServiceImpl{}
+	defaultUserService              UserService              = userServiceImpl{}
+	defaultBalanceService           BalanceService           = balanceServiceImpl{}
+	defaultTransactionService       TransactionService       = transactionServiceImpl{}
+	defaultAuditLogService          AuditLogService          = auditLogServiceImpl{}
+	defaultLedgerService            LedgerService            = ledgerServiceImpl{}
+	defaultFXService                FXService                = fxServiceImpl{}
+	defaultHoldService              HoldService              = holdServiceImpl{}
+	defaultScheduledTransferService ScheduledTransferService = scheduledTransferServiceImpl{}
+	defaultStandingOrderService     StandingOrderService     = standingOrderServiceImpl{}
+	defaultLimitService             LimitService             = limitServiceImpl{}
+	defaultOverdraftService         OverdraftService         = overdraftServiceImpl{}
+	defaultFeeService               FeeService               = feeServiceImpl{}
+	defaultBatchService             BatchService             = batchServiceImpl{}
+	defaultPaymentRequestService    PaymentRequestService    = paymentRequestServiceImpl{}
+	defaultEscrowService            EscrowService            = escrowServiceImpl{}
+	defaultInterestService          InterestService          = interestServiceImpl{}
+	defaultStatementService         StatementService         = statementServiceImpl{}
+	defaultExportService            ExportService            = exportServiceImpl{}
+	defaultBankStatementService     BankStatementService     = bankStatementServiceImpl{}
+	defaultPaymentInitiationService PaymentInitiationService = paymentInitiationServiceImpl{}
 )
 
 // Getter'lar
-func UserSvc() UserService               { return defaultUserService }
-func BalanceSvc() BalanceService         { return defaultBalanceService }
-func TransactionSvc() TransactionService { return defaultTransactionService }
-func AuditLogSvc() AuditLogService       { return defaultAuditLogService }
+func UserSvc() UserService                           { return defaultUserService }
+func BalanceSvc() BalanceService                     { return defaultBalanceService }
+func TransactionSvc() TransactionService             { return defaultTransactionService }
+func AuditLogSvc() AuditLogService                   { return defaultAuditLogService }
+func LedgerSvc() LedgerService                       { return defaultLedgerService }
+func FXSvc() FXService                               { return defaultFXService }
+func HoldSvc() HoldService                           { return defaultHoldService }
+func ScheduledTransferSvc() ScheduledTransferService { return defaultScheduledTransferService }
+func StandingOrderSvc() StandingOrderService         { return defaultStandingOrderService }
+func LimitSvc() LimitService                         { return defaultLimitService }
+func OverdraftSvc() OverdraftService                 { return defaultOverdraftService }
+func FeeSvc() FeeService                             { return defaultFeeService }
+func BatchSvc() BatchService                         { return defaultBatchService }
+func PaymentRequestSvc() PaymentRequestService       { return defaultPaymentRequestService }
+func EscrowSvc() EscrowService                       { return defaultEscrowService }
+func InterestSvc() InterestService                   { return defaultInterestService }
+func StatementSvc() StatementService                 { return defaultStatementService }
+func ExportSvc() ExportService                       { return defaultExportService }
+func BankStatementSvc() BankStatementService         { return defaultBankStatementService }
+func PaymentInitiationSvc() PaymentInitiationService { return defaultPaymentInitiationService }
 
 // Setters (test veya özel implementasyonlar için)
-func SetUserSvc(s UserService)               { defaultUserService = s }
-func SetBalanceSvc(s BalanceService)         { defaultBalanceService = s }
-func SetTransactionSvc(s TransactionService) { defaultTransactionService = s }
-func SetAuditLogSvc(s AuditLogService)       { defaultAuditLogService = s }
+func SetUserSvc(s UserService)                           { defaultUserService = s }
+func SetBalanceSvc(s BalanceService)                     { defaultBalanceService = s }
+func SetTransactionSvc(s TransactionService)             { defaultTransactionService = s }
+func SetAuditLogSvc(s AuditLogService)                   { defaultAuditLogService = s }
+func SetLedgerSvc(s LedgerService)                       { defaultLedgerService = s }
+func SetFXSvc(s FXService)                               { defaultFXService = s }
+func SetHoldSvc(s HoldService)                           { defaultHoldService = s }
+func SetScheduledTransferSvc(s ScheduledTransferService) { defaultScheduledTransferService = s }
+func SetStandingOrderSvc(s StandingOrderService)         { defaultStandingOrderService = s }
+func SetLimitSvc(s LimitService)                         { defaultLimitService = s }
+func SetOverdraftSvc(s OverdraftService)                 { defaultOverdraftService = s }
+func SetFeeSvc(s FeeService)                             { defaultFeeService = s }
+func SetBatchSvc(s BatchService)                         { defaultBatchService = s }
+func SetPaymentRequestSvc(s PaymentRequestService)       { defaultPaymentRequestService = s }
+func SetEscrowSvc(s EscrowService)                       { defaultEscrowService = s }
+func SetInterestSvc(s InterestService)                   { defaultInterestService = s }
+func SetStatementSvc(s StatementService)                 { defaultStatementService = s }
+func SetExportSvc(s ExportService)                       { defaultExportService = s }
+func SetBankStatementSvc(s BankStatementService)         { defaultBankStatementService = s }
+func SetPaymentInitiationSvc(s PaymentInitiationService) { defaultPaymentInitiationService = s }
 
 // Basit implementasyonlar: varolan paket-level fonksiyonlara delege
 type userServiceImpl struct{}
@@ -45,8 +95,8 @@ func (userServiceImpl) ParseJWT(tokenStr string) (int, string, error) { return P
 func (userServiceImpl) CheckUserRole(user *models.User, role string) bool {
 	return CheckUserRole(user, role)
 }
-func (userServiceImpl) CreateBalanceForUser(userID int, initialAmount float64) error {
-	return CreateBalanceForUser(userID, initialAmount)
+func (userServiceImpl) CreateBalanceForUser(userID int, currency string, initialAmount models.Money) error {
+	return CreateBalanceForUser(userID, currency, initialAmount)
 }
 func (userServiceImpl) ListUsers() ([]*models.User, error)   { return ListUsers() }
 func (userServiceImpl) GetUser(id int) (*models.User, error) { return GetUser(id) }
@@ -57,28 +107,53 @@ func (userServiceImpl) DeleteUser(id int) error { return DeleteUser(id) }
 
 type balanceServiceImpl struct{}
 
-func (balanceServiceImpl) AddOrUpdateBalance(userID int, amount float64) error {
-	return AddOrUpdateBalance(userID, amount)
+func (balanceServiceImpl) AddOrUpdateBalance(userID int, currency string, amount models.Money) error {
+	return AddOrUpdateBalance(userID, currency, amount)
 }
-func (balanceServiceImpl) GetUserBalance(userID int) (float64, error)     { return GetUserBalance(userID) }
-func (balanceServiceImpl) GetBalance(userID int) (*models.Balance, error) { return GetBalance(userID) }
-func (balanceServiceImpl) SetBalance(userID int, amount float64) (*models.Balance, error) {
-	return SetBalance(userID, amount)
+func (balanceServiceImpl) GetUserBalance(userID int, currency string) (models.Money, error) {
+	return GetUserBalance(userID, currency)
 }
-func (balanceServiceImpl) CalculateBalanceAt(userID int, at time.Time) (float64, error) {
-	return CalculateBalanceAt(userID, at)
+func (balanceServiceImpl) GetBalance(userID int, currency string) (*models.Balance, error) {
+	return GetBalance(userID, currency)
+}
+func (balanceServiceImpl) GetBalances(userID int) ([]models.Balance, error) {
+	return GetBalances(userID)
+}
+func (balanceServiceImpl) OpenWallet(userID int, currency string) (*models.Balance, error) {
+	return OpenWallet(userID, currency)
+}
+func (balanceServiceImpl) SetBalance(userID int, currency string, amount models.Money) (*models.Balance, error) {
+	return SetBalance(userID, currency, amount)
+}
+func (balanceServiceImpl) CalculateBalanceAt(userID int, currency string, at time.Time) (models.Money, error) {
+	return CalculateBalanceAt(userID, currency, at)
+}
+func (balanceServiceImpl) WriteBalanceCheckpoints(now time.Time, lag time.Duration) (int64, error) {
+	return WriteBalanceCheckpoints(now, lag)
 }
 
 type transactionServiceImpl struct{}
 
-func (transactionServiceImpl) Credit(userID int, amount float64) (float64, error) {
-	return Credit(userID, amount)
+func (transactionServiceImpl) Credit(userID int, amount models.Money, currency string) (models.Money, error) {
+	return Credit(userID, amount, currency)
+}
+func (transactionServiceImpl) Debit(userID int, amount models.Money, currency string) (models.Money, error) {
+	return Debit(userID, amount, currency)
+}
+func (transactionServiceImpl) CreditWith(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	return CreditWith(userID, amount, currency, opts)
+}
+func (transactionServiceImpl) DebitWith(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	return DebitWith(userID, amount, currency, opts)
 }
-func (transactionServiceImpl) Debit(userID int, amount float64) (float64, error) {
-	return Debit(userID, amount)
+func (transactionServiceImpl) Transfer(fromUserID, toUserID int, amount models.Money, currency string) (models.Money, models.Money, error) {
+	return Transfer(fromUserID, toUserID, amount, currency)
 }
-func (transactionServiceImpl) Transfer(fromUserID, toUserID int, amount float64) (float64, float64, error) {
-	return Transfer(fromUserID, toUserID, amount)
+func (transactionServiceImpl) TransferWith(fromUserID, toUserID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, models.Money, *models.Transaction, error) {
+	return TransferWith(fromUserID, toUserID, amount, currency, opts)
+}
+func (transactionServiceImpl) SplitTransfer(fromUserID int, amount models.Money, currency string, parts []models.SplitPart) (models.Money, *models.Transaction, error) {
+	return SplitTransfer(fromUserID, amount, currency, parts)
 }
 func (transactionServiceImpl) GetTransactionsByUser(userID int) ([]*models.Transaction, error) {
 	return GetTransactionsByUser(userID)
@@ -86,6 +161,18 @@ func (transactionServiceImpl) GetTransactionsByUser(userID int) ([]*models.Trans
 func (transactionServiceImpl) GetTransactionByID(id int) (*models.Transaction, error) {
 	return GetTransactionByID(id)
 }
+func (transactionServiceImpl) ListTransactionHistory(userID int, f models.TxFilter) (*models.TxPage, error) {
+	return ListTransactionHistory(userID, f)
+}
+func (transactionServiceImpl) UpdateTransactionMeta(actorID, txID int, patch models.TxMetaPatch) (*models.Transaction, error) {
+	return UpdateTransactionMeta(actorID, txID, patch)
+}
+func (transactionServiceImpl) ReverseTransaction(txID int, reason string, actorID int) (*models.Transaction, *models.Transaction, error) {
+	return ReverseTransaction(txID, reason, actorID)
+}
+func (transactionServiceImpl) RefundTransaction(txID int, amount models.Money, reason string, actorID int) (*models.Transaction, *models.Transaction, error) {
+	return RefundTransaction(txID, amount, reason, actorID)
+}
 
 type auditLogServiceImpl struct{}
 
@@ -95,3 +182,299 @@ func (auditLogServiceImpl) LogAction(entity string, entityID int, action, detail
 func (auditLogServiceImpl) GetEntityLogs(entity string, entityID int) ([]models.AuditLog, error) {
 	return GetEntityLogs(entity, entityID)
 }
+
+type ledgerServiceImpl struct{}
+
+func (ledgerServiceImpl) TrialBalance(at time.Time) (*models.TrialBalance, error) {
+	return TrialBalance(at)
+}
+func (ledgerServiceImpl) GetLedgerEntriesByTransaction(txID int) ([]models.LedgerEntry, error) {
+	return GetLedgerEntriesByTransaction(txID)
+}
+func (ledgerServiceImpl) GetLedgerEntriesByAccount(account string) ([]models.LedgerEntry, error) {
+	return GetLedgerEntriesByAccount(account)
+}
+func (ledgerServiceImpl) Reconcile(apply bool, actorID int) (*models.ReconcileReport, error) {
+	return Reconcile(apply, actorID)
+}
+
+type fxServiceImpl struct{}
+
+func (fxServiceImpl) QuoteFX(fromCurrency, toCurrency string, amount models.Money, at time.Time) (*models.FXQuote, error) {
+	return QuoteFX(fromCurrency, toCurrency, amount, at)
+}
+func (fxServiceImpl) TransferFX(fromUserID, toUserID int, amount models.Money, fromCurrency, toCurrency string) (models.Money, models.Money, *models.FXQuote, error) {
+	return TransferFX(fromUserID, toUserID, amount, fromCurrency, toCurrency)
+}
+func (fxServiceImpl) TransferFXWith(fromUserID, toUserID int, amount models.Money, fromCurrency, toCurrency string, opts models.TxOptions) (models.Money, models.Money, *models.FXQuote, *models.Transaction, error) {
+	return TransferFXWith(fromUserID, toUserID, amount, fromCurrency, toCurrency, opts)
+}
+func (fxServiceImpl) CreateFXRate(rate *models.FXRate) error { return CreateFXRate(rate) }
+func (fxServiceImpl) ImportFXRatesCSV(r io.Reader, createdBy int) (int, error) {
+	return ImportFXRatesCSV(r, createdBy)
+}
+func (fxServiceImpl) ListFXRates(base, quote string, limit int) ([]models.FXRate, error) {
+	return ListFXRates(base, quote, limit)
+}
+
+type holdServiceImpl struct{}
+
+func (holdServiceImpl) AuthorizeHold(userID, toUserID int, amount models.Money, currency string, ttl time.Duration, reference string) (*models.Hold, error) {
+	return AuthorizeHold(userID, toUserID, amount, currency, ttl, reference)
+}
+func (holdServiceImpl) CaptureHold(actorID, holdID int, amount models.Money) (*models.Hold, *models.Transaction, error) {
+	return CaptureHold(actorID, holdID, amount)
+}
+func (holdServiceImpl) VoidHold(actorID, holdID int) (*models.Hold, error) {
+	return VoidHold(actorID, holdID)
+}
+func (holdServiceImpl) ListHolds(userID int, status string) ([]models.Hold, error) {
+	return ListHolds(userID, status)
+}
+func (holdServiceImpl) ExpireHolds() (int, error) { return ExpireHolds() }
+
+type scheduledTransferServiceImpl struct{}
+
+func (scheduledTransferServiceImpl) CreateScheduledTransfer(userID, toUserID int, amount models.Money, currency string, executeAt time.Time) (*models.ScheduledTransfer, error) {
+	return CreateScheduledTransfer(userID, toUserID, amount, currency, executeAt)
+}
+func (scheduledTransferServiceImpl) ListScheduledTransfers(userID int, status string) ([]models.ScheduledTransfer, error) {
+	return ListScheduledTransfers(userID, status)
+}
+func (scheduledTransferServiceImpl) GetScheduledTransfer(userID, id int) (*models.ScheduledTransfer, []models.ScheduledTransferRun, error) {
+	return GetScheduledTransfer(userID, id)
+}
+func (scheduledTransferServiceImpl) UpdateScheduledTransfer(userID, id, toUserID int, amount models.Money, currency string, executeAt time.Time) (*models.ScheduledTransfer, error) {
+	return UpdateScheduledTransfer(userID, id, toUserID, amount, currency, executeAt)
+}
+func (scheduledTransferServiceImpl) CancelScheduledTransfer(userID, id int) error {
+	return CancelScheduledTransfer(userID, id)
+}
+func (scheduledTransferServiceImpl) RunDueScheduledTransfers(ctx context.Context) (int, error) {
+	return RunDueScheduledTransfers(ctx)
+}
+
+type standingOrderServiceImpl struct{}
+
+func (standingOrderServiceImpl) CreateStandingOrder(o *models.StandingOrder) error {
+	return CreateStandingOrder(o)
+}
+func (standingOrderServiceImpl) ListStandingOrders(userID int, status string) ([]models.StandingOrder, error) {
+	return ListStandingOrders(userID, status)
+}
+func (standingOrderServiceImpl) GetStandingOrder(userID, id int) (*models.StandingOrder, error) {
+	return GetStandingOrder(userID, id)
+}
+func (standingOrderServiceImpl) GetStandingOrderRuns(userID, id int) ([]models.StandingOrderRun, error) {
+	return GetStandingOrderRuns(userID, id)
+}
+func (standingOrderServiceImpl) PauseStandingOrder(userID, id int) error {
+	return PauseStandingOrder(userID, id)
+}
+func (standingOrderServiceImpl) ResumeStandingOrder(userID, id int) error {
+	return ResumeStandingOrder(userID, id)
+}
+func (standingOrderServiceImpl) CancelStandingOrder(userID, id int) error {
+	return CancelStandingOrder(userID, id)
+}
+func (standingOrderServiceImpl) RunDueStandingOrders(ctx context.Context) (int, error) {
+	return RunDueStandingOrders(ctx)
+}
+
+type limitServiceImpl struct{}
+
+func (limitServiceImpl) ResolveLimits(userID int, currency string) (*models.EffectiveLimit, error) {
+	return ResolveLimits(userID, currency)
+}
+func (limitServiceImpl) UpsertLimitPolicy(p *models.LimitPolicy, actorID int) error {
+	return UpsertLimitPolicy(p, actorID)
+}
+func (limitServiceImpl) DeleteLimitPolicy(id, actorID int) error {
+	return DeleteLimitPolicy(id, actorID)
+}
+func (limitServiceImpl) ListLimitPolicies(role string, userID int) ([]models.LimitPolicy, error) {
+	return ListLimitPolicies(role, userID)
+}
+
+type overdraftServiceImpl struct{}
+
+func (overdraftServiceImpl) SetCreditLine(actorID, userID int, currency string, limit models.Money, rateBps *int) (*models.Balance, error) {
+	return SetCreditLine(actorID, userID, currency, limit, rateBps)
+}
+func (overdraftServiceImpl) AccrueOverdraftInterest(now time.Time) (int, error) {
+	return AccrueOverdraftInterest(now)
+}
+
+type feeServiceImpl struct{}
+
+func (feeServiceImpl) QuoteFee(userID int, txType string, amount models.Money, currency string) (*models.FeeQuote, error) {
+	return QuoteFee(userID, txType, amount, currency)
+}
+func (feeServiceImpl) CreateFeeSchedule(f *models.FeeSchedule, actorID int) error {
+	return CreateFeeSchedule(f, actorID)
+}
+func (feeServiceImpl) UpdateFeeSchedule(f *models.FeeSchedule, actorID int) error {
+	return UpdateFeeSchedule(f, actorID)
+}
+func (feeServiceImpl) DeleteFeeSchedule(id, actorID int) error {
+	return DeleteFeeSchedule(id, actorID)
+}
+func (feeServiceImpl) ListFeeSchedules(txType, currency string, at *time.Time) ([]models.FeeSchedule, error) {
+	return ListFeeSchedules(txType, currency, at)
+}
+func (feeServiceImpl) GetFeeSchedule(id int) (*models.FeeSchedule, error) {
+	return GetFeeSchedule(id)
+}
+
+type batchServiceImpl struct{}
+
+func (batchServiceImpl) SubmitBatch(userID int, mode string, items []models.BatchItem) (*models.Batch, error) {
+	return SubmitBatch(userID, mode, items)
+}
+func (batchServiceImpl) GetBatch(userID int, isAdmin bool, id int) (*models.Batch, error) {
+	return GetBatch(userID, isAdmin, id)
+}
+func (batchServiceImpl) ListBatches(userID, limit int) ([]models.Batch, error) {
+	return ListBatches(userID, limit)
+}
+
+type paymentRequestServiceImpl struct{}
+
+func (paymentRequestServiceImpl) CreatePaymentRequest(requesterID, payerID int, amount models.Money, currency, note string, ttl time.Duration) (*models.PaymentRequest, error) {
+	return CreatePaymentRequest(requesterID, payerID, amount, currency, note, ttl)
+}
+func (paymentRequestServiceImpl) ListPaymentRequests(userID int, direction, status string) ([]models.PaymentRequest, error) {
+	return ListPaymentRequests(userID, direction, status)
+}
+func (paymentRequestServiceImpl) GetPaymentRequest(userID, id int) (*models.PaymentRequest, error) {
+	return GetPaymentRequest(userID, id)
+}
+func (paymentRequestServiceImpl) AcceptPaymentRequest(payerID, id int) (*models.PaymentRequest, error) {
+	return AcceptPaymentRequest(payerID, id)
+}
+func (paymentRequestServiceImpl) DeclinePaymentRequest(payerID, id int) (*models.PaymentRequest, error) {
+	return DeclinePaymentRequest(payerID, id)
+}
+func (paymentRequestServiceImpl) CancelPaymentRequest(requesterID, id int) (*models.PaymentRequest, error) {
+	return CancelPaymentRequest(requesterID, id)
+}
+func (paymentRequestServiceImpl) ExpirePaymentRequests() (int, error) {
+	return ExpirePaymentRequests()
+}
+
+type escrowServiceImpl struct{}
+
+func (escrowServiceImpl) OpenEscrow(buyerID, sellerID int, amount models.Money, currency, description string, ttl time.Duration, timeoutOutcome string) (*models.Escrow, error) {
+	return OpenEscrow(buyerID, sellerID, amount, currency, description, ttl, timeoutOutcome)
+}
+func (escrowServiceImpl) ListEscrows(userID int, side, status string) ([]models.Escrow, error) {
+	return ListEscrows(userID, side, status)
+}
+func (escrowServiceImpl) ListAllEscrows(status string) ([]models.Escrow, error) {
+	return ListAllEscrows(status)
+}
+func (escrowServiceImpl) GetEscrow(userID int, isAdmin bool, id int) (*models.Escrow, error) {
+	return GetEscrow(userID, isAdmin, id)
+}
+func (escrowServiceImpl) ConfirmEscrow(userID, id int, outcome string) (*models.Escrow, error) {
+	return ConfirmEscrow(userID, id, outcome)
+}
+func (escrowServiceImpl) ArbitrateEscrow(adminID, id int, outcome, reason string) (*models.Escrow, error) {
+	return ArbitrateEscrow(adminID, id, outcome, reason)
+}
+func (escrowServiceImpl) SettleExpiredEscrows() (int, error) {
+	return SettleExpiredEscrows()
+}
+
+type interestServiceImpl struct{}
+
+func (interestServiceImpl) CreateInterestRate(r *models.InterestRate, actorID int) error {
+	return CreateInterestRate(r, actorID)
+}
+func (interestServiceImpl) UpdateInterestRate(r *models.InterestRate, actorID int) error {
+	return UpdateInterestRate(r, actorID)
+}
+func (interestServiceImpl) DeleteInterestRate(id, actorID int) error {
+	return DeleteInterestRate(id, actorID)
+}
+func (interestServiceImpl) ListInterestRates(currency string, at *time.Time) ([]models.InterestRate, error) {
+	return ListInterestRates(currency, at)
+}
+func (interestServiceImpl) GetInterestRate(id int) (*models.InterestRate, error) {
+	return GetInterestRate(id)
+}
+func (interestServiceImpl) AccrueInterest(now time.Time) (int, error) {
+	return AccrueInterest(now)
+}
+func (interestServiceImpl) PostInterest(at time.Time) (int, error) {
+	return PostInterest(at)
+}
+func (interestServiceImpl) PreviewInterest(from, to time.Time, userID int, currency string) (*models.InterestPreview, error) {
+	return PreviewInterest(from, to, userID, currency)
+}
+
+type statementServiceImpl struct{}
+
+func (statementServiceImpl) GenerateStatement(userID int, currency string, month time.Time) (*models.Statement, error) {
+	return GenerateStatement(userID, currency, month)
+}
+func (statementServiceImpl) GenerateMonthlyStatements(now time.Time) (int, error) {
+	return GenerateMonthlyStatements(now)
+}
+func (statementServiceImpl) ListStatements(userID int, currency string) ([]models.Statement, error) {
+	return ListStatements(userID, currency)
+}
+func (statementServiceImpl) GetStatement(userID int, isAdmin bool, id int) (*models.Statement, error) {
+	return GetStatement(userID, isAdmin, id)
+}
+
+type exportServiceImpl struct{}
+
+func (exportServiceImpl) PrepareExport(userID int, currency string, from, to time.Time) (*models.ExportHeader, error) {
+	return PrepareExport(userID, currency, from, to)
+}
+func (exportServiceImpl) StreamExport(actorID int, h *models.ExportHeader, w models.ExportWriter, format string) error {
+	return StreamExport(actorID, h, w, format)
+}
+
+type bankStatementServiceImpl struct{}
+
+func (bankStatementServiceImpl) ImportBankStatement(actorID int, source, filename string, data []byte, mapping *models.BankCSVMapping) (*models.BankStatementReport, error) {
+	return ImportBankStatement(actorID, source, filename, data, mapping)
+}
+func (bankStatementServiceImpl) RematchBankStatement(actorID, id int) (*models.BankStatementReport, error) {
+	return RematchBankStatement(actorID, id)
+}
+func (bankStatementServiceImpl) CreditBankStatementLine(actorID, lineID, userID int) (*models.BankStatementLine, *models.Transaction, error) {
+	return CreditBankStatementLine(actorID, lineID, userID)
+}
+func (bankStatementServiceImpl) GetBankStatementReport(id int) (*models.BankStatementReport, error) {
+	return GetBankStatementReport(id)
+}
+func (bankStatementServiceImpl) GetBankStatement(id int, status string) (*models.BankStatement, []models.BankStatementLine, error) {
+	return GetBankStatement(id, status)
+}
+func (bankStatementServiceImpl) ListBankStatements(limit int) ([]models.BankStatement, error) {
+	return ListBankStatements(limit)
+}
+
+type paymentInitiationServiceImpl struct{}
+
+func (paymentInitiationServiceImpl) SubmitPain001(actorID int, isAdmin bool, filename string, data []byte) (*models.PaymentInitiation, error) {
+	return SubmitPain001(actorID, isAdmin, filename, data)
+}
+func (paymentInitiationServiceImpl) RecordPaymentInitiationTx(t models.PaymentInitiationTx, tx *models.Transaction, err error) {
+	RecordPaymentInitiationTx(t, tx, err)
+}
+func (paymentInitiationServiceImpl) FinishPaymentInitiation(id int) (*models.PaymentInitiation, error) {
+	return FinishPaymentInitiation(id)
+}
+func (paymentInitiationServiceImpl) PendingPaymentInitiations() ([]*models.PaymentInitiation, error) {
+	return PendingPaymentInitiations()
+}
+func (paymentInitiationServiceImpl) GetPaymentInitiation(actorID int, isAdmin bool, id int) (*models.PaymentInitiation, error) {
+	return GetPaymentInitiation(actorID, isAdmin, id)
+}
+func (paymentInitiationServiceImpl) ListPaymentInitiations(actorID, limit int) ([]models.PaymentInitiation, error) {
+	return ListPaymentInitiations(actorID, limit)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// escrowSweepBatch: timeout job'unun tek turda kapattığı en fazla escrow sayısı
+const escrowSweepBatch = 200
+
+// OpenEscrow: alıcının tutarını system:escrow hesabına aktararak escrow açar.
+// ttl sıfırsa ESCROW_TTL, timeoutOutcome boşsa ESCROW_TIMEOUT_OUTCOME kullanılır.
+func OpenEscrow(buyerID, sellerID int, amount models.Money, currency, description string, ttl time.Duration, timeoutOutcome string) (*models.Escrow, error) {
+	if sellerID == 0 || sellerID == buyerID {
+		return nil, errors.New("invalid seller")
+	}
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.GetEscrow()
+	if ttl <= 0 {
+		ttl = cfg.TTL
+	}
+	if ttl > cfg.MaxTTL {
+		return nil, fmt.Errorf("escrow ttl exceeds maximum of %s", cfg.MaxTTL)
+	}
+	if timeoutOutcome == "" {
+		timeoutOutcome = cfg.TimeoutOutcome
+	}
+	if !models.IsValidEscrowOutcome(timeoutOutcome) {
+		return nil, errors.New("timeout_outcome must be release or refund")
+	}
+	lim, err := ResolveLimits(buyerID, currency)
+	if err != nil {
+		return nil, err
+	}
+	e := &models.Escrow{
+		BuyerID: buyerID, SellerID: sellerID, Amount: amount, Currency: currency,
+		Description: strings.TrimSpace(description), TimeoutOutcome: timeoutOutcome, ExpiresAt: time.Now().Add(ttl),
+	}
+	slog.Info("service.escrow.open.start", "buyer_id", buyerID, "seller_id", sellerID, "amount", amount, "currency", currency)
+	if _, err := database.EscrowRepo().Fund(e, models.TxOptions{Limit: lim}); err != nil {
+		if auditLimitViolation(buyerID, models.TxTypeEscrowFund, amount, err) {
+			return nil, err
+		}
+		if err.Error() == "insufficient funds" {
+			slog.Warn("service.escrow.open.insufficient_funds", "buyer_id", buyerID, "amount", amount)
+		} else {
+			slog.Error("service.escrow.open.failed", "buyer_id", buyerID, "err", err)
+		}
+		return nil, err
+	}
+	_ = LogAction("escrow", e.ID, "fund", fmt.Sprintf("User %d funded %s %s for user %d (transaction %d), %s on timeout at %s",
+		buyerID, amount, currency, sellerID, *e.FundTransactionID, timeoutOutcome, e.ExpiresAt.Format(time.RFC3339)))
+	slog.Info("service.escrow.open.success", "id", e.ID, "transaction_id", *e.FundTransactionID)
+	return e, nil
+}
+
+// ListEscrows: side buyer | seller | boş (ikisi)
+func ListEscrows(userID int, side, status string) ([]models.Escrow, error) {
+	if side != "" && side != "buyer" && side != "seller" {
+		return nil, errors.New("side must be buyer or seller")
+	}
+	return database.EscrowRepo().List(userID, side, status)
+}
+
+// ListAllEscrows: admin görünümü (ör. status=disputed ile karar bekleyenler)
+func ListAllEscrows(status string) ([]models.Escrow, error) {
+	return database.EscrowRepo().List(0, "", status)
+}
+
+// GetEscrow: yalnızca taraflar ve admin görebilir
+func GetEscrow(userID int, isAdmin bool, id int) (*models.Escrow, error) {
+	e, err := database.EscrowRepo().Get(id)
+	if err != nil || (!isAdmin && e.BuyerID != userID && e.SellerID != userID) {
+		return nil, errors.New("escrow not found")
+	}
+	return e, nil
+}
+
+// ConfirmEscrow: tarafın release/refund onayı; iki taraf aynı sonucu onaylayınca escrow kapanır
+func ConfirmEscrow(userID, id int, outcome string) (*models.Escrow, error) {
+	if !models.IsValidEscrowOutcome(outcome) {
+		return nil, errors.New("outcome must be release or refund")
+	}
+	if _, err := GetEscrow(userID, false, id); err != nil {
+		return nil, err
+	}
+	e, err := database.EscrowRepo().Decide(id, userID, outcome, time.Now())
+	if err != nil {
+		slog.Warn("service.escrow.confirm_failed", "id", id, "user_id", userID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("escrow", id, "confirm", fmt.Sprintf("User %d confirmed %s", userID, outcome))
+	if e.Status == models.EscrowDisputed {
+		_ = LogAction("escrow", id, models.EscrowDisputed, fmt.Sprintf("Buyer confirmed %s, seller confirmed %s", e.BuyerDecision, e.SellerDecision))
+	}
+	logEscrowSettlement(e)
+	return e, nil
+}
+
+// ArbitrateEscrow: admin açık (ihtilaflı ya da değil) escrow'u verilen sonuçla kapatır
+func ArbitrateEscrow(adminID, id int, outcome, reason string) (*models.Escrow, error) {
+	if !models.IsValidEscrowOutcome(outcome) {
+		return nil, errors.New("outcome must be release or refund")
+	}
+	e, err := database.EscrowRepo().Settle(id, outcome, models.EscrowResolutionArbitration, &adminID, time.Now())
+	if err != nil {
+		slog.Warn("service.escrow.arbitrate_failed", "id", id, "admin_id", adminID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("escrow", id, "arbitrate", fmt.Sprintf("Admin %d decided %s: %s", adminID, outcome, strings.TrimSpace(reason)))
+	logEscrowSettlement(e)
+	return e, nil
+}
+
+// SettleExpiredEscrows: süresi dolan ihtilafsız escrow'ları TimeoutOutcome ile kapatır
+func SettleExpiredEscrows() (int, error) {
+	total := 0
+	for {
+		now := time.Now()
+		ids, err := database.EscrowRepo().DueForTimeout(now, escrowSweepBatch)
+		if err != nil {
+			slog.Error("service.escrow.timeout_scan_failed", "err", err)
+			return total, err
+		}
+		settled := 0
+		for _, id := range ids {
+			e, err := database.EscrowRepo().Get(id)
+			if err != nil {
+				continue
+			}
+			if e, err = database.EscrowRepo().Settle(id, e.TimeoutOutcome, models.EscrowResolutionTimeout, nil, now); err != nil {
+				// bu arada taraflarca kapatılmış ya da ihtilafa düşmüş olabilir
+				slog.Warn("service.escrow.timeout_failed", "id", id, "err", err)
+				continue
+			}
+			settled++
+			logEscrowSettlement(e)
+		}
+		total += settled
+		if len(ids) < escrowSweepBatch || settled == 0 {
+			break
+		}
+	}
+	if total > 0 {
+		slog.Info("service.escrow.timed_out", "count", total)
+	}
+	return total, nil
+}
+
+// logEscrowSettlement: kapanan escrow'un sonucunu audit'e yazar
+func logEscrowSettlement(e *models.Escrow) {
+	if e.Open() {
+		return
+	}
+	_ = LogAction("escrow", e.ID, e.Status, fmt.Sprintf("%s %s %s by %s (transaction %d)", e.Status, e.Amount, e.Currency, e.Resolution, *e.SettleTransactionID))
+	slog.Info("service.escrow.settled", "id", e.ID, "status", e.Status, "resolution", e.Resolution)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+// PrepareExport: cüzdanın [from, to) aralığı için dışa aktarma başlığını hazırlar; açılış ve kapanış bakiyeleri
+// checkpoint'lerden hesaplanır, böylece satırlar akmaya başlamadan önce bilinir. Gelecekteki bitiş şimdiye çekilir.
+func PrepareExport(userID int, currency string, from, to time.Time) (*models.ExportHeader, error) {
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+	if _, err := database.BalanceRepo().GetBalance(userID, cur.Code); err != nil {
+		return nil, errors.New("balance not found")
+	}
+	opening, err := database.CheckpointRepo().BalanceAt(userID, cur.Code, from.Add(-time.Microsecond))
+	if err != nil {
+		slog.Error("service.export.opening_failed", "user_id", userID, "currency", cur.Code, "err", err)
+		return nil, err
+	}
+	closing, err := database.CheckpointRepo().BalanceAt(userID, cur.Code, to.Add(-time.Microsecond))
+	if err != nil {
+		slog.Error("service.export.closing_failed", "user_id", userID, "currency", cur.Code, "err", err)
+		return nil, err
+	}
+	cfg := config.GetExport()
+	return &models.ExportHeader{
+		UserID: userID, Currency: cur, AccountID: models.WalletAccountID(userID, cur.Code),
+		BankID: cfg.BankID, BankName: cfg.BankName,
+		From: from, To: to, Opening: opening, Closing: closing, GeneratedAt: now,
+	}, nil
+}
+
+// StreamExport: hareketleri ledger'dan satır satır okuyup yürüyen bakiyeyle yazıcıya aktarır; actorID denetim kaydı içindir
+func StreamExport(actorID int, h *models.ExportHeader, w models.ExportWriter, format string) error {
+	slog.Info("service.export.start", "user_id", h.UserID, "currency", h.Currency.Code, "from", h.From, "to", h.To, "format", format)
+	if err := w.Begin(*h); err != nil {
+		return err
+	}
+	balance, count := h.Opening, 0
+	err := database.StatementRepo().StreamLines(h.UserID, h.Currency.Code, h.From, h.To, func(l models.ExportLine) error {
+		balance += l.Amount
+		l.Balance = balance
+		count++
+		return w.Line(l)
+	})
+	if err != nil {
+		slog.Error("service.export.stream_failed", "user_id", h.UserID, "currency", h.Currency.Code, "lines", count, "err", err)
+		return err
+	}
+	if balance != h.Closing {
+		// başlık yazıldıktan sonra commit edilen geç kayıtlar; kapanış checkpoint değeri esas alınır
+		slog.Error("service.export.closing_mismatch", "user_id", h.UserID, "currency", h.Currency.Code, "running", balance, "closing", h.Closing)
+	}
+	if err := w.End(); err != nil {
+		return err
+	}
+	_ = LogAction("balance", h.UserID, "export", fmt.Sprintf("%s export of %s %s..%s (%d lines) by user %d",
+		format, h.Currency.Code, h.From.UTC().Format(time.RFC3339), h.To.UTC().Format(time.RFC3339), count, actorID))
+	slog.Info("service.export.done", "user_id", h.UserID, "currency", h.Currency.Code, "lines", count)
+	return nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QuoteFee: işlem gönderilmeden önce uygulanacak ücreti hesaplar.
+// Uyan tarife yoksa ücret sıfırdır (ScheduleID boş).
+func QuoteFee(userID int, txType string, amount models.Money, currency string) (*models.FeeQuote, error) {
+	if txType != "debit" && txType != "transfer" {
+		return nil, errors.New("type must be debit or transfer")
+	}
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be positive")
+	}
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.CheckPrecision(amount); err != nil {
+		return nil, err
+	}
+	user, err := database.UserRepo().GetUserByID(userID)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	quote := &models.FeeQuote{TxType: txType, Currency: cur.Code, Amount: amount, Total: amount}
+	schedules, err := database.FeeRepo().FindActive(txType, cur.Code, time.Now())
+	if err != nil {
+		slog.Error("service.fees.find_failed", "user_id", userID, "err", err)
+		return nil, err
+	}
+	if s := pickFeeSchedule(schedules, user.Role, amount, time.Now()); s != nil {
+		id := s.ID
+		quote.Fee = s.Compute(amount, cur)
+		quote.Total = amount + quote.Fee
+		quote.ScheduleID, quote.ScheduleName = &id, s.Name
+	}
+	return quote, nil
+}
+
+// pickFeeSchedule: uyan tarifeler arasından role özel, sonra yüksek öncelikli, sonra en yeni olanı seçer
+func pickFeeSchedule(items []models.FeeSchedule, role string, amount models.Money, at time.Time) *models.FeeSchedule {
+	var matched []models.FeeSchedule
+	for _, s := range items {
+		if s.Matches(role, amount, at) {
+			matched = append(matched, s)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if (a.Role != nil) != (b.Role != nil) {
+			return a.Role != nil
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.EffectiveFrom.After(b.EffectiveFrom)
+	})
+	return &matched[0]
+}
+
+// CreateFeeSchedule: yeni ücret tarifesi (admin); EffectiveFrom boşsa hemen geçerli olur
+func CreateFeeSchedule(f *models.FeeSchedule, actorID int) error {
+	normalizeFeeSchedule(f)
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	f.ID, f.UpdatedBy = 0, actorID
+	if err := database.FeeRepo().CreateSchedule(f); err != nil {
+		slog.Error("service.fees.create_failed", "err", err)
+		return err
+	}
+	_ = LogAction("fee_schedule", f.ID, "create", fmt.Sprintf("%s (%s %s %s) by user %d", f.Name, f.TxType, f.Currency, f.Kind, actorID))
+	return nil
+}
+
+// UpdateFeeSchedule: tarifeyi günceller (admin). Geçmiş ücretler işlem anındaki tutarla yazıldığından etkilenmez.
+func UpdateFeeSchedule(f *models.FeeSchedule, actorID int) error {
+	existing, err := database.FeeRepo().GetSchedule(f.ID)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return errors.New("fee schedule not found")
+		}
+		return err
+	}
+	normalizeFeeSchedule(f)
+	if f.EffectiveFrom.IsZero() {
+		f.EffectiveFrom = existing.EffectiveFrom
+	}
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	f.CreatedAt, f.UpdatedBy = existing.CreatedAt, actorID
+	if err := database.FeeRepo().UpdateSchedule(f); err != nil {
+		slog.Error("service.fees.update_failed", "id", f.ID, "err", err)
+		return err
+	}
+	_ = LogAction("fee_schedule", f.ID, "update", fmt.Sprintf("%s (%s %s %s) by user %d", f.Name, f.TxType, f.Currency, f.Kind, actorID))
+	return nil
+}
+
+// DeleteFeeSchedule: tarifeyi siler (admin); tarifeyi ileri bir tarihte bitirmek için effective_to güncellenmelidir
+func DeleteFeeSchedule(id, actorID int) error {
+	if err := database.FeeRepo().DeleteSchedule(id); err != nil {
+		if database.IsNotFound(err) {
+			return errors.New("fee schedule not found")
+		}
+		return err
+	}
+	_ = LogAction("fee_schedule", id, "delete", fmt.Sprintf("Deleted by user %d", actorID))
+	return nil
+}
+
+func ListFeeSchedules(txType, currency string, at *time.Time) ([]models.FeeSchedule, error) {
+	return database.FeeRepo().ListSchedules(txType, strings.ToUpper(currency), at)
+}
+
+func GetFeeSchedule(id int) (*models.FeeSchedule, error) {
+	f, err := database.FeeRepo().GetSchedule(id)
+	if err != nil && database.IsNotFound(err) {
+		return nil, errors.New("fee schedule not found")
+	}
+	return f, err
+}
+
+func normalizeFeeSchedule(f *models.FeeSchedule) {
+	f.TxType = strings.ToLower(strings.TrimSpace(f.TxType))
+	f.Kind = strings.ToLower(strings.TrimSpace(f.Kind))
+	if f.Role != nil {
+		role := strings.ToLower(strings.TrimSpace(*f.Role))
+		f.Role = &role
+	}
+}
+
+// feeAuditSuffix: audit detayına eklenen ücret bilgisi
+func feeAuditSuffix(fee *models.FeeQuote) string {
+	if fee == nil || !fee.Fee.IsPositive() || fee.ScheduleID == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (fee %s %s, schedule %d)", fee.Fee, fee.Currency, *fee.ScheduleID)
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrFXRateNotFound = errors.New("no fx rate available")
+
+// QuoteFX: verilen andaki kur tablosundan (gerekirse ters kurdan) çevrim teklifi hesaplar.
+// Spread kur kaydında yoksa FX_SPREAD_BPS kullanılır.
+func QuoteFX(fromCurrency, toCurrency string, amount models.Money, at time.Time) (*models.FXQuote, error) {
+	fromCode, err := resolveAmount(fromCurrency, amount)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolveCurrency(toCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if fromCode == to.Code {
+		return nil, errors.New("currencies must differ")
+	}
+
+	inverted := false
+	rate, err := database.FXRateRepo().GetRateAt(fromCode, to.Code, at)
+	if database.IsNotFound(err) {
+		rate, err = database.FXRateRepo().GetRateAt(to.Code, fromCode, at)
+		inverted = true
+	}
+	if err != nil {
+		if database.IsNotFound(err) {
+			slog.Warn("service.fx.quote.rate_not_found", "from", fromCode, "to", to.Code, "at", at)
+			return nil, ErrFXRateNotFound
+		}
+		slog.Error("service.fx.quote.lookup_failed", "from", fromCode, "to", to.Code, "err", err)
+		return nil, err
+	}
+
+	mid := rate.Rate
+	if inverted {
+		mid = mid.Invert()
+	}
+	spread := config.GetFX().SpreadBps
+	if rate.SpreadBps != nil {
+		spread = *rate.SpreadBps
+	}
+	applied := mid.ApplySpread(spread)
+	counter := applied.Convert(amount, to)
+	if !counter.IsPositive() {
+		return nil, errors.New("amount too small to convert")
+	}
+	return &models.FXQuote{
+		FromCurrency:  fromCode,
+		ToCurrency:    to.Code,
+		Amount:        amount,
+		CounterAmount: counter,
+		MidRate:       mid,
+		Rate:          applied,
+		SpreadBps:     spread,
+		RateID:        rate.ID,
+		Inverted:      inverted,
+		At:            at,
+	}, nil
+}
+
+// TransferFX: çapraz kur transferi; gönderenden fromCurrency düşer, alıcıya toCurrency cinsinden karşı tutar geçer
+func TransferFX(fromUserID, toUserID int, amount models.Money, fromCurrency, toCurrency string) (fromNew models.Money, toNew models.Money, quote *models.FXQuote, err error) {
+	fromNew, toNew, quote, _, err = TransferFXWith(fromUserID, toUserID, amount, fromCurrency, toCurrency, models.TxOptions{})
+	return fromNew, toNew, quote, err
+}
+
+// TransferFXWith: TransferFX'in seçenekli hali (ör. memo/etiket metadata'sı); limit ve ücret verilmemişse çözülür, oluşan kaydı da döner
+func TransferFXWith(fromUserID, toUserID int, amount models.Money, fromCurrency, toCurrency string, opts models.TxOptions) (fromNew models.Money, toNew models.Money, quote *models.FXQuote, tx *models.Transaction, err error) {
+	if err = normalizeMeta(opts.Meta); err != nil {
+		return 0, 0, nil, nil, err
+	}
+	slog.Info("service.transfer_fx.start", "from_user_id", fromUserID, "to_user_id", toUserID, "amount", amount, "from_currency", fromCurrency, "to_currency", toCurrency)
+	quote, err = QuoteFX(fromCurrency, toCurrency, amount, time.Now())
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	if opts.Limit == nil {
+		if opts.Limit, err = ResolveLimits(fromUserID, quote.FromCurrency); err != nil {
+			return 0, 0, nil, nil, err
+		}
+	}
+	if opts.Fee == nil {
+		// ücret gönderenin para biriminde, aynı para birimli transferle aynı tarifeden alınır
+		if opts.Fee, err = QuoteFee(fromUserID, "transfer", amount, quote.FromCurrency); err != nil {
+			return 0, 0, nil, nil, err
+		}
+	}
+	fromNew, toNew, tx, err = database.TransactionRepo().TransferFXAtomic(fromUserID, toUserID, *quote, opts)
+	if err != nil {
+		if auditLimitViolation(fromUserID, "transfer_fx", amount, err) {
+			return 0, 0, nil, nil, err
+		}
+		switch err.Error() {
+		case "insufficient funds":
+			slog.Warn("service.transfer_fx.insufficient_funds", "from_user_id", fromUserID, "amount", amount)
+		case "sender balance not found":
+			slog.Error("service.transfer_fx.sender_balance_not_found", "from_user_id", fromUserID, "err", err)
+		case "recipient balance not found":
+			slog.Error("service.transfer_fx.recipient_balance_not_found", "to_user_id", toUserID, "err", err)
+		case database.ErrDuplicateExternalRef.Error():
+			slog.Warn("service.transfer_fx.duplicate_external_ref", "from_user_id", fromUserID)
+		default:
+			slog.Error("service.transfer_fx.failed", "from_user_id", fromUserID, "to_user_id", toUserID, "err", err)
+		}
+		return 0, 0, nil, nil, err
+	}
+	_ = LogAction("transaction", tx.ID, "transfer_fx", fmt.Sprintf("Transferred %s %s from user %d to user %d as %s %s at rate %s (rate_id=%d)%s",
+		quote.Amount, quote.FromCurrency, fromUserID, toUserID, quote.CounterAmount, quote.ToCurrency, quote.Rate, quote.RateID, feeAuditSuffix(opts.Fee)))
+	slog.Info("service.transfer_fx.success", "from_user_id", fromUserID, "to_user_id", toUserID, "from_new", fromNew, "to_new", toNew, "rate", quote.Rate)
+	return fromNew, toNew, quote, tx, nil
+}
+
+// IsCrossCurrency: hedef para birimi verilmiş ve kaynak (ya da varsayılan) para biriminden farklı mı
+func IsCrossCurrency(from, to string) bool {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return false
+	}
+	if from = strings.TrimSpace(from); from == "" {
+		from = config.DefaultCurrency()
+	}
+	return !strings.EqualFold(from, to)
+}
+
+// CreateFXRate: tek kur kaydı ekler (ValidFrom boşsa şimdi)
+func CreateFXRate(rate *models.FXRate) error {
+	if rate.ValidFrom.IsZero() {
+		rate.ValidFrom = time.Now()
+	}
+	if err := rate.Validate(); err != nil {
+		return err
+	}
+	slog.Info("service.fx.rate.create", "base", rate.Base, "quote", rate.Quote, "rate", rate.Rate, "valid_from", rate.ValidFrom)
+	if err := database.FXRateRepo().CreateRate(rate); err != nil {
+		slog.Error("service.fx.rate.create_failed", "err", err)
+		return err
+	}
+	_ = LogAction("fx_rate", rate.ID, "create", fmt.Sprintf("%s/%s=%s by user %d", rate.Base, rate.Quote, rate.Rate, rate.CreatedBy))
+	return nil
+}
+
+// ImportFXRatesCSV: başlık satırlı CSV'den toplu kur yükler.
+// Zorunlu sütunlar: base, quote, rate; opsiyonel: spread_bps, valid_from, valid_to (RFC3339).
+// Satırlardan biri hatalıysa hiçbiri yazılmaz.
+func ImportFXRatesCSV(r io.Reader, createdBy int) (int, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return 0, errors.New("csv header required")
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"base", "quote", "rate"} {
+		if _, ok := col[req]; !ok {
+			return 0, fmt.Errorf("csv column %q required", req)
+		}
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	now := time.Now()
+	var rates []models.FXRate
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %v", line, err)
+		}
+		rate := models.FXRate{Base: field(rec, "base"), Quote: field(rec, "quote"), CreatedBy: createdBy, ValidFrom: now}
+		if rate.Rate, err = models.ParseRate(field(rec, "rate")); err != nil {
+			return 0, fmt.Errorf("line %d: %v", line, err)
+		}
+		if v := field(rec, "spread_bps"); v != "" {
+			bps, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid spread_bps", line)
+			}
+			rate.SpreadBps = &bps
+		}
+		if v := field(rec, "valid_from"); v != "" {
+			if rate.ValidFrom, err = time.Parse(time.RFC3339, v); err != nil {
+				return 0, fmt.Errorf("line %d: invalid valid_from", line)
+			}
+		}
+		if v := field(rec, "valid_to"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid valid_to", line)
+			}
+			rate.ValidTo = &t
+		}
+		if err := rate.Validate(); err != nil {
+			return 0, fmt.Errorf("line %d: %v", line, err)
+		}
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return 0, errors.New("csv contains no rates")
+	}
+	slog.Info("service.fx.rate.bulk_import", "count", len(rates), "created_by", createdBy)
+	if err := database.FXRateRepo().CreateRates(rates); err != nil {
+		slog.Error("service.fx.rate.bulk_import_failed", "err", err)
+		return 0, err
+	}
+	_ = LogAction("fx_rate", 0, "bulk_import", fmt.Sprintf("%d rates imported by user %d", len(rates), createdBy))
+	return len(rates), nil
+}
+
+// ListFXRates: kur geçmişi (en yeni önce); base/quote boşsa filtre uygulanmaz
+func ListFXRates(base, quote string, limit int) ([]models.FXRate, error) {
+	slog.Info("service.fx.rate.list", "base", base, "quote", quote)
+	return database.FXRateRepo().ListRates(strings.ToUpper(base), strings.ToUpper(quote), limit)
+}
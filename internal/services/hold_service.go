@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+// ErrHoldForbidden: hold işlemi yalnızca ilgili taraflarca yapılabilir
+var ErrHoldForbidden = errors.New("not allowed to operate on this hold")
+
+// holdSweepBatch: sweeper'ın tek turda kapattığı en fazla hold sayısı
+const holdSweepBatch = 500
+
+// AuthorizeHold: kullanıcının cüzdanında tutarı rezerve eder. toUserID sıfırdan farklıysa capture o kullanıcıya transfer olur.
+// ttl sıfırsa HOLD_TTL kullanılır.
+func AuthorizeHold(userID, toUserID int, amount models.Money, currency string, ttl time.Duration, reference string) (*models.Hold, error) {
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.GetHolds()
+	if ttl <= 0 {
+		ttl = cfg.TTL
+	}
+	if ttl > cfg.MaxTTL {
+		return nil, fmt.Errorf("hold ttl exceeds maximum of %s", cfg.MaxTTL)
+	}
+	if toUserID == userID {
+		return nil, errors.New("invalid recipient")
+	}
+	hold := &models.Hold{UserID: userID, Currency: currency, Amount: amount, Reference: reference, ExpiresAt: time.Now().Add(ttl)}
+	if toUserID != 0 {
+		hold.ToUserID = &toUserID
+	}
+	lim, err := ResolveLimits(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("service.hold.authorize.start", "user_id", userID, "to_user_id", toUserID, "amount", amount, "currency", currency)
+	if err := database.HoldRepo().CreateHold(hold, lim); err != nil {
+		if auditLimitViolation(userID, "hold.authorize", amount, err) {
+			return nil, err
+		}
+		if err.Error() == "insufficient funds" {
+			slog.Warn("service.hold.authorize.insufficient_funds", "user_id", userID, "amount", amount)
+		} else {
+			slog.Error("service.hold.authorize.failed", "user_id", userID, "err", err)
+		}
+		return nil, err
+	}
+	_ = LogAction("hold", hold.ID, "authorize", fmt.Sprintf("Held %s %s for user %d until %s", amount, currency, userID, hold.ExpiresAt.Format(time.RFC3339)))
+	slog.Info("service.hold.authorize.success", "hold_id", hold.ID, "user_id", userID)
+	return hold, nil
+}
+
+// CaptureHold: hold'u (tamamen ya da kısmen) gerçek debit/transfer'e çevirir.
+// Alıcılı hold'ları yalnızca alıcı, alıcısız hold'ları yalnızca sahibi capture edebilir.
+func CaptureHold(actorID, holdID int, amount models.Money) (*models.Hold, *models.Transaction, error) {
+	hold, err := getHoldFor(actorID, holdID)
+	if err != nil {
+		return nil, nil, err
+	}
+	capturer := hold.UserID
+	if hold.ToUserID != nil {
+		capturer = *hold.ToUserID
+	}
+	if actorID != capturer {
+		return nil, nil, ErrHoldForbidden
+	}
+	if !amount.IsZero() {
+		if _, err := resolveAmount(hold.Currency, amount); err != nil {
+			return nil, nil, err
+		}
+	}
+	// limitler hold sahibinin (payer) limitleridir; capture'ı alıcı yapsa da para payer'dan çıkar
+	lim, err := ResolveLimits(hold.UserID, hold.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	payer, captured := hold.UserID, amount
+	if captured.IsZero() {
+		captured = hold.Amount
+	}
+	// ücret de payer'dan, capture'ın türüne (alıcılıysa transfer) göre alınır
+	txType := "debit"
+	if hold.ToUserID != nil {
+		txType = "transfer"
+	}
+	fee, err := QuoteFee(payer, txType, captured, hold.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+	slog.Info("service.hold.capture.start", "hold_id", holdID, "amount", amount, "actor_id", actorID)
+	hold, tx, err := database.HoldRepo().CaptureHold(holdID, amount, lim, fee)
+	if err != nil {
+		if auditLimitViolation(payer, "hold.capture", captured, err) {
+			return nil, nil, err
+		}
+		slog.Warn("service.hold.capture.failed", "hold_id", holdID, "err", err)
+		return nil, nil, err
+	}
+	_ = LogAction("hold", hold.ID, "capture", fmt.Sprintf("Captured %s of %s %s (transaction %d)", hold.CapturedAmount, hold.Amount, hold.Currency, tx.ID))
+	_ = LogAction("transaction", tx.ID, tx.Type, fmt.Sprintf("Captured from hold %d: %s %s%s", hold.ID, tx.Amount, tx.Currency, feeAuditSuffix(fee)))
+	slog.Info("service.hold.capture.success", "hold_id", hold.ID, "transaction_id", tx.ID)
+	return hold, tx, nil
+}
+
+// VoidHold: aktif hold'u iptal eder; hold'un her iki tarafı da iptal edebilir
+func VoidHold(actorID, holdID int) (*models.Hold, error) {
+	if _, err := getHoldFor(actorID, holdID); err != nil {
+		return nil, err
+	}
+	hold, err := database.HoldRepo().VoidHold(holdID)
+	if err != nil {
+		slog.Warn("service.hold.void.failed", "hold_id", holdID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("hold", hold.ID, "void", fmt.Sprintf("Voided by user %d, released %s %s", actorID, hold.Amount, hold.Currency))
+	slog.Info("service.hold.void.success", "hold_id", hold.ID)
+	return hold, nil
+}
+
+// ListHolds: kullanıcının taraf olduğu hold'lar
+func ListHolds(userID int, status string) ([]models.Hold, error) {
+	slog.Info("service.hold.list", "user_id", userID, "status", status)
+	return database.HoldRepo().ListHoldsByUser(userID, status)
+}
+
+// ExpireHolds: süresi dolmuş hold'ları serbest bırakır (sweeper job)
+func ExpireHolds() (int, error) {
+	total := 0
+	for {
+		expired, err := database.HoldRepo().ExpireHolds(time.Now(), holdSweepBatch)
+		if err != nil {
+			slog.Error("service.hold.expire_failed", "err", err)
+			return total, err
+		}
+		for _, h := range expired {
+			_ = LogAction("hold", h.ID, "expire", fmt.Sprintf("Expired, released %s %s", h.Amount, h.Currency))
+		}
+		total += len(expired)
+		if len(expired) < holdSweepBatch {
+			break
+		}
+	}
+	if total > 0 {
+		slog.Info("service.hold.expired", "count", total)
+	}
+	return total, nil
+}
+
+func getHoldFor(actorID, holdID int) (*models.Hold, error) {
+	hold, err := database.HoldRepo().GetHold(holdID)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.New("hold not found")
+		}
+		return nil, err
+	}
+	if hold.UserID != actorID && (hold.ToUserID == nil || *hold.ToUserID != actorID) {
+		// başkasının hold'unun varlığı sızdırılmaz
+		return nil, errors.New("hold not found")
+	}
+	return hold, nil
+}
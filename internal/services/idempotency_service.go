@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+var (
+	// ErrIdempotencyMismatch: aynı anahtar farklı bir istek gövdesiyle tekrar kullanıldı (422)
+	ErrIdempotencyMismatch = errors.New("idempotency key reused with a different request")
+	// ErrIdempotencyInProgress: aynı anahtarlı orijinal istek hâlâ işleniyor (409)
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// BeginIdempotent: anahtarı ayırır. Daha önce tamamlanmış aynı istek varsa saklanan kaydı replay=true ile döner.
+func BeginIdempotent(userID int, key, method, path string, body []byte) (rec *models.IdempotencyKey, replay bool, err error) {
+	fp := requestFingerprint(method, path, body)
+	now := time.Now()
+	existing, created, err := database.IdempotencyRepo().Reserve(&models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		Fingerprint: fp,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(config.GetIdempotency().TTL),
+	})
+	if err != nil {
+		slog.Error("service.idempotency.reserve_failed", "user_id", userID, "key", key, "err", err)
+		return nil, false, err
+	}
+	if created {
+		return existing, false, nil
+	}
+	if existing.Fingerprint != fp {
+		slog.Warn("service.idempotency.mismatch", "user_id", userID, "key", key, "path", path)
+		return nil, false, ErrIdempotencyMismatch
+	}
+	if !existing.Completed() {
+		return nil, false, ErrIdempotencyInProgress
+	}
+	slog.Info("service.idempotency.replay", "user_id", userID, "key", key, "path", path, "status", existing.StatusCode)
+	return existing, true, nil
+}
+
+// CompleteIdempotent: orijinal yanıtı saklar; sonraki tekrarlar bu yanıtı alır
+func CompleteIdempotent(userID int, key string, statusCode int, contentType string, body []byte) error {
+	if err := database.IdempotencyRepo().Complete(userID, key, statusCode, contentType, string(body)); err != nil {
+		slog.Error("service.idempotency.complete_failed", "user_id", userID, "key", key, "err", err)
+		return err
+	}
+	return nil
+}
+
+// ReleaseIdempotent: tamamlanamayan (5xx/panic) isteğin anahtarını bırakır; istemci aynı anahtarla yeniden deneyebilir
+func ReleaseIdempotent(userID int, key string) error {
+	return database.IdempotencyRepo().Release(userID, key)
+}
+
+// PurgeExpiredIdempotencyKeys: TTL'i dolmuş anahtarları siler
+func PurgeExpiredIdempotencyKeys() (int64, error) {
+	n, err := database.IdempotencyRepo().DeleteExpired(time.Now())
+	if err != nil {
+		slog.Error("service.idempotency.purge_failed", "err", err)
+		return 0, err
+	}
+	if n > 0 {
+		slog.Info("service.idempotency.purged", "count", n)
+	}
+	return n, nil
+}
+
+// requestFingerprint: method + path + gövde özeti; JSON gövdelerde boşluk farkları yok sayılır
+func requestFingerprint(method, path string, body []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body); err == nil {
+		body = compact.Bytes()
+	}
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CreateInterestRate: yeni mevduat faizi tarifesi (admin)
+func CreateInterestRate(r *models.InterestRate, actorID int) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	r.ID, r.UpdatedBy = 0, actorID
+	if err := database.InterestRepo().CreateRate(r); err != nil {
+		slog.Error("service.interest.rate_create_failed", "err", err)
+		return err
+	}
+	_ = LogAction("interest_rate", r.ID, "create", interestRateAudit(r, actorID))
+	return nil
+}
+
+// UpdateInterestRate: tarifeyi günceller (admin). Yazılmış tahakkuklar yazıldıkları oranla kalır.
+func UpdateInterestRate(r *models.InterestRate, actorID int) error {
+	existing, err := database.InterestRepo().GetRate(r.ID)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return errors.New("interest rate not found")
+		}
+		return err
+	}
+	if r.EffectiveFrom.IsZero() {
+		r.EffectiveFrom = existing.EffectiveFrom
+	}
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	r.CreatedAt, r.UpdatedBy = existing.CreatedAt, actorID
+	if err := database.InterestRepo().UpdateRate(r); err != nil {
+		slog.Error("service.interest.rate_update_failed", "id", r.ID, "err", err)
+		return err
+	}
+	_ = LogAction("interest_rate", r.ID, "update", interestRateAudit(r, actorID))
+	return nil
+}
+
+// DeleteInterestRate: tarifeyi siler (admin); oranı ileri bir tarihte bitirmek için effective_to güncellenmelidir
+func DeleteInterestRate(id, actorID int) error {
+	if err := database.InterestRepo().DeleteRate(id); err != nil {
+		if database.IsNotFound(err) {
+			return errors.New("interest rate not found")
+		}
+		return err
+	}
+	_ = LogAction("interest_rate", id, "delete", fmt.Sprintf("Deleted by user %d", actorID))
+	return nil
+}
+
+func ListInterestRates(currency string, at *time.Time) ([]models.InterestRate, error) {
+	return database.InterestRepo().ListRates(strings.ToUpper(currency), at)
+}
+
+func GetInterestRate(id int) (*models.InterestRate, error) {
+	r, err := database.InterestRepo().GetRate(id)
+	if err != nil && database.IsNotFound(err) {
+		return nil, errors.New("interest rate not found")
+	}
+	return r, err
+}
+
+func interestRateAudit(r *models.InterestRate, actorID int) string {
+	return fmt.Sprintf("%s %d bps %s from balance %s by user %d", r.Currency, r.RateBps, r.DayCount, r.MinBalance, actorID)
+}
+
+// AccrueInterest: kapanmış (gün sonu + INTEREST_LAG geçmiş) günler için pozitif bakiyelere faiz tahakkuk ettirir,
+// ardından kapanmış ayların tahakkuklarını öder. Son tahakkuk günü (yarım kalmış olabilir) ve sonrası, en fazla
+// INTEREST_CATCHUP_DAYS geriye kadar telafi edilir; tahakkuk (user, currency, gün) başına tek olduğundan tekrar çalıştırmalar çift faiz yazmaz.
+func AccrueInterest(now time.Time) (int, error) {
+	cfg := config.GetInterest()
+	cutoff := utcDay(now.Add(-cfg.Lag))
+	to := cutoff.AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -(cfg.CatchUpDays - 1))
+	last, err := database.InterestRepo().LastAccrualDate()
+	if err != nil {
+		slog.Error("service.interest.last_accrual_failed", "err", err)
+		return 0, err
+	}
+	if last != nil && !utcDay(*last).Before(from) {
+		from = utcDay(*last)
+	}
+
+	count := 0
+	if !from.After(to) {
+		accruals, err := computeAccruals(0, "", from, to)
+		if err != nil {
+			slog.Error("service.interest.compute_failed", "err", err)
+			return 0, err
+		}
+		for i := range accruals {
+			ok, err := database.InterestRepo().InsertAccrual(&accruals[i])
+			if err != nil {
+				slog.Error("service.interest.accrue_failed", "user_id", accruals[i].UserID, "currency", accruals[i].Currency, "err", err)
+				continue
+			}
+			if ok {
+				count++
+			}
+		}
+		if count > 0 {
+			slog.Info("service.interest.accrued", "from", from.Format("2006-01-02"), "to", to.Format("2006-01-02"), "count", count)
+		}
+	}
+
+	if _, err := PostInterest(cutoff); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// PostInterest: at'in ayından önceki aylara ait ödenmemiş tahakkukları cüzdan başına tek interest işlemi olarak öder
+func PostInterest(at time.Time) (int, error) {
+	monthStart := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periods, err := database.InterestRepo().UnpostedPeriods(monthStart)
+	if err != nil {
+		slog.Error("service.interest.periods_failed", "err", err)
+		return 0, err
+	}
+	posted := 0
+	for _, p := range periods {
+		rec, amount, err := database.InterestRepo().PostInterest(p)
+		if err != nil {
+			slog.Error("service.interest.post_failed", "user_id", p.UserID, "currency", p.Currency, "month", p.Month.Format("2006-01"), "err", err)
+			continue
+		}
+		if rec == nil {
+			continue
+		}
+		posted++
+		_ = LogAction("transaction", rec.ID, models.TxTypeInterest, fmt.Sprintf("Interest %s %s for %s paid to user %d", amount, p.Currency, p.Month.Format("2006-01"), p.UserID))
+	}
+	if posted > 0 {
+		slog.Info("service.interest.posted", "count", posted)
+	}
+	return posted, nil
+}
+
+// PreviewInterest: from..to (dahil) için tahakkukları yazmadan hesaplar (admin). userID/currency boşsa tüm cüzdanlar.
+func PreviewInterest(from, to time.Time, userID int, currency string) (*models.InterestPreview, error) {
+	from, to = utcDay(from), utcDay(to)
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+	if max := config.GetInterest().MaxRangeDays; int(to.Sub(from).Hours()/24) >= max {
+		return nil, fmt.Errorf("range must not exceed %d days", max)
+	}
+	accruals, err := computeAccruals(userID, strings.ToUpper(currency), from, to)
+	if err != nil {
+		slog.Error("service.interest.preview_failed", "err", err)
+		return nil, err
+	}
+	totals := make(map[string]models.Money)
+	for _, a := range accruals {
+		totals[a.Currency] += a.Interest
+	}
+	p := &models.InterestPreview{From: from, To: to, Accruals: accruals, Totals: []models.CurrencyTotal{}}
+	if p.Accruals == nil {
+		p.Accruals = []models.InterestAccrual{}
+	}
+	for cur, total := range totals {
+		p.Totals = append(p.Totals, models.CurrencyTotal{Currency: cur, Total: total})
+	}
+	sort.Slice(p.Totals, func(i, j int) bool { return p.Totals[i].Currency < p.Totals[j].Currency })
+	return p, nil
+}
+
+// computeAccruals: tarifesi olan para birimlerindeki cüzdanların from..to günleri için sıfırdan büyük tahakkukları
+func computeAccruals(userID int, currency string, from, to time.Time) ([]models.InterestAccrual, error) {
+	rates, err := database.InterestRepo().ListRates(currency, nil)
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := make(map[string][]models.InterestRate)
+	for _, r := range rates {
+		byCurrency[r.Currency] = append(byCurrency[r.Currency], r)
+	}
+	if len(byCurrency) == 0 {
+		return nil, nil
+	}
+	wallets, err := database.InterestRepo().ListWallets(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+	var out []models.InterestAccrual
+	for _, w := range wallets {
+		curRates := byCurrency[w.Currency]
+		if len(curRates) == 0 {
+			continue
+		}
+		days, err := database.InterestRepo().ClosingBalances(w.UserID, w.Currency, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range days {
+			r := models.PickInterestRate(curRates, d.Day, d.Balance)
+			if r == nil {
+				continue
+			}
+			interest := models.DailyInterest(d.Balance, r.RateBps, r.DayCount, d.Day)
+			if !interest.IsPositive() {
+				continue
+			}
+			rateID := r.ID
+			out = append(out, models.InterestAccrual{
+				UserID: w.UserID, Currency: w.Currency, AccrualDate: d.Day, Balance: d.Balance,
+				RateID: &rateID, RateBps: r.RateBps, DayCount: r.DayCount, Interest: interest,
+			})
+		}
+	}
+	return out, nil
+}
+
+// utcDay: t'nin UTC gün başı
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
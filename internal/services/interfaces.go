@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"insider-go-backend/internal/models"
+	"io"
 	"time"
 )
 
@@ -14,7 +16,7 @@ type UserService interface {
 	RefreshAccessToken(refreshToken string) (string, error)
 	ParseJWT(tokenStr string) (userID int, role string, err error)
 	CheckUserRole(user *models.User, role string) bool
-	CreateBalanceForUser(userID int, initialAmount float64) error
+	CreateBalanceForUser(userID int, currency string, initialAmount models.Money) error
 	ListUsers() ([]*models.User, error)
 	GetUser(id int) (*models.User, error)
 	UpdateUser(id int, username, email, role string) error
@@ -23,20 +25,179 @@ type UserService interface {
 
 // BalanceService arayüzü
 type BalanceService interface {
-	AddOrUpdateBalance(userID int, amount float64) error
-	GetUserBalance(userID int) (float64, error)
-	GetBalance(userID int) (*models.Balance, error)
-	SetBalance(userID int, amount float64) (*models.Balance, error)
-	CalculateBalanceAt(userID int, at time.Time) (float64, error)
+	AddOrUpdateBalance(userID int, currency string, amount models.Money) error
+	GetUserBalance(userID int, currency string) (models.Money, error)
+	GetBalance(userID int, currency string) (*models.Balance, error)
+	GetBalances(userID int) ([]models.Balance, error)
+	OpenWallet(userID int, currency string) (*models.Balance, error)
+	SetBalance(userID int, currency string, amount models.Money) (*models.Balance, error)
+	CalculateBalanceAt(userID int, currency string, at time.Time) (models.Money, error)
+	WriteBalanceCheckpoints(now time.Time, lag time.Duration) (int64, error)
 }
 
 // TransactionService arayüzü
 type TransactionService interface {
-	Credit(userID int, amount float64) (float64, error)
-	Debit(userID int, amount float64) (float64, error)
-	Transfer(fromUserID, toUserID int, amount float64) (fromNew float64, toNew float64, err error)
+	Credit(userID int, amount models.Money, currency string) (models.Money, error)
+	Debit(userID int, amount models.Money, currency string) (models.Money, error)
+	CreditWith(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error)
+	DebitWith(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error)
+	Transfer(fromUserID, toUserID int, amount models.Money, currency string) (fromNew models.Money, toNew models.Money, err error)
+	TransferWith(fromUserID, toUserID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, models.Money, *models.Transaction, error)
+	SplitTransfer(fromUserID int, amount models.Money, currency string, parts []models.SplitPart) (models.Money, *models.Transaction, error)
 	GetTransactionsByUser(userID int) ([]*models.Transaction, error)
 	GetTransactionByID(id int) (*models.Transaction, error)
+	ListTransactionHistory(userID int, f models.TxFilter) (*models.TxPage, error)
+	UpdateTransactionMeta(actorID, txID int, patch models.TxMetaPatch) (*models.Transaction, error)
+	ReverseTransaction(txID int, reason string, actorID int) (*models.Transaction, *models.Transaction, error)
+	RefundTransaction(txID int, amount models.Money, reason string, actorID int) (*models.Transaction, *models.Transaction, error)
+}
+
+// HoldService arayüzü (authorize / capture / void)
+type HoldService interface {
+	AuthorizeHold(userID, toUserID int, amount models.Money, currency string, ttl time.Duration, reference string) (*models.Hold, error)
+	CaptureHold(actorID, holdID int, amount models.Money) (*models.Hold, *models.Transaction, error)
+	VoidHold(actorID, holdID int) (*models.Hold, error)
+	ListHolds(userID int, status string) ([]models.Hold, error)
+	ExpireHolds() (int, error)
+}
+
+// FXService arayüzü
+type FXService interface {
+	QuoteFX(fromCurrency, toCurrency string, amount models.Money, at time.Time) (*models.FXQuote, error)
+	TransferFX(fromUserID, toUserID int, amount models.Money, fromCurrency, toCurrency string) (models.Money, models.Money, *models.FXQuote, error)
+	TransferFXWith(fromUserID, toUserID int, amount models.Money, fromCurrency, toCurrency string, opts models.TxOptions) (models.Money, models.Money, *models.FXQuote, *models.Transaction, error)
+	CreateFXRate(rate *models.FXRate) error
+	ImportFXRatesCSV(r io.Reader, createdBy int) (int, error)
+	ListFXRates(base, quote string, limit int) ([]models.FXRate, error)
+}
+
+// LedgerService arayüzü
+type LedgerService interface {
+	TrialBalance(at time.Time) (*models.TrialBalance, error)
+	GetLedgerEntriesByTransaction(txID int) ([]models.LedgerEntry, error)
+	GetLedgerEntriesByAccount(account string) ([]models.LedgerEntry, error)
+	Reconcile(apply bool, actorID int) (*models.ReconcileReport, error)
+}
+
+// ScheduledTransferService arayüzü (ileri tarihli transferler)
+type ScheduledTransferService interface {
+	CreateScheduledTransfer(userID, toUserID int, amount models.Money, currency string, executeAt time.Time) (*models.ScheduledTransfer, error)
+	ListScheduledTransfers(userID int, status string) ([]models.ScheduledTransfer, error)
+	GetScheduledTransfer(userID, id int) (*models.ScheduledTransfer, []models.ScheduledTransferRun, error)
+	UpdateScheduledTransfer(userID, id, toUserID int, amount models.Money, currency string, executeAt time.Time) (*models.ScheduledTransfer, error)
+	CancelScheduledTransfer(userID, id int) error
+	RunDueScheduledTransfers(ctx context.Context) (int, error)
+}
+
+// StandingOrderService arayüzü (düzenli transfer talimatları)
+type StandingOrderService interface {
+	CreateStandingOrder(o *models.StandingOrder) error
+	ListStandingOrders(userID int, status string) ([]models.StandingOrder, error)
+	GetStandingOrder(userID, id int) (*models.StandingOrder, error)
+	GetStandingOrderRuns(userID, id int) ([]models.StandingOrderRun, error)
+	PauseStandingOrder(userID, id int) error
+	ResumeStandingOrder(userID, id int) error
+	CancelStandingOrder(userID, id int) error
+	RunDueStandingOrders(ctx context.Context) (int, error)
+}
+
+// LimitService arayüzü (giden işlem limitleri)
+type LimitService interface {
+	ResolveLimits(userID int, currency string) (*models.EffectiveLimit, error)
+	UpsertLimitPolicy(p *models.LimitPolicy, actorID int) error
+	DeleteLimitPolicy(id, actorID int) error
+	ListLimitPolicies(role string, userID int) ([]models.LimitPolicy, error)
+}
+
+// OverdraftService arayüzü (kredili hesap)
+type OverdraftService interface {
+	SetCreditLine(actorID, userID int, currency string, limit models.Money, rateBps *int) (*models.Balance, error)
+	AccrueOverdraftInterest(now time.Time) (int, error)
+}
+
+// FeeService arayüzü (işlem ücretleri)
+type FeeService interface {
+	QuoteFee(userID int, txType string, amount models.Money, currency string) (*models.FeeQuote, error)
+	CreateFeeSchedule(f *models.FeeSchedule, actorID int) error
+	UpdateFeeSchedule(f *models.FeeSchedule, actorID int) error
+	DeleteFeeSchedule(id, actorID int) error
+	ListFeeSchedules(txType, currency string, at *time.Time) ([]models.FeeSchedule, error)
+	GetFeeSchedule(id int) (*models.FeeSchedule, error)
+}
+
+// BatchService arayüzü (toplu işlemler)
+type BatchService interface {
+	SubmitBatch(userID int, mode string, items []models.BatchItem) (*models.Batch, error)
+	GetBatch(userID int, isAdmin bool, id int) (*models.Batch, error)
+	ListBatches(userID, limit int) ([]models.Batch, error)
+}
+
+// PaymentRequestService arayüzü (kullanıcılar arası ödeme istekleri)
+type PaymentRequestService interface {
+	CreatePaymentRequest(requesterID, payerID int, amount models.Money, currency, note string, ttl time.Duration) (*models.PaymentRequest, error)
+	ListPaymentRequests(userID int, direction, status string) ([]models.PaymentRequest, error)
+	GetPaymentRequest(userID, id int) (*models.PaymentRequest, error)
+	AcceptPaymentRequest(payerID, id int) (*models.PaymentRequest, error)
+	DeclinePaymentRequest(payerID, id int) (*models.PaymentRequest, error)
+	CancelPaymentRequest(requesterID, id int) (*models.PaymentRequest, error)
+	ExpirePaymentRequests() (int, error)
+}
+
+// EscrowService arayüzü (koşullu ödeme (escrow))
+type EscrowService interface {
+	OpenEscrow(buyerID, sellerID int, amount models.Money, currency, description string, ttl time.Duration, timeoutOutcome string) (*models.Escrow, error)
+	ListEscrows(userID int, side, status string) ([]models.Escrow, error)
+	ListAllEscrows(status string) ([]models.Escrow, error)
+	GetEscrow(userID int, isAdmin bool, id int) (*models.Escrow, error)
+	ConfirmEscrow(userID, id int, outcome string) (*models.Escrow, error)
+	ArbitrateEscrow(adminID, id int, outcome, reason string) (*models.Escrow, error)
+	SettleExpiredEscrows() (int, error)
+}
+
+// InterestService arayüzü (mevduat faizi)
+type InterestService interface {
+	CreateInterestRate(r *models.InterestRate, actorID int) error
+	UpdateInterestRate(r *models.InterestRate, actorID int) error
+	DeleteInterestRate(id, actorID int) error
+	ListInterestRates(currency string, at *time.Time) ([]models.InterestRate, error)
+	GetInterestRate(id int) (*models.InterestRate, error)
+	AccrueInterest(now time.Time) (int, error)
+	PostInterest(at time.Time) (int, error)
+	PreviewInterest(from, to time.Time, userID int, currency string) (*models.InterestPreview, error)
+}
+
+// StatementService arayüzü (dönem ekstreleri)
+type StatementService interface {
+	GenerateStatement(userID int, currency string, month time.Time) (*models.Statement, error)
+	GenerateMonthlyStatements(now time.Time) (int, error)
+	ListStatements(userID int, currency string) ([]models.Statement, error)
+	GetStatement(userID int, isAdmin bool, id int) (*models.Statement, error)
+}
+
+// ExportService arayüzü (hesap hareketlerinin CSV, OFX ve camt.053 olarak akışla dışa aktarılması)
+type ExportService interface {
+	PrepareExport(userID int, currency string, from, to time.Time) (*models.ExportHeader, error)
+	StreamExport(actorID int, h *models.ExportHeader, w models.ExportWriter, format string) error
+}
+
+// BankStatementService arayüzü (banka ekstrelerinin içe aktarılması ve mutabakatı)
+type BankStatementService interface {
+	ImportBankStatement(actorID int, source, filename string, data []byte, mapping *models.BankCSVMapping) (*models.BankStatementReport, error)
+	RematchBankStatement(actorID, id int) (*models.BankStatementReport, error)
+	CreditBankStatementLine(actorID, lineID, userID int) (*models.BankStatementLine, *models.Transaction, error)
+	GetBankStatementReport(id int) (*models.BankStatementReport, error)
+	GetBankStatement(id int, status string) (*models.BankStatement, []models.BankStatementLine, error)
+	ListBankStatements(limit int) ([]models.BankStatement, error)
+}
+
+// PaymentInitiationService arayüzü (pain.001 toplu ödemeler ve pain.002 durum raporu)
+type PaymentInitiationService interface {
+	SubmitPain001(actorID int, isAdmin bool, filename string, data []byte) (*models.PaymentInitiation, error)
+	RecordPaymentInitiationTx(t models.PaymentInitiationTx, tx *models.Transaction, err error)
+	FinishPaymentInitiation(id int) (*models.PaymentInitiation, error)
+	PendingPaymentInitiations() ([]*models.PaymentInitiation, error)
+	GetPaymentInitiation(actorID int, isAdmin bool, id int) (*models.PaymentInitiation, error)
+	ListPaymentInitiations(actorID, limit int) ([]models.PaymentInitiation, error)
 }
 
 // AuditLogService arayüzü
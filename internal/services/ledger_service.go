@@ -0,0 +1,43 @@
+package services
+
+import (
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+// TrialBalance: verilen andaki tüm ledger hesaplarının bakiyesi ve kayıtların toplamı
+func TrialBalance(at time.Time) (*models.TrialBalance, error) {
+	slog.Info("service.ledger.trial_balance.start", "at", at)
+	accounts, err := database.LedgerRepo().AccountBalances(at)
+	if err != nil {
+		slog.Error("service.ledger.trial_balance.failed", "err", err)
+		return nil, err
+	}
+	totals, err := database.LedgerRepo().SumPostingsByCurrency(at)
+	if err != nil {
+		slog.Error("service.ledger.trial_balance.sum_failed", "err", err)
+		return nil, err
+	}
+	balanced := true
+	for _, t := range totals {
+		if !t.Total.IsZero() {
+			balanced = false
+			slog.Error("service.ledger.trial_balance.unbalanced", "at", at, "currency", t.Currency, "total", t.Total)
+		}
+	}
+	return &models.TrialBalance{At: at, Accounts: accounts, Totals: totals, Balanced: balanced}, nil
+}
+
+// GetLedgerEntriesByTransaction: bir transaction'a ait kayıt bacakları
+func GetLedgerEntriesByTransaction(txID int) ([]models.LedgerEntry, error) {
+	slog.Info("service.ledger.entries_by_tx", "transaction_id", txID)
+	return database.LedgerRepo().GetEntriesByTransaction(txID)
+}
+
+// GetLedgerEntriesByAccount: bir hesabın tüm kayıtları (kronolojik)
+func GetLedgerEntriesByAccount(account string) ([]models.LedgerEntry, error) {
+	slog.Info("service.ledger.entries_by_account", "account", account)
+	return database.LedgerRepo().GetEntriesByAccount(account)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+)
+
+// ResolveLimits: kullanıcının para birimindeki geçerli limitleri (rol varsayılanı + kullanıcı override'ı).
+// Hiç politika yoksa nil döner (limitsiz).
+func ResolveLimits(userID int, currency string) (*models.EffectiveLimit, error) {
+	user, err := database.UserRepo().GetUserByID(userID)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	roleDefault, override, err := database.LimitRepo().FindPolicies(user.Role, userID, currency)
+	if err != nil {
+		slog.Error("service.limits.resolve_failed", "user_id", userID, "err", err)
+		return nil, err
+	}
+	if roleDefault == nil && override == nil {
+		return nil, nil
+	}
+	return models.MergeLimits(userID, currency, roleDefault, override), nil
+}
+
+// UpsertLimitPolicy: rol varsayılanı ya da kullanıcı override'ı ekler/günceller (admin)
+func UpsertLimitPolicy(p *models.LimitPolicy, actorID int) error {
+	if p.Role != nil {
+		role := strings.ToLower(strings.TrimSpace(*p.Role))
+		p.Role = &role
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	p.UpdatedBy = actorID
+	if err := database.LimitRepo().UpsertPolicy(p); err != nil {
+		slog.Error("service.limits.upsert_failed", "err", err)
+		return err
+	}
+	_ = LogAction("limit_policy", p.ID, "upsert", fmt.Sprintf("%s %s by user %d", limitScope(p), p.Currency, actorID))
+	return nil
+}
+
+// DeleteLimitPolicy: politikayı siler (override silinirse rol varsayılanına dönülür)
+func DeleteLimitPolicy(id, actorID int) error {
+	p, err := database.LimitRepo().GetPolicy(id)
+	if err != nil {
+		if database.IsNotFound(err) {
+			return errors.New("limit policy not found")
+		}
+		return err
+	}
+	if err := database.LimitRepo().DeletePolicy(id); err != nil {
+		return err
+	}
+	_ = LogAction("limit_policy", id, "delete", fmt.Sprintf("%s %s by user %d", limitScope(p), p.Currency, actorID))
+	return nil
+}
+
+func ListLimitPolicies(role string, userID int) ([]models.LimitPolicy, error) {
+	return database.LimitRepo().ListPolicies(role, userID)
+}
+
+// auditLimitViolation: limit aşımını loglar ve audit kaydı yazar; err limit hatası değilse false döner
+func auditLimitViolation(userID int, op string, amount models.Money, err error) bool {
+	var lim *models.LimitExceededError
+	if !errors.As(err, &lim) {
+		return false
+	}
+	slog.Warn("service."+op+".limit_exceeded", "user_id", userID, "limit", lim.Limit, "amount", amount, "max", lim.Max, "used", lim.Current)
+	_ = LogAction("user", userID, models.ErrCodeLimitExceeded, fmt.Sprintf("%s of %s %s rejected: %s", op, amount, lim.Currency, lim.Error()))
+	return true
+}
+
+func limitScope(p *models.LimitPolicy) string {
+	if p.Role != nil {
+		return "role " + *p.Role
+	}
+	return fmt.Sprintf("user %d", *p.UserID)
+}
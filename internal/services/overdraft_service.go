@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+// SetCreditLine: cüzdana kredi (overdraft) limiti tanımlar (admin). rateBps nil ise OVERDRAFT_RATE_BPS uygulanır.
+func SetCreditLine(actorID, userID int, currency string, limit models.Money, rateBps *int) (*models.Balance, error) {
+	code, err := resolveAmount(currency, limit)
+	if err != nil {
+		return nil, err
+	}
+	if limit.IsNegative() {
+		return nil, errors.New("credit_limit must be >= 0")
+	}
+	if rateBps != nil && (*rateBps < 0 || *rateBps > 100000) {
+		return nil, errors.New("rate_bps must be between 0 and 100000")
+	}
+	b, err := database.BalanceRepo().SetCreditLine(userID, code, limit, rateBps)
+	if err != nil {
+		slog.Warn("service.credit_line.set_failed", "user_id", userID, "currency", code, "err", err)
+		return nil, err
+	}
+	rate := "default"
+	if rateBps != nil {
+		rate = fmt.Sprintf("%d bps", *rateBps)
+	}
+	_ = LogAction("balance", userID, "credit_line", fmt.Sprintf("%s credit limit set to %s (rate %s) by user %d", code, limit, rate, actorID))
+	slog.Info("service.credit_line.set", "user_id", userID, "currency", code, "limit", limit)
+	return b, nil
+}
+
+// AccrueOverdraftInterest: kapanmış (gün sonu + OVERDRAFT_LAG geçmiş) günlerin kapanış bakiyesi negatif olan cüzdanlarına
+// faiz tahakkuk ettirir. Son tahakkuk günü ve sonrası, en fazla OVERDRAFT_CATCHUP_DAYS geriye kadar telafi edilir;
+// tekrar çağrılması güvenlidir. Tahakkuk sayısını döner.
+func AccrueOverdraftInterest(now time.Time) (int, error) {
+	cfg := config.GetOverdraft()
+	to := utcDay(now.Add(-cfg.Lag)).AddDate(0, 0, -1)
+	from := to.AddDate(0, 0, -(cfg.CatchUpDays - 1))
+	last, err := database.BalanceRepo().LastOverdraftAccrualDate()
+	if err != nil {
+		slog.Error("service.overdraft_interest.last_accrual_failed", "err", err)
+		return 0, err
+	}
+	if last != nil && !utcDay(*last).Before(from) {
+		from = utcDay(*last)
+	}
+	wallets, err := database.BalanceRepo().ListCreditWallets()
+	if err != nil {
+		slog.Error("service.overdraft_interest.list_failed", "err", err)
+		return 0, err
+	}
+	count := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for _, b := range wallets {
+			a, err := database.BalanceRepo().AccrueOverdraftInterest(b.UserID, b.Currency, day, cfg.RateBps)
+			if err != nil {
+				slog.Error("service.overdraft_interest.accrue_failed", "user_id", b.UserID, "currency", b.Currency, "day", day.Format("2006-01-02"), "err", err)
+				continue
+			}
+			if a == nil {
+				continue
+			}
+			count++
+			_ = LogAction("transaction", *a.TransactionID, models.TxTypeOverdraftInterest, fmt.Sprintf("Overdraft interest %s %s for %s on closing balance %s at %d bps",
+				a.Interest, a.Currency, day.Format("2006-01-02"), a.Balance, a.RateBps))
+		}
+	}
+	if count > 0 {
+		slog.Info("service.overdraft_interest.accrued", "from", from.Format("2006-01-02"), "to", to.Format("2006-01-02"), "count", count)
+	}
+	return count, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/models"
+	"strconv"
+	"strings"
+)
+
+// parsePain001: pain.001 belgesini okur ve grup başlığını doğrular (MsgId, kalem sayıları, kontrol toplamları).
+// Hatalar dosyanın tamamını reddeden *models.Pain001Error'dur; dönen tutar tüm kalemlerin toplamıdır.
+func parsePain001(data []byte) (*models.Pain001Document, models.Money, error) {
+	reject := func(code, format string, args ...interface{}) (*models.Pain001Document, models.Money, error) {
+		return nil, 0, &models.Pain001Error{Code: code, Reason: fmt.Sprintf(format, args...)}
+	}
+	var doc models.Pain001Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return reject(models.PainReasonFormat, "invalid pain.001: %v", err)
+	}
+	hdr := &doc.GrpHdr
+	hdr.MsgId = strings.TrimSpace(hdr.MsgId)
+	if hdr.MsgId == "" || len(hdr.MsgId) > models.PainMaxIDLength {
+		return reject(models.PainReasonFormat, "GrpHdr/MsgId must be 1-%d characters", models.PainMaxIDLength)
+	}
+	if len(doc.PmtInf) == 0 {
+		return reject(models.PainReasonFormat, "pain.001 contains no payment information")
+	}
+
+	var total models.Money
+	count := 0
+	pmtIDs := make(map[string]bool, len(doc.PmtInf))
+	for i := range doc.PmtInf {
+		pmt := &doc.PmtInf[i]
+		pmt.PmtInfId = strings.TrimSpace(pmt.PmtInfId)
+		if pmt.PmtInfId == "" || len(pmt.PmtInfId) > models.PainMaxIDLength || pmtIDs[pmt.PmtInfId] {
+			return reject(models.PainReasonFormat, "PmtInf %d: PmtInfId missing, too long or duplicated", i+1)
+		}
+		pmtIDs[pmt.PmtInfId] = true
+		if m := strings.TrimSpace(pmt.PmtMtd); m != "" && m != models.PainTransferMethod {
+			return reject(models.PainReasonFormat, "PmtInf %s: PmtMtd must be %s", pmt.PmtInfId, models.PainTransferMethod)
+		}
+		if len(pmt.CdtTrfTxInf) == 0 {
+			return reject(models.PainReasonFormat, "PmtInf %s: no transactions", pmt.PmtInfId)
+		}
+		var sum models.Money
+		for j, t := range pmt.CdtTrfTxInf {
+			amt, err := models.ParseMoney(strings.TrimSpace(t.InstdAmt.Value))
+			if err != nil || amt.IsNegative() {
+				return reject(models.PainReasonFormat, "PmtInf %s transaction %d: invalid InstdAmt %q", pmt.PmtInfId, j+1, t.InstdAmt.Value)
+			}
+			sum += amt
+		}
+		if err := checkPainTotals("PmtInf "+pmt.PmtInfId, pmt.NbOfTxs, pmt.CtrlSum, len(pmt.CdtTrfTxInf), sum, false); err != nil {
+			return nil, 0, err
+		}
+		total += sum
+		count += len(pmt.CdtTrfTxInf)
+	}
+	if max := config.GetPaymentInitiation().MaxTxs; count > max {
+		return reject(models.PainReasonFormat, "too many transactions (max %d)", max)
+	}
+	if err := checkPainTotals("GrpHdr", hdr.NbOfTxs, hdr.CtrlSum, count, total, true); err != nil {
+		return nil, 0, err
+	}
+	return &doc, total, nil
+}
+
+// checkPainTotals: verilen NbOfTxs ve CtrlSum değerlerini kalemlerle karşılaştırır; NbOfTxs yalnızca GrpHdr'de zorunludur
+func checkPainTotals(where, nbOfTxs, ctrlSum string, count int, sum models.Money, required bool) error {
+	nbOfTxs, ctrlSum = strings.TrimSpace(nbOfTxs), strings.TrimSpace(ctrlSum)
+	if nbOfTxs == "" && required {
+		return &models.Pain001Error{Code: models.PainReasonTxCount, Reason: where + "/NbOfTxs is required"}
+	}
+	if nbOfTxs != "" {
+		if n, err := strconv.Atoi(nbOfTxs); err != nil || n != count {
+			return &models.Pain001Error{Code: models.PainReasonTxCount, Reason: fmt.Sprintf("%s/NbOfTxs %s does not match %d transactions", where, nbOfTxs, count)}
+		}
+	}
+	if ctrlSum != "" {
+		if v, err := models.ParseMoney(ctrlSum); err != nil || v != sum {
+			return &models.Pain001Error{Code: models.PainReasonControlSum, Reason: fmt.Sprintf("%s/CtrlSum %s does not match transaction total %s", where, ctrlSum, sum)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"insider-go-backend/internal/models"
+	"strings"
+	"testing"
+)
+
+func painTx(amt string) string {
+	return `<CdtTrfTxInf><PmtId><EndToEndId>E2E</EndToEndId></PmtId><Amt><InstdAmt Ccy="USD">` + amt + `</InstdAmt></Amt>` +
+		`<Cdtr><Nm>Ada</Nm></Cdtr><CdtrAcct><Id><Othr><Id>42-USD</Id></Othr></Id></CdtrAcct></CdtTrfTxInf>`
+}
+
+func painPmt(id, mtd, nbOfTxs, ctrlSum string, txs ...string) string {
+	s := `<PmtInf><PmtInfId>` + id + `</PmtInfId>`
+	if mtd != "" {
+		s += `<PmtMtd>` + mtd + `</PmtMtd>`
+	}
+	if nbOfTxs != "" {
+		s += `<NbOfTxs>` + nbOfTxs + `</NbOfTxs>`
+	}
+	if ctrlSum != "" {
+		s += `<CtrlSum>` + ctrlSum + `</CtrlSum>`
+	}
+	return s + `<DbtrAcct><Id><Othr><Id>7-USD</Id></Othr></Id><Ccy>USD</Ccy></DbtrAcct>` + strings.Join(txs, "") + `</PmtInf>`
+}
+
+func painDoc(msgID, nbOfTxs, ctrlSum string, pmts ...string) []byte {
+	hdr := `<GrpHdr><MsgId>` + msgID + `</MsgId><CreDtTm>2026-03-02T10:00:00</CreDtTm>`
+	if nbOfTxs != "" {
+		hdr += `<NbOfTxs>` + nbOfTxs + `</NbOfTxs>`
+	}
+	if ctrlSum != "" {
+		hdr += `<CtrlSum>` + ctrlSum + `</CtrlSum>`
+	}
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"><CstmrCdtTrfInitn>` +
+		hdr + `</GrpHdr>` + strings.Join(pmts, "") + `</CstmrCdtTrfInitn></Document>`)
+}
+
+func TestParsePain001(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []byte
+		pmts  int
+		total string
+	}{
+		{"single payment", painDoc("M1", "1", "10", painPmt("P1", "TRF", "1", "10", painTx("10"))), 1, "10"},
+		{"optional PmtInf totals", painDoc(" M1 ", "3", "", painPmt("P1", "", "", "", painTx("1.25"), painTx("2.5")), painPmt("P2", "TRF", "", "", painTx("0.25"))), 2, "4"},
+		{"control sums with trailing zeros", painDoc("M1", "2", "3.7500", painPmt("P1", "TRF", "2", "3.75", painTx("1.25"), painTx("2.50"))), 1, "3.75"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, total, err := parsePain001(tt.data)
+			if err != nil {
+				t.Fatalf("parsePain001 error = %v", err)
+			}
+			if doc.GrpHdr.MsgId != "M1" {
+				t.Errorf("MsgId = %q, want M1", doc.GrpHdr.MsgId)
+			}
+			if len(doc.PmtInf) != tt.pmts {
+				t.Errorf("got %d PmtInf, want %d", len(doc.PmtInf), tt.pmts)
+			}
+			if want := models.MustParseMoney(tt.total); total != want {
+				t.Errorf("total = %s, want %s", total, want)
+			}
+		})
+	}
+}
+
+func TestParsePain001Errors(t *testing.T) {
+	t.Setenv("PAIN001_MAX_TXS", "3")
+	ok := painPmt("P1", "TRF", "1", "10", painTx("10"))
+	tests := []struct {
+		name   string
+		data   []byte
+		code   string
+		reason string
+	}{
+		{"malformed xml", []byte(`<Document><CstmrCdtTrfInitn><GrpHdr>`), models.PainReasonFormat, "invalid pain.001"},
+		{"missing MsgId", painDoc(" ", "1", "10", ok), models.PainReasonFormat, "GrpHdr/MsgId must be 1-35 characters"},
+		{"long MsgId", painDoc(strings.Repeat("M", 36), "1", "10", ok), models.PainReasonFormat, "GrpHdr/MsgId must be 1-35 characters"},
+		{"no payment information", painDoc("M1", "0", ""), models.PainReasonFormat, "pain.001 contains no payment information"},
+		{"missing PmtInfId", painDoc("M1", "1", "10", painPmt("", "TRF", "", "", painTx("10"))), models.PainReasonFormat, "PmtInf 1: PmtInfId missing, too long or duplicated"},
+		{"duplicate PmtInfId", painDoc("M1", "2", "20", ok, ok), models.PainReasonFormat, "PmtInf 2: PmtInfId missing, too long or duplicated"},
+		{"cheque method", painDoc("M1", "1", "10", painPmt("P1", "CHK", "", "", painTx("10"))), models.PainReasonFormat, "PmtInf P1: PmtMtd must be TRF"},
+		{"no transactions", painDoc("M1", "0", "", painPmt("P1", "TRF", "", "")), models.PainReasonFormat, "PmtInf P1: no transactions"},
+		{"bad amount", painDoc("M1", "1", "", painPmt("P1", "TRF", "", "", painTx("ten"))), models.PainReasonFormat, `PmtInf P1 transaction 1: invalid InstdAmt "ten"`},
+		{"negative amount", painDoc("M1", "1", "", painPmt("P1", "TRF", "", "", painTx("-1"))), models.PainReasonFormat, `PmtInf P1 transaction 1: invalid InstdAmt "-1"`},
+		{"wrong PmtInf NbOfTxs", painDoc("M1", "2", "", painPmt("P1", "TRF", "1", "", painTx("1"), painTx("2"))), models.PainReasonTxCount, "PmtInf P1/NbOfTxs 1 does not match 2 transactions"},
+		{"wrong PmtInf CtrlSum", painDoc("M1", "2", "", painPmt("P1", "TRF", "2", "3.01", painTx("1"), painTx("2"))), models.PainReasonControlSum, "PmtInf P1/CtrlSum 3.01 does not match transaction total 3.00"},
+		{"too many transactions", painDoc("M1", "4", "", painPmt("P1", "TRF", "", "", painTx("1"), painTx("1")), painPmt("P2", "TRF", "", "", painTx("1"), painTx("1"))), models.PainReasonFormat, "too many transactions (max 3)"},
+		{"missing GrpHdr NbOfTxs", painDoc("M1", "", "10", ok), models.PainReasonTxCount, "GrpHdr/NbOfTxs is required"},
+		{"wrong GrpHdr NbOfTxs", painDoc("M1", "2", "10", ok), models.PainReasonTxCount, "GrpHdr/NbOfTxs 2 does not match 1 transactions"},
+		{"wrong GrpHdr CtrlSum", painDoc("M1", "2", "11", ok, painPmt("P2", "TRF", "", "", painTx("0.5"))), models.PainReasonControlSum, "GrpHdr/CtrlSum 11 does not match transaction total 10.50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, _, err := parsePain001(tt.data)
+			var perr *models.Pain001Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("parsePain001 = %v, %v; want *Pain001Error", doc, err)
+			}
+			if perr.Code != tt.code || !strings.HasPrefix(perr.Reason, tt.reason) {
+				t.Errorf("parsePain001 error = %s %q, want %s %q", perr.Code, perr.Reason, tt.code, tt.reason)
+			}
+		})
+	}
+}
+
+func TestCheckPainTotals(t *testing.T) {
+	sum := models.MustParseMoney("12.5")
+	tests := []struct {
+		name             string
+		nbOfTxs, ctrlSum string
+		required         bool
+		code             string
+	}{
+		{"both match", "2", "12.50", true, ""},
+		{"padded values", " 2 ", " 12.5 ", true, ""},
+		{"optional and absent", "", "", false, ""},
+		{"required and absent", "", "12.5", true, models.PainReasonTxCount},
+		{"count mismatch", "3", "12.5", false, models.PainReasonTxCount},
+		{"count not a number", "two", "", false, models.PainReasonTxCount},
+		{"sum mismatch", "2", "12.49", false, models.PainReasonControlSum},
+		{"sum not a number", "", "12,5", false, models.PainReasonControlSum},
+		{"sum beyond precision", "", "12.50001", false, models.PainReasonControlSum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPainTotals("GrpHdr", tt.nbOfTxs, tt.ctrlSum, 2, sum, tt.required)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("checkPainTotals error = %v", err)
+				}
+				return
+			}
+			var perr *models.Pain001Error
+			if !errors.As(err, &perr) || perr.Code != tt.code {
+				t.Errorf("checkPainTotals error = %v, want code %s", err, tt.code)
+			}
+		})
+	}
+}
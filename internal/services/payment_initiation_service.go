@@ -0,0 +1,261 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SubmitPain001: pain.001 dosyasını doğrular, borçlu ve alacaklıları cüzdanlara eşler ve talimatı kaydeder.
+// Grup başlığı hataları dosyayı reddeder (*models.Pain001Error); kalem hataları yalnızca o kalemi RJCT yapar.
+// Kalemler burada çalıştırılmaz; PDNG kalemler işlemci tarafından TransferWith ile gerçekleştirilir.
+func SubmitPain001(actorID int, isAdmin bool, filename string, data []byte) (*models.PaymentInitiation, error) {
+	doc, total, err := parsePain001(data)
+	if err != nil {
+		return nil, err
+	}
+	p := &models.PaymentInitiation{
+		SubmittedBy: actorID, MsgID: doc.GrpHdr.MsgId, Filename: filename,
+		InitiatedBy: strings.TrimSpace(doc.GrpHdr.InitgPty.Nm), ControlSum: total, Status: models.PaymentInitiationProcessing,
+	}
+	seen := make(map[string]bool)
+	for _, pmt := range doc.PmtInf {
+		debtor, currency, err := resolvePainDebtor(actorID, isAdmin, pmt)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range pmt.CdtTrfTxInf {
+			it := models.PaymentInitiationTx{
+				Seq: len(p.Txs), PmtInfID: pmt.PmtInfId, InstrID: strings.TrimSpace(t.PmtId.InstrId),
+				EndToEndID: strings.TrimSpace(t.PmtId.EndToEndId), FromUserID: debtor,
+				CreditorName: strings.TrimSpace(t.Cdtr.Nm), Remittance: truncateRunes(t.Remittance(), models.MaxMemoLength),
+				Status: models.PainStatusPending,
+			}
+			if it.EndToEndID == "" {
+				it.EndToEndID = models.PainNotProvided
+			}
+			if code, reason := preparePainTx(&it, t, currency, seen); code != "" {
+				it.Status, it.ReasonCode, it.Reason = models.PainStatusRejected, code, reason
+			}
+			p.Txs = append(p.Txs, it)
+		}
+	}
+	p.TxCount = len(p.Txs)
+	p.Tally()
+	if p.RejectedCount == p.TxCount {
+		now := time.Now()
+		p.Status, p.CompletedAt = models.PaymentInitiationCompleted, &now
+	}
+	if err := database.PaymentInitiationRepo().Create(p); err != nil {
+		if errors.Is(err, database.ErrDuplicatePaymentInitiation) {
+			slog.Warn("service.pain001.duplicate", "submitted_by", actorID, "msg_id", p.MsgID)
+			return nil, &models.Pain001Error{Code: models.PainReasonDuplicateMsg, Reason: err.Error()}
+		}
+		slog.Error("service.pain001.create_failed", "submitted_by", actorID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("payment_initiation", p.ID, "submit", fmt.Sprintf("pain.001 %s with %d transactions (%s total) by user %d: %d rejected on validation",
+		p.MsgID, p.TxCount, total, actorID, p.RejectedCount))
+	slog.Info("service.pain001.submitted", "id", p.ID, "msg_id", p.MsgID, "txs", p.TxCount, "rejected", p.RejectedCount)
+	return p, nil
+}
+
+// resolvePainDebtor: PmtInf borçlu hesabını cüzdana çözer; admin dışındaki kullanıcılar yalnızca kendi cüzdanından ödeyebilir
+func resolvePainDebtor(actorID int, isAdmin bool, pmt models.Pain001PaymentInfo) (int, string, error) {
+	account := strings.TrimSpace(pmt.DbtrAcct.Id.String())
+	userID, currency, ok := walletAccount(account)
+	if !ok {
+		return 0, "", &models.Pain001Error{Code: models.PainReasonDebtorAccount, Reason: fmt.Sprintf("PmtInf %s: debtor account %q is not a wallet account id", pmt.PmtInfId, account)}
+	}
+	if !isAdmin && userID != actorID {
+		return 0, "", &models.Pain001Error{Code: models.PainReasonForbidden, Reason: fmt.Sprintf("PmtInf %s: debtor account does not belong to you", pmt.PmtInfId)}
+	}
+	if ccy := strings.TrimSpace(pmt.DbtrAcct.Ccy); ccy != "" && !strings.EqualFold(ccy, currency) {
+		return 0, "", &models.Pain001Error{Code: models.PainReasonCurrency, Reason: fmt.Sprintf("PmtInf %s: DbtrAcct/Ccy %s does not match account %s", pmt.PmtInfId, ccy, account)}
+	}
+	if _, err := database.BalanceRepo().GetBalance(userID, currency); err != nil {
+		return 0, "", &models.Pain001Error{Code: models.PainReasonDebtorAccount, Reason: fmt.Sprintf("PmtInf %s: debtor wallet %s not found", pmt.PmtInfId, account)}
+	}
+	return userID, currency, nil
+}
+
+// preparePainTx: kalemin tutarını, para birimini ve alacaklısını doldurur; red durumunda neden kodu ve açıklama döner
+func preparePainTx(it *models.PaymentInitiationTx, t models.Pain001Tx, currency string, seen map[string]bool) (string, string) {
+	if len(it.InstrID) > models.PainMaxIDLength || len(it.EndToEndID) > models.PainMaxIDLength {
+		return models.PainReasonFormat, fmt.Sprintf("InstrId and EndToEndId must be at most %d characters", models.PainMaxIDLength)
+	}
+	it.Amount, _ = models.ParseMoney(strings.TrimSpace(t.InstdAmt.Value)) // parsePain001 doğruladı
+	it.Currency = strings.ToUpper(strings.TrimSpace(t.InstdAmt.Ccy))
+	if it.Currency == "" {
+		it.Currency = currency
+	}
+	if it.Currency != currency {
+		return models.PainReasonCurrency, "currency must match debtor account (" + currency + ")"
+	}
+	if it.Amount.IsZero() {
+		return models.PainReasonZeroAmount, "amount must be positive"
+	}
+	if _, err := resolveAmount(it.Currency, it.Amount); err != nil {
+		return models.PainReasonAmount, err.Error()
+	}
+	if it.EndToEndID != models.PainNotProvided {
+		key := fmt.Sprintf("%d/%s", it.FromUserID, it.EndToEndID)
+		if seen[key] {
+			return models.PainReasonDuplicate, "duplicate EndToEndId"
+		}
+		seen[key] = true
+	}
+	ids := t.CreditorIDs()
+	if len(ids) == 0 {
+		return models.PainReasonCreditorAcct, "creditor account or identifier required"
+	}
+	it.CreditorAccount = ids[0]
+	userID, code, reason := resolvePainCreditor(ids, it.Currency)
+	if code != "" {
+		return code, reason
+	}
+	if userID == it.FromUserID {
+		return models.PainReasonNarrative, "creditor is the debtor"
+	}
+	it.ToUserID = &userID
+	return "", ""
+}
+
+// resolvePainCreditor: alacaklıyı cüzdan hesap kimliğinden (ör. "42-USD") ya da kullanıcı adından ("@alice" ya da "alice") bulur
+func resolvePainCreditor(ids []string, currency string) (int, string, string) {
+	for _, id := range ids {
+		userID, cur, ok := walletAccount(id)
+		if ok {
+			if cur != currency {
+				return 0, models.PainReasonCurrency, fmt.Sprintf("creditor account %s is not a %s wallet", id, currency)
+			}
+		} else {
+			u, err := database.UserRepo().GetUserByUsername(strings.TrimPrefix(id, "@"))
+			if err != nil {
+				continue
+			}
+			userID = u.ID
+		}
+		if _, err := database.BalanceRepo().GetBalance(userID, currency); err != nil {
+			return 0, models.PainReasonCreditorWallet, fmt.Sprintf("creditor %s has no %s wallet", id, currency)
+		}
+		return userID, "", ""
+	}
+	return 0, models.PainReasonCreditorAcct, "creditor not found"
+}
+
+// walletAccount: metnin tamamı bir cüzdan hesap kimliğiyse kullanıcıyı ve para birimini döner
+func walletAccount(s string) (int, string, bool) {
+	userID, currency, ok := models.FindWalletAccountID(s)
+	if !ok || !strings.EqualFold(models.WalletAccountID(userID, currency), strings.TrimSpace(s)) {
+		return 0, "", false
+	}
+	return userID, currency, true
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// RecordPaymentInitiationTx: işlemcinin transfer sonucunu kaleme yazar. Origin çakışması kalemin daha önce
+// (ör. yeniden başlatmadan önce) gerçekleştiği anlamına gelir; mevcut işlem bağlanır.
+func RecordPaymentInitiationTx(t models.PaymentInitiationTx, tx *models.Transaction, err error) {
+	if errors.Is(err, database.ErrDuplicateOrigin) {
+		tx, err = database.TransactionRepo().GetTransactionByOrigin(*t.Options().Origin)
+	}
+	status, code, reason := models.PainStatusSettled, "", ""
+	var txID *int
+	if err != nil {
+		status, code, reason = models.PainStatusRejected, painReasonCode(err), err.Error()
+	} else if tx != nil {
+		id := tx.ID
+		txID = &id
+	}
+	if err := database.PaymentInitiationRepo().SettleTx(t.ID, status, code, reason, txID); err != nil {
+		slog.Warn("service.pain001.settle_failed", "initiation_id", t.InitiationID, "seq", t.Seq, "err", err)
+	}
+}
+
+// painReasonCode: transfer hatasının pain.002 neden kodu
+func painReasonCode(err error) string {
+	var lim *models.LimitExceededError
+	if errors.As(err, &lim) {
+		return models.PainReasonAmount
+	}
+	switch err.Error() {
+	case "insufficient funds":
+		return models.PainReasonFunds
+	case "sender balance not found":
+		return models.PainReasonDebtorAccount
+	case "recipient balance not found":
+		return models.PainReasonCreditorWallet
+	case "currency mismatch":
+		return models.PainReasonCurrency
+	case database.ErrDuplicateExternalRef.Error():
+		return models.PainReasonDuplicate
+	}
+	return models.PainReasonNarrative
+}
+
+// FinishPaymentInitiation: kalem durumlarından grup durumunu hesaplar; tüm kalemler sonuçlandıysa talimatı kapatır
+func FinishPaymentInitiation(id int) (*models.PaymentInitiation, error) {
+	p, err := database.PaymentInitiationRepo().Get(id)
+	if err != nil {
+		return nil, errors.New("payment initiation not found")
+	}
+	if p.Status == models.PaymentInitiationCompleted {
+		return p, nil
+	}
+	if err := database.PaymentInitiationRepo().Finish(p); err != nil {
+		slog.Error("service.pain001.finish_failed", "id", id, "err", err)
+		return nil, err
+	}
+	if p.Status == models.PaymentInitiationCompleted {
+		_ = LogAction("payment_initiation", p.ID, "complete", fmt.Sprintf("pain.001 %s finished with %s: %d settled, %d rejected",
+			p.MsgID, p.GroupStatus, p.SettledCount, p.RejectedCount))
+		slog.Info("service.pain001.completed", "id", p.ID, "group_status", p.GroupStatus, "settled", p.SettledCount, "rejected", p.RejectedCount)
+	}
+	return p, nil
+}
+
+// PendingPaymentInitiations: tamamlanmamış talimatlar (kalemleriyle); yeniden başlatmada işlemciye geri verilir
+func PendingPaymentInitiations() ([]*models.PaymentInitiation, error) {
+	ids, err := database.PaymentInitiationRepo().Processing()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*models.PaymentInitiation, 0, len(ids))
+	for _, id := range ids {
+		p, err := database.PaymentInitiationRepo().Get(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// GetPaymentInitiation: talimat ve kalemleri (yalnızca gönderen ya da admin görebilir)
+func GetPaymentInitiation(actorID int, isAdmin bool, id int) (*models.PaymentInitiation, error) {
+	p, err := database.PaymentInitiationRepo().Get(id)
+	if err != nil || (!isAdmin && p.SubmittedBy != actorID) {
+		return nil, errors.New("payment initiation not found")
+	}
+	return p, nil
+}
+
+// ListPaymentInitiations: kullanıcının son talimatları (kalemler olmadan)
+func ListPaymentInitiations(actorID, limit int) ([]models.PaymentInitiation, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return database.PaymentInitiationRepo().List(actorID, limit)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// ErrPaymentRequestForbidden: işlem isteğin diğer tarafına ait (ör. isteyenin kabul etmesi)
+var ErrPaymentRequestForbidden = errors.New("not allowed to operate on this payment request")
+
+// paymentRequestSweepBatch: sweeper'ın tek turda kapattığı en fazla istek sayısı
+const paymentRequestSweepBatch = 500
+
+// CreatePaymentRequest: requesterID'nin payerID'den para istemesi. ttl sıfırsa PAYMENT_REQUEST_TTL kullanılır.
+func CreatePaymentRequest(requesterID, payerID int, amount models.Money, currency, note string, ttl time.Duration) (*models.PaymentRequest, error) {
+	if payerID == 0 || payerID == requesterID {
+		return nil, errors.New("invalid payer")
+	}
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.GetPaymentRequests()
+	if ttl <= 0 {
+		ttl = cfg.TTL
+	}
+	if ttl > cfg.MaxTTL {
+		return nil, fmt.Errorf("payment request ttl exceeds maximum of %s", cfg.MaxTTL)
+	}
+	if _, err := database.UserRepo().GetUserByID(payerID); err != nil {
+		return nil, errors.New("payer not found")
+	}
+	pr := &models.PaymentRequest{
+		RequesterID: requesterID, PayerID: payerID, Amount: amount, Currency: currency,
+		Note: strings.TrimSpace(note), Status: models.PaymentRequestPending, ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := database.PaymentRequestRepo().Create(pr); err != nil {
+		slog.Error("service.payment_request.create_failed", "requester_id", requesterID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("payment_request", pr.ID, "create", fmt.Sprintf("User %d requested %s %s from user %d until %s",
+		requesterID, amount, currency, payerID, pr.ExpiresAt.Format(time.RFC3339)))
+	slog.Info("service.payment_request.created", "id", pr.ID, "requester_id", requesterID, "payer_id", payerID)
+	return pr, nil
+}
+
+// ListPaymentRequests: direction incoming (ödenecek) | outgoing (istenen) | boş (ikisi)
+func ListPaymentRequests(userID int, direction, status string) ([]models.PaymentRequest, error) {
+	if direction != "" && direction != "incoming" && direction != "outgoing" {
+		return nil, errors.New("direction must be incoming or outgoing")
+	}
+	return database.PaymentRequestRepo().List(userID, direction, status)
+}
+
+// GetPaymentRequest: yalnızca taraflar görebilir
+func GetPaymentRequest(userID, id int) (*models.PaymentRequest, error) {
+	pr, err := database.PaymentRequestRepo().Get(id)
+	if err != nil || (pr.RequesterID != userID && pr.PayerID != userID) {
+		return nil, errors.New("payment request not found")
+	}
+	return pr, nil
+}
+
+// AcceptPaymentRequest: ödeyen isteği kabul eder; payer'dan requester'a transfer yapılır ve işlem isteğe bağlanır.
+// İstek önce accepting'e alınır (iptal/ret ile yarışmaz); transfer başarısız olursa tekrar pending olur.
+// Transfer origin'i istek başına tektir: yarım kalan kabul tekrar denenirse ikinci kez para çekilmez.
+func AcceptPaymentRequest(payerID, id int) (*models.PaymentRequest, error) {
+	pr, err := GetPaymentRequest(payerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.PayerID != payerID {
+		return nil, ErrPaymentRequestForbidden
+	}
+	repo := database.PaymentRequestRepo()
+	if err := repo.Transition(id, []string{models.PaymentRequestPending, models.PaymentRequestAccepting}, models.PaymentRequestAccepting, nil, time.Now()); err != nil {
+		return nil, paymentRequestStateError(pr, err)
+	}
+
+	origin := &models.TxOrigin{Type: models.OriginPaymentRequest, ID: pr.ID}
+	_, _, tx, err := TransferWith(pr.PayerID, pr.RequesterID, pr.Amount, pr.Currency, models.TxOptions{Origin: origin})
+	if errors.Is(err, database.ErrDuplicateOrigin) {
+		tx, err = database.TransactionRepo().GetTransactionByOrigin(*origin)
+	}
+	if err != nil {
+		if rerr := repo.Transition(id, []string{models.PaymentRequestAccepting}, models.PaymentRequestPending, nil, time.Now()); rerr != nil {
+			slog.Error("service.payment_request.revert_failed", "id", id, "err", rerr)
+		}
+		slog.Warn("service.payment_request.accept_failed", "id", id, "payer_id", payerID, "err", err)
+		return nil, err
+	}
+	txID := tx.ID
+	if err := repo.Transition(id, []string{models.PaymentRequestAccepting}, models.PaymentRequestAccepted, &txID, time.Now()); err != nil {
+		slog.Error("service.payment_request.finish_failed", "id", id, "transaction_id", txID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("payment_request", id, models.PaymentRequestAccepted, fmt.Sprintf("Accepted by user %d, paid %s %s with transaction %d", payerID, pr.Amount, pr.Currency, txID))
+	slog.Info("service.payment_request.accepted", "id", id, "transaction_id", txID)
+	return database.PaymentRequestRepo().Get(id)
+}
+
+// DeclinePaymentRequest: ödeyen bekleyen isteği reddeder
+func DeclinePaymentRequest(payerID, id int) (*models.PaymentRequest, error) {
+	return closePaymentRequest(payerID, id, models.PaymentRequestDeclined)
+}
+
+// CancelPaymentRequest: isteyen bekleyen isteğini geri çeker
+func CancelPaymentRequest(requesterID, id int) (*models.PaymentRequest, error) {
+	return closePaymentRequest(requesterID, id, models.PaymentRequestCancelled)
+}
+
+func closePaymentRequest(userID, id int, to string) (*models.PaymentRequest, error) {
+	pr, err := GetPaymentRequest(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if (to == models.PaymentRequestDeclined && pr.PayerID != userID) || (to == models.PaymentRequestCancelled && pr.RequesterID != userID) {
+		return nil, ErrPaymentRequestForbidden
+	}
+	if err := database.PaymentRequestRepo().Transition(id, []string{models.PaymentRequestPending}, to, nil, time.Now()); err != nil {
+		return nil, paymentRequestStateError(pr, err)
+	}
+	_ = LogAction("payment_request", id, to, fmt.Sprintf("Status changed to %s by user %d", to, userID))
+	slog.Info("service.payment_request.transition", "id", id, "status", to)
+	return database.PaymentRequestRepo().Get(id)
+}
+
+// paymentRequestStateError: geçiş reddedildiyse nedenini (süre dolumu / durum) ayırt eder
+func paymentRequestStateError(pr *models.PaymentRequest, err error) error {
+	if err.Error() != "invalid payment request state" {
+		return err
+	}
+	if pr.Status == models.PaymentRequestExpired || (pr.Status == models.PaymentRequestPending && !time.Now().Before(pr.ExpiresAt)) {
+		return errors.New("payment request expired")
+	}
+	return errors.New("payment request not pending")
+}
+
+// ExpirePaymentRequests: süresi dolan bekleyen istekleri kapatır ve her birini audit'e yazar
+func ExpirePaymentRequests() (int, error) {
+	total := 0
+	for {
+		expired, err := database.PaymentRequestRepo().ExpireDue(time.Now(), paymentRequestSweepBatch)
+		if err != nil {
+			slog.Error("service.payment_request.expire_failed", "err", err)
+			return total, err
+		}
+		for _, pr := range expired {
+			_ = LogAction("payment_request", pr.ID, models.PaymentRequestExpired, fmt.Sprintf("Expired unanswered (%s %s from user %d)", pr.Amount, pr.Currency, pr.PayerID))
+		}
+		total += len(expired)
+		if len(expired) < paymentRequestSweepBatch {
+			break
+		}
+	}
+	if total > 0 {
+		slog.Info("service.payment_request.expired", "count", total)
+	}
+	return total, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ledgerDriftAmount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ledger_balance_drift_amount",
+			Help: "Sum of absolute differences between balances and ledger totals at the last reconciliation",
+		},
+		[]string{"currency"},
+	)
+
+	ledgerDriftWallets = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ledger_balance_drift_wallets",
+			Help: "Number of wallets whose balance differs from the ledger at the last reconciliation",
+		},
+		[]string{"currency"},
+	)
+
+	ledgerTxMismatches = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ledger_transaction_mismatches",
+			Help: "Number of transactions whose ledger legs are unbalanced, missing or disagree with the transaction at the last reconciliation",
+		},
+		[]string{"reason"},
+	)
+
+	ledgerReconcileLastRun = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ledger_reconcile_last_run_timestamp_seconds",
+			Help: "Unix time of the last completed reconciliation",
+		},
+	)
+)
+
+// Reconcile: tüm cüzdanların bakiyesini ledger geçmişinden yeniden hesaplar, işlemleri ledger bacaklarıyla
+// karşılaştırır ve uyuşmazlıkları raporlar. apply true ise her cüzdan sapması için düzeltme (adjustment) kaydı açılır;
+// balances değişmez, ledger ona eşitlenir. İşlem uyuşmazlıkları yalnızca raporlanır.
+// Drift gauge'ları düzeltme öncesi ölçümle güncellenir.
+func Reconcile(apply bool, actorID int) (*models.ReconcileReport, error) {
+	slog.Info("service.reconcile.start", "apply", apply)
+	wallets, mismatches, err := database.LedgerRepo().WalletDrift()
+	if err != nil {
+		slog.Error("service.reconcile.failed", "err", err)
+		return nil, err
+	}
+	transactions, txMismatches, err := database.LedgerRepo().TransactionDrift()
+	if err != nil {
+		slog.Error("service.reconcile.transactions_failed", "err", err)
+		return nil, err
+	}
+	report := &models.ReconcileReport{At: time.Now(), Wallets: wallets, Mismatches: mismatches, Transactions: transactions, TxMismatches: txMismatches, Applied: apply}
+	if report.Mismatches == nil {
+		report.Mismatches = []models.ReconcileItem{}
+	}
+	if report.TxMismatches == nil {
+		report.TxMismatches = []models.TxReconcileItem{}
+	}
+
+	drift := map[string]*models.CurrencyDrift{}
+	for _, m := range mismatches {
+		d, ok := drift[m.Currency]
+		if !ok {
+			d = &models.CurrencyDrift{Currency: m.Currency}
+			drift[m.Currency] = d
+		}
+		d.Wallets++
+		if m.Delta.IsNegative() {
+			d.AbsDelta += m.Delta.Neg()
+		} else {
+			d.AbsDelta += m.Delta
+		}
+		slog.Warn("service.reconcile.mismatch", "user_id", m.UserID, "currency", m.Currency, "expected", m.Expected, "actual", m.Actual, "delta", m.Delta)
+	}
+	ledgerDriftAmount.Reset()
+	ledgerDriftWallets.Reset()
+	for _, d := range drift {
+		report.Drift = append(report.Drift, *d)
+		ledgerDriftAmount.WithLabelValues(d.Currency).Set(d.AbsDelta.Float64())
+		ledgerDriftWallets.WithLabelValues(d.Currency).Set(float64(d.Wallets))
+	}
+	ledgerTxMismatches.Reset()
+	byReason := map[string]int{}
+	for _, m := range txMismatches {
+		byReason[m.Reason]++
+		slog.Warn("service.reconcile.tx_mismatch", "transaction_id", m.TransactionID, "type", m.Type, "reason", m.Reason, "user_id", m.UserID, "currency", m.Currency, "expected", m.Expected, "actual", m.Actual)
+	}
+	for _, reason := range []string{models.TxMismatchUnbalanced, models.TxMismatchMissingLegs, models.TxMismatchWalletLeg} {
+		ledgerTxMismatches.WithLabelValues(reason).Set(float64(byReason[reason]))
+	}
+	ledgerReconcileLastRun.SetToCurrentTime()
+
+	if apply {
+		for i := range report.Mismatches {
+			m := &report.Mismatches[i]
+			fixed, err := database.LedgerRepo().CorrectDrift(m.UserID, m.Currency)
+			if err != nil {
+				slog.Error("service.reconcile.correct_failed", "user_id", m.UserID, "currency", m.Currency, "err", err)
+				continue
+			}
+			if fixed == nil {
+				continue
+			}
+			m.Corrected, m.TransactionID = true, fixed.TransactionID
+			_ = LogAction("transaction", *fixed.TransactionID, "reconcile_adjustment", fmt.Sprintf("Ledger corrected for user %d by %s %s (expected %s, actual %s) by user %d",
+				m.UserID, fixed.Delta, m.Currency, fixed.Expected, fixed.Actual, actorID))
+		}
+	}
+	slog.Info("service.reconcile.done", "wallets", wallets, "mismatches", len(mismatches), "transactions", transactions, "tx_mismatches", len(txMismatches), "apply", apply)
+	return report, nil
+}
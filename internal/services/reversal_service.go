@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+)
+
+// ReverseTransaction: işlemin iade edilmemiş kalan kısmını tamamen geri alır (orijinal durum: reversed)
+func ReverseTransaction(txID int, reason string, actorID int) (*models.Transaction, *models.Transaction, error) {
+	return compensate(txID, 0, models.TxTypeReversal, reason, actorID)
+}
+
+// RefundTransaction: işlemin bir kısmını iade eder; toplam iade tutara ulaşınca orijinal reversed olur
+func RefundTransaction(txID int, amount models.Money, reason string, actorID int) (*models.Transaction, *models.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, nil, errors.New("refund amount must be > 0")
+	}
+	return compensate(txID, amount, models.TxTypeRefund, reason, actorID)
+}
+
+func compensate(txID int, amount models.Money, txType, reason string, actorID int) (*models.Transaction, *models.Transaction, error) {
+	slog.Info("service."+txType+".start", "transaction_id", txID, "amount", amount, "actor_id", actorID)
+	if !amount.IsZero() {
+		orig, err := database.TransactionRepo().GetTransactionByID(txID)
+		if err != nil {
+			if database.IsNotFound(err) {
+				return nil, nil, errors.New("transaction not found")
+			}
+			return nil, nil, err
+		}
+		// iade tutarı orijinal para biriminin minor unit kuralına uymalı
+		if _, err := resolveAmount(orig.Currency, amount); err != nil {
+			return nil, nil, err
+		}
+	}
+	rec, orig, err := database.TransactionRepo().ReverseAtomic(txID, amount, txType)
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found", "transaction already reversed", "transaction not reversible", "refund exceeds remaining amount":
+			slog.Warn("service."+txType+".rejected", "transaction_id", txID, "reason", err.Error())
+		case "insufficient funds":
+			slog.Warn("service."+txType+".insufficient_funds", "transaction_id", txID, "amount", amount)
+		default:
+			slog.Error("service."+txType+".failed", "transaction_id", txID, "err", err)
+		}
+		return nil, nil, err
+	}
+	details := fmt.Sprintf("%s of %s %s by user %d (refunded %s of %s)", txType, rec.Amount, rec.Currency, actorID, orig.RefundedAmount, orig.Amount)
+	if reason != "" {
+		details += ": " + reason
+	}
+	_ = LogAction("transaction", orig.ID, txType, details+fmt.Sprintf("; compensating transaction %d", rec.ID))
+	_ = LogAction("transaction", rec.ID, txType, details+fmt.Sprintf("; original transaction %d", orig.ID))
+	slog.Info("service."+txType+".success", "transaction_id", orig.ID, "compensating_id", rec.ID, "status", orig.Status)
+	return rec, orig, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+// CreateScheduledTransfer: executeAt anında çalıştırılacak transfer talimatı oluşturur
+func CreateScheduledTransfer(userID, toUserID int, amount models.Money, currency string, executeAt time.Time) (*models.ScheduledTransfer, error) {
+	st := &models.ScheduledTransfer{UserID: userID, Status: models.ScheduledPending}
+	if err := applyScheduledFields(st, toUserID, amount, currency, executeAt); err != nil {
+		return nil, err
+	}
+	if err := database.ScheduledTransferRepo().Create(st); err != nil {
+		slog.Error("service.scheduled_transfer.create_failed", "user_id", userID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("scheduled_transfer", st.ID, "create", fmt.Sprintf("Scheduled %s %s from user %d to user %d at %s", st.Amount, st.Currency, userID, toUserID, executeAt.Format(time.RFC3339)))
+	slog.Info("service.scheduled_transfer.created", "id", st.ID, "user_id", userID, "execute_at", executeAt)
+	return st, nil
+}
+
+// ListScheduledTransfers: kullanıcının talimatları; status boşsa hepsi
+func ListScheduledTransfers(userID int, status string) ([]models.ScheduledTransfer, error) {
+	return database.ScheduledTransferRepo().ListByUser(userID, status)
+}
+
+// GetScheduledTransfer: talimat ve çalıştırma geçmişi (yalnızca sahibi görebilir)
+func GetScheduledTransfer(userID, id int) (*models.ScheduledTransfer, []models.ScheduledTransferRun, error) {
+	st, err := database.ScheduledTransferRepo().Get(id)
+	if err != nil || st.UserID != userID {
+		return nil, nil, errors.New("scheduled transfer not found")
+	}
+	runs, err := database.ScheduledTransferRepo().ListRuns(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return st, runs, nil
+}
+
+// UpdateScheduledTransfer: henüz çalışmamış talimatı değiştirir
+func UpdateScheduledTransfer(userID, id, toUserID int, amount models.Money, currency string, executeAt time.Time) (*models.ScheduledTransfer, error) {
+	st, _, err := GetScheduledTransfer(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyScheduledFields(st, toUserID, amount, currency, executeAt); err != nil {
+		return nil, err
+	}
+	if err := database.ScheduledTransferRepo().UpdatePending(st); err != nil {
+		return nil, err
+	}
+	_ = LogAction("scheduled_transfer", st.ID, "update", fmt.Sprintf("Rescheduled %s %s to user %d at %s", st.Amount, st.Currency, toUserID, executeAt.Format(time.RFC3339)))
+	return st, nil
+}
+
+// CancelScheduledTransfer: henüz çalışmamış talimatı iptal eder
+func CancelScheduledTransfer(userID, id int) error {
+	if _, _, err := GetScheduledTransfer(userID, id); err != nil {
+		return err
+	}
+	if err := database.ScheduledTransferRepo().Cancel(id, userID); err != nil {
+		return err
+	}
+	_ = LogAction("scheduled_transfer", id, "cancel", fmt.Sprintf("Cancelled by user %d", userID))
+	return nil
+}
+
+func applyScheduledFields(st *models.ScheduledTransfer, toUserID int, amount models.Money, currency string, executeAt time.Time) error {
+	if toUserID == 0 || toUserID == st.UserID {
+		return errors.New("invalid recipient")
+	}
+	if !amount.IsPositive() {
+		return errors.New("amount must be > 0")
+	}
+	code, err := resolveAmount(currency, amount)
+	if err != nil {
+		return err
+	}
+	if !executeAt.After(time.Now()) {
+		return errors.New("execute_at must be in the future")
+	}
+	st.ToUserID, st.Amount, st.Currency = toUserID, amount, code
+	st.ExecuteAt, st.NextRunAt = executeAt, executeAt
+	return nil
+}
+
+// RunDueScheduledTransfers: vadesi gelen talimatları sahiplenip services.TransferWith ile çalıştırır.
+// Birden fazla replikada eşzamanlı çalışabilir (bkz. ClaimDue).
+func RunDueScheduledTransfers(ctx context.Context) (int, error) {
+	cfg := config.GetScheduler()
+	total := 0
+	for ctx.Err() == nil {
+		due, err := database.ScheduledTransferRepo().ClaimDue(time.Now(), cfg.Lease, cfg.BatchSize)
+		if err != nil {
+			slog.Error("service.scheduled_transfer.claim_failed", "err", err)
+			return total, err
+		}
+		for i := range due {
+			executeScheduledTransfer(&due[i], cfg.RetryMax, cfg.RetryBackoff)
+		}
+		total += len(due)
+		if len(due) < cfg.BatchSize {
+			break
+		}
+	}
+	return total, nil
+}
+
+func executeScheduledTransfer(st *models.ScheduledTransfer, retryMax int, backoff time.Duration) {
+	origin := &models.TxOrigin{Type: models.OriginScheduledTransfer, ID: st.ID}
+	now := time.Now()
+	run := &models.ScheduledTransferRun{ScheduledTransferID: st.ID, Attempt: st.Attempts, ExecutedAt: now}
+
+	_, _, tx, err := TransferWith(st.UserID, st.ToUserID, st.Amount, st.Currency, models.TxOptions{Origin: origin})
+	if errors.Is(err, database.ErrDuplicateOrigin) {
+		// önceki deneme transferi yazdı ama sonucu kaydedemedi (ör. replika çöktü): tekrar para taşınmaz
+		tx, err = database.TransactionRepo().GetTransactionByOrigin(*origin)
+	}
+	switch {
+	case err == nil:
+		txID := tx.ID
+		st.Status, st.TransactionID, st.LastError = models.ScheduledCompleted, &txID, ""
+		run.Status, run.TransactionID = models.RunSucceeded, &txID
+	case err.Error() == "insufficient funds" && st.Attempts < retryMax:
+		st.Status, st.LastError = models.ScheduledPending, err.Error()
+		st.NextRunAt = now.Add(backoff * time.Duration(st.Attempts))
+		run.Status, run.Error = models.RunRetry, err.Error()
+	default:
+		st.Status, st.LastError = models.ScheduledFailed, err.Error()
+		run.Status, run.Error = models.RunFailed, err.Error()
+	}
+	if ferr := database.ScheduledTransferRepo().Finish(st, run); ferr != nil {
+		if errors.Is(ferr, database.ErrLeaseLost) {
+			// kaydı yeni sahibi sonuçlandırır; origin kontrolü çift transferi engeller
+			slog.Warn("service.scheduled_transfer.lease_lost", "id", st.ID, "attempt", run.Attempt)
+			return
+		}
+		// lease dolunca kayıt yeniden alınır; origin kontrolü çift transferi engeller
+		slog.Error("service.scheduled_transfer.finish_failed", "id", st.ID, "err", ferr)
+		return
+	}
+	_ = LogAction("scheduled_transfer", st.ID, "run", fmt.Sprintf("Attempt %d: %s %s", run.Attempt, run.Status, run.Error))
+	slog.Info("service.scheduled_transfer.run", "id", st.ID, "attempt", run.Attempt, "status", run.Status, "next_run_at", st.NextRunAt)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+)
+
+// maxSplitRecipients: tek bölünmüş ödemedeki azami alıcı sayısı
+const maxSplitRecipients = 100
+
+// SplitTransfer: tek tutarı birden çok alıcıya (tutar ya da yüzde ile) atomik olarak dağıtır.
+// amount sıfırsa ve paylar tutarla verildiyse toplam paylardan hesaplanır. Ebeveyn işlem ve payları döner.
+func SplitTransfer(fromUserID int, amount models.Money, currency string, parts []models.SplitPart) (models.Money, *models.Transaction, error) {
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := cur.CheckPrecision(amount); err != nil {
+		return 0, nil, err
+	}
+	if amount.IsNegative() {
+		return 0, nil, errors.New("amount must be positive")
+	}
+	if len(parts) < 2 || len(parts) > maxSplitRecipients {
+		return 0, nil, fmt.Errorf("split requires between 2 and %d recipients", maxSplitRecipients)
+	}
+	seen := make(map[int]bool, len(parts))
+	for _, p := range parts {
+		if p.ToUserID <= 0 || p.ToUserID == fromUserID || seen[p.ToUserID] {
+			return 0, nil, errors.New("invalid recipient")
+		}
+		seen[p.ToUserID] = true
+	}
+	if amount, err = models.ResolveSplit(amount, parts, cur); err != nil {
+		return 0, nil, err
+	}
+	slog.Info("service.split.start", "from_user_id", fromUserID, "amount", amount, "currency", cur.Code, "recipients", len(parts))
+
+	limit, err := ResolveLimits(fromUserID, cur.Code)
+	if err != nil {
+		return 0, nil, err
+	}
+	fee, err := QuoteFee(fromUserID, "transfer", amount, cur.Code)
+	if err != nil {
+		return 0, nil, err
+	}
+	fromNew, tx, err := database.TransactionRepo().SplitAtomic(fromUserID, amount, cur.Code, parts, models.TxOptions{Limit: limit, Fee: fee})
+	if err != nil {
+		if auditLimitViolation(fromUserID, "split", amount, err) {
+			return 0, nil, err
+		}
+		switch err.Error() {
+		case "insufficient funds":
+			slog.Warn("service.split.insufficient_funds", "from_user_id", fromUserID, "amount", amount)
+		case "sender balance not found", "recipient balance not found":
+			slog.Error("service.split.balance_not_found", "from_user_id", fromUserID, "err", err)
+		default:
+			slog.Error("service.split.failed", "from_user_id", fromUserID, "err", err)
+		}
+		return 0, nil, err
+	}
+	_ = LogAction("transaction", tx.ID, models.TxTypeSplit, fmt.Sprintf("Split %s %s from user %d to %d recipients%s", amount, cur.Code, fromUserID, len(parts), feeAuditSuffix(fee)))
+	slog.Info("service.split.success", "from_user_id", fromUserID, "transaction_id", tx.ID, "from_new", fromNew)
+	return fromNew, tx, nil
+}
+
+// GroupTransactions: ebeveyni listede bulunan bölünmüş ödeme paylarını ebeveynin Legs alanına taşır.
+// Ebeveyni görmeyen (yalnızca alıcı olan) kullanıcı için paylar tek başına listelenir.
+func GroupTransactions(txs []*models.Transaction) []*models.Transaction {
+	parents := make(map[int]*models.Transaction)
+	for _, t := range txs {
+		if t.Type == models.TxTypeSplit {
+			t.Legs = nil
+			parents[t.ID] = t
+		}
+	}
+	out := make([]*models.Transaction, 0, len(txs))
+	for _, t := range txs {
+		if t.Type == models.TxTypeSplitPart && t.ParentID != nil {
+			if p, ok := parents[*t.ParentID]; ok {
+				p.Legs = append(p.Legs, t)
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
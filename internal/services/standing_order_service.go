@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"time"
+)
+
+// maxCatchUpScan: kaçırılan occurrence sayımında taranacak en fazla adım
+const maxCatchUpScan = 1000
+
+// CreateStandingOrder: düzenli transfer talimatı oluşturur; StartAt boşsa şimdi
+func CreateStandingOrder(o *models.StandingOrder) error {
+	if o.ToUserID == 0 || o.ToUserID == o.UserID {
+		return errors.New("invalid recipient")
+	}
+	if !o.Amount.IsPositive() {
+		return errors.New("amount must be > 0")
+	}
+	code, err := resolveAmount(o.Currency, o.Amount)
+	if err != nil {
+		return err
+	}
+	o.Currency = code
+	now := time.Now()
+	if o.StartAt.IsZero() {
+		o.StartAt = now
+	}
+	if o.StartAt.Before(now.Add(-time.Minute)) {
+		return errors.New("start_at must not be in the past")
+	}
+	o.StartAt = o.StartAt.UTC().Truncate(time.Second)
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	first := o.OccurrenceAfter(o.StartAt.Add(-time.Nanosecond))
+	if !o.Due(first) {
+		return errors.New("no occurrence before end_at")
+	}
+	o.NextRunAt, o.Status, o.Occurrences = &first, models.StandingOrderActive, 0
+	if err := database.StandingOrderRepo().Create(o); err != nil {
+		slog.Error("service.standing_order.create_failed", "user_id", o.UserID, "err", err)
+		return err
+	}
+	_ = LogAction("standing_order", o.ID, "create", fmt.Sprintf("%s %s %s from user %d to user %d, first run %s",
+		o.Frequency, o.Amount, o.Currency, o.UserID, o.ToUserID, first.Format(time.RFC3339)))
+	slog.Info("service.standing_order.created", "id", o.ID, "user_id", o.UserID, "next_run_at", first)
+	return nil
+}
+
+func ListStandingOrders(userID int, status string) ([]models.StandingOrder, error) {
+	return database.StandingOrderRepo().ListByUser(userID, status)
+}
+
+// GetStandingOrder: yalnızca sahibi görebilir
+func GetStandingOrder(userID, id int) (*models.StandingOrder, error) {
+	o, err := database.StandingOrderRepo().Get(id)
+	if err != nil || o.UserID != userID {
+		return nil, errors.New("standing order not found")
+	}
+	return o, nil
+}
+
+// GetStandingOrderRuns: talimatın tüm occurrence sonuçları
+func GetStandingOrderRuns(userID, id int) ([]models.StandingOrderRun, error) {
+	if _, err := GetStandingOrder(userID, id); err != nil {
+		return nil, err
+	}
+	return database.StandingOrderRepo().ListRuns(id)
+}
+
+// PauseStandingOrder: aktif talimatı duraklatır
+func PauseStandingOrder(userID, id int) error {
+	return transitionStandingOrder(userID, id, []string{models.StandingOrderActive}, models.StandingOrderPaused, nil)
+}
+
+// ResumeStandingOrder: duraklatılmış talimatı sürdürür; duraklama süresince kaçırılan occurrence'lar çalıştırılmaz
+func ResumeStandingOrder(userID, id int) error {
+	o, err := GetStandingOrder(userID, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var next *time.Time
+	if o.NextRunAt == nil || o.NextRunAt.Before(now) {
+		n := o.OccurrenceAfter(now)
+		if !o.Due(n) {
+			return errors.New("no occurrence left before end_at")
+		}
+		next = &n
+	}
+	return transitionStandingOrder(userID, id, []string{models.StandingOrderPaused}, models.StandingOrderActive, next)
+}
+
+// CancelStandingOrder: talimatı kalıcı olarak iptal eder
+func CancelStandingOrder(userID, id int) error {
+	return transitionStandingOrder(userID, id, []string{models.StandingOrderActive, models.StandingOrderPaused}, models.StandingOrderCancelled, nil)
+}
+
+func transitionStandingOrder(userID, id int, from []string, to string, next *time.Time) error {
+	if _, err := GetStandingOrder(userID, id); err != nil {
+		return err
+	}
+	if err := database.StandingOrderRepo().Transition(id, userID, from, to, next); err != nil {
+		return err
+	}
+	_ = LogAction("standing_order", id, to, fmt.Sprintf("Status changed to %s by user %d", to, userID))
+	slog.Info("service.standing_order.transition", "id", id, "status", to)
+	return nil
+}
+
+// RunDueStandingOrders: vadesi gelen talimatların birer occurrence'ını çalıştırır.
+// Kesinti sonrası birden fazla occurrence kaçırılmışsa yalnızca bir kez çalıştırılır, sonraki çalıştırma şimdiden sonrasına kurulur.
+func RunDueStandingOrders(ctx context.Context) (int, error) {
+	cfg := config.GetScheduler()
+	total := 0
+	for ctx.Err() == nil {
+		due, err := database.StandingOrderRepo().ClaimDue(time.Now(), cfg.Lease, cfg.BatchSize)
+		if err != nil {
+			slog.Error("service.standing_order.claim_failed", "err", err)
+			return total, err
+		}
+		for i := range due {
+			executeStandingOrder(&due[i])
+		}
+		total += len(due)
+		if len(due) < cfg.BatchSize {
+			break
+		}
+	}
+	return total, nil
+}
+
+func executeStandingOrder(o *models.StandingOrder) {
+	now := time.Now()
+	scheduledFor := *o.NextRunAt
+	seq := o.Occurrences + 1
+	origin := &models.TxOrigin{Type: models.OriginStandingOrder, ID: o.ID, Seq: seq}
+	run := &models.StandingOrderRun{StandingOrderID: o.ID, Seq: seq, ScheduledFor: scheduledFor, ExecutedAt: now}
+
+	// scheduledFor ile şimdi arasında kalan (kaçırılmış) occurrence'lar telafi edilmez, yalnızca sayılır
+	next := o.OccurrenceAfter(scheduledFor)
+	for i := 0; !next.After(now) && i < maxCatchUpScan; i++ {
+		run.Skipped++
+		next = o.OccurrenceAfter(next)
+	}
+
+	_, _, tx, err := TransferWith(o.UserID, o.ToUserID, o.Amount, o.Currency, models.TxOptions{Origin: origin})
+	if errors.Is(err, database.ErrDuplicateOrigin) {
+		// önceki deneme transferi yazdı ama sonucu kaydedemedi: aynı occurrence tekrar ödenmez
+		tx, err = database.TransactionRepo().GetTransactionByOrigin(*origin)
+	}
+	if err == nil {
+		txID := tx.ID
+		run.Status, run.TransactionID = models.RunSucceeded, &txID
+		o.LastError = ""
+	} else {
+		run.Status, run.Error = models.RunFailed, err.Error()
+		o.LastError = err.Error()
+	}
+
+	o.Occurrences = seq
+	if o.Due(next) {
+		o.NextRunAt = &next
+	} else {
+		o.NextRunAt, o.Status = nil, models.StandingOrderCompleted
+	}
+	if ferr := database.StandingOrderRepo().Finish(o, run); ferr != nil {
+		if errors.Is(ferr, database.ErrLeaseLost) {
+			// occurrence'ı yeni sahibi sonuçlandırır; origin seq'i çift transferi engeller
+			slog.Warn("service.standing_order.lease_lost", "id", o.ID, "seq", seq)
+			return
+		}
+		slog.Error("service.standing_order.finish_failed", "id", o.ID, "seq", seq, "err", ferr)
+		return
+	}
+	_ = LogAction("standing_order", o.ID, "run", fmt.Sprintf("Occurrence %d (%s): %s %s", seq, scheduledFor.Format(time.RFC3339), run.Status, run.Error))
+	slog.Info("service.standing_order.run", "id", o.ID, "seq", seq, "status", run.Status, "skipped", run.Skipped, "next_run_at", o.NextRunAt)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"insider-go-backend/internal/config"
+	"insider-go-backend/internal/database"
+	"insider-go-backend/internal/models"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// GenerateStatement: cüzdanın month ayı için ekstresini üretir; zaten üretilmişse mevcut ekstreyi döner.
+// Yalnızca kapanmış (ay sonu + STATEMENT_LAG geçmiş) aylar için üretilebilir, böylece içerik sonradan değişmez.
+func GenerateStatement(userID int, currency string, month time.Time) (*models.Statement, error) {
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	if end.After(time.Now().Add(-config.GetStatements().Lag)) {
+		return nil, errors.New("period is not closed yet")
+	}
+	repo := database.StatementRepo()
+	if s, err := repo.GetByPeriod(userID, cur.Code, start); err == nil {
+		return s, nil
+	} else if !database.IsNotFound(err) {
+		return nil, err
+	}
+	if _, err := database.BalanceRepo().GetBalance(userID, cur.Code); err != nil {
+		return nil, errors.New("balance not found")
+	}
+
+	opening, err := database.CheckpointRepo().BalanceAt(userID, cur.Code, start.Add(-time.Microsecond))
+	if err != nil {
+		slog.Error("service.statement.opening_failed", "user_id", userID, "currency", cur.Code, "err", err)
+		return nil, err
+	}
+	lines, err := repo.Lines(userID, cur.Code, start, end)
+	if err != nil {
+		slog.Error("service.statement.lines_failed", "user_id", userID, "currency", cur.Code, "err", err)
+		return nil, err
+	}
+	s := models.BuildStatement(userID, cur.Code, start, end, opening, lines)
+	s.GeneratedAt = time.Now()
+	if err := s.Seal(); err != nil {
+		return nil, err
+	}
+	created, err := repo.Create(s)
+	if err != nil {
+		slog.Error("service.statement.create_failed", "user_id", userID, "currency", cur.Code, "err", err)
+		return nil, err
+	}
+	if !created {
+		// paralel üretim: ilk yazılan ekstre geçerlidir
+		return repo.GetByPeriod(userID, cur.Code, start)
+	}
+	_ = LogAction("statement", s.ID, "generate", fmt.Sprintf("%s statement for user %d, %s: opening %s, closing %s, %d lines, sha256 %s",
+		cur.Code, userID, start.Format("2006-01"), s.OpeningBalance, s.ClosingBalance, s.LineCount, s.ContentHash))
+	slog.Info("service.statement.generated", "id", s.ID, "user_id", userID, "currency", cur.Code, "period", start.Format("2006-01"))
+	return s, nil
+}
+
+// GenerateMonthlyStatements: kapanmış son ay için ekstresi olmayan tüm cüzdanların ekstresini üretir (ay sonu job'u)
+func GenerateMonthlyStatements(now time.Time) (int, error) {
+	closed := now.UTC().Add(-config.GetStatements().Lag)
+	end := time.Date(closed.Year(), closed.Month(), 1, 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, -1, 0)
+	wallets, err := database.StatementRepo().MissingWallets(start, end)
+	if err != nil {
+		slog.Error("service.statement.scan_failed", "err", err)
+		return 0, err
+	}
+	count := 0
+	for _, w := range wallets {
+		if _, err := GenerateStatement(w.UserID, w.Currency, start); err != nil {
+			slog.Error("service.statement.generate_failed", "user_id", w.UserID, "currency", w.Currency, "err", err)
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		slog.Info("service.statement.monthly_generated", "period", start.Format("2006-01"), "count", count)
+	}
+	return count, nil
+}
+
+// ListStatements: kullanıcının ekstreleri (satırlar olmadan)
+func ListStatements(userID int, currency string) ([]models.Statement, error) {
+	return database.StatementRepo().List(userID, strings.ToUpper(currency))
+}
+
+// GetStatement: ekstreyi satırlarıyla döner; sahibi ve admin görebilir. İçerik hash'i tutmuyorsa hata döner.
+func GetStatement(userID int, isAdmin bool, id int) (*models.Statement, error) {
+	s, err := database.StatementRepo().Get(id)
+	if err != nil || (!isAdmin && s.UserID != userID) {
+		return nil, errors.New("statement not found")
+	}
+	if !s.Verify() {
+		slog.Error("service.statement.hash_mismatch", "id", id, "user_id", s.UserID)
+		return nil, errors.New("statement integrity check failed")
+	}
+	if err := s.Unseal(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -1,54 +1,130 @@
 package services
 
 import (
+	"errors"
 	"fmt"
+	"insider-go-backend/internal/config"
 	"insider-go-backend/internal/database"
 	"insider-go-backend/internal/models"
 	"log/slog"
 )
 
+// ErrTransactionForbidden: işlem metadata'sını yalnızca işlemi başlatan kullanıcı düzenleyebilir
+var ErrTransactionForbidden = errors.New("not allowed to modify this transaction")
+
 // Credit: kullanıcı bakiyesine para ekler ve transaction kaydı oluşturur
-func Credit(userID int, amount float64) (float64, error) {
-	slog.Info("service.credit.start", "user_id", userID, "amount", amount)
-	newBal, tx, err := database.TransactionRepo().CreditAtomic(userID, amount)
+func Credit(userID int, amount models.Money, currency string) (models.Money, error) {
+	newBal, _, err := CreditWith(userID, amount, currency, models.TxOptions{})
+	return newBal, err
+}
+
+// CreditWith: Credit'in seçenekli hali (ör. memo/etiket metadata'sı); oluşan kaydı da döner
+func CreditWith(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := normalizeMeta(opts.Meta); err != nil {
+		return 0, nil, err
+	}
+	slog.Info("service.credit.start", "user_id", userID, "amount", amount, "currency", currency)
+	newBal, tx, err := database.TransactionRepo().CreditAtomic(userID, amount, currency, opts)
 	if err != nil {
-		if err.Error() == "balance not found" {
+		switch err.Error() {
+		case "balance not found":
 			slog.Error("service.credit.balance_not_found", "user_id", userID, "err", err)
-		} else {
+		case database.ErrDuplicateExternalRef.Error():
+			slog.Warn("service.credit.duplicate_external_ref", "user_id", userID)
+		default:
 			slog.Error("service.credit.failed", "user_id", userID, "err", err)
 		}
-		return 0, err
+		return 0, nil, err
 	}
 	// audit log
-	_ = LogAction("transaction", tx.ID, "credit", "Credited amount: "+fmt.Sprintf("%.2f", amount))
+	_ = LogAction("transaction", tx.ID, "credit", "Credited amount: "+amount.String()+" "+currency)
 	slog.Info("service.credit.success", "user_id", userID, "new_balance", newBal)
-	return newBal, nil
+	return newBal, tx, nil
 }
 
 // Debit: kullanıcı bakiyesinden para düşer ve transaction kaydı oluşturur
-func Debit(userID int, amount float64) (float64, error) {
-	slog.Info("service.debit.start", "user_id", userID, "amount", amount)
-	newBal, tx, err := database.TransactionRepo().DebitAtomic(userID, amount)
+func Debit(userID int, amount models.Money, currency string) (models.Money, error) {
+	newBal, _, err := DebitWith(userID, amount, currency, models.TxOptions{})
+	return newBal, err
+}
+
+// DebitWith: Debit'in seçenekli hali; limit ve ücret verilmemişse çözülür, oluşan kaydı da döner
+func DebitWith(userID int, amount models.Money, currency string, opts models.TxOptions) (models.Money, *models.Transaction, error) {
+	currency, err := resolveAmount(currency, amount)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := normalizeMeta(opts.Meta); err != nil {
+		return 0, nil, err
+	}
+	slog.Info("service.debit.start", "user_id", userID, "amount", amount, "currency", currency)
+	if opts.Limit == nil {
+		if opts.Limit, err = ResolveLimits(userID, currency); err != nil {
+			return 0, nil, err
+		}
+	}
+	if opts.Fee == nil {
+		if opts.Fee, err = QuoteFee(userID, "debit", amount, currency); err != nil {
+			return 0, nil, err
+		}
+	}
+	newBal, tx, err := database.TransactionRepo().DebitAtomic(userID, amount, currency, opts)
 	if err != nil {
-		if err.Error() == "insufficient funds" {
+		if auditLimitViolation(userID, "debit", amount, err) {
+			return 0, nil, err
+		}
+		switch err.Error() {
+		case "insufficient funds":
 			slog.Warn("service.debit.insufficient_funds", "user_id", userID, "amount", amount)
-		} else if err.Error() == "balance not found" {
+		case "balance not found":
 			slog.Error("service.debit.balance_not_found", "user_id", userID, "err", err)
-		} else {
+		case database.ErrDuplicateExternalRef.Error():
+			slog.Warn("service.debit.duplicate_external_ref", "user_id", userID)
+		default:
 			slog.Error("service.debit.failed", "user_id", userID, "err", err)
 		}
-		return 0, err
+		return 0, nil, err
 	}
-	_ = LogAction("transaction", tx.ID, "debit", "Debited amount: "+fmt.Sprintf("%.2f", amount))
+	_ = LogAction("transaction", tx.ID, "debit", "Debited amount: "+amount.String()+" "+currency+feeAuditSuffix(opts.Fee))
 	slog.Info("service.debit.success", "user_id", userID, "new_balance", newBal)
-	return newBal, nil
+	return newBal, tx, nil
 }
 
 // Para transferi: iki bakiye arasında aktarım yapar, transaction kaydı oluşturur; yeni bakiyeleri döner
-func Transfer(fromUserID, toUserID int, amount float64) (fromNew float64, toNew float64, err error) {
-	slog.Info("service.transfer.start", "from_user_id", fromUserID, "to_user_id", toUserID, "amount", amount)
-	fromNew, toNew, tx, err := database.TransactionRepo().TransferAtomic(fromUserID, toUserID, amount)
+func Transfer(fromUserID, toUserID int, amount models.Money, currency string) (fromNew models.Money, toNew models.Money, err error) {
+	fromNew, toNew, _, err = TransferWith(fromUserID, toUserID, amount, currency, models.TxOptions{})
+	return fromNew, toNew, err
+}
+
+// TransferWith: Transfer'in seçenekli hali (ör. zamanlanmış transferin origin bağlantısı); oluşan kaydı da döner
+func TransferWith(fromUserID, toUserID int, amount models.Money, currency string, opts models.TxOptions) (fromNew models.Money, toNew models.Money, tx *models.Transaction, err error) {
+	currency, err = resolveAmount(currency, amount)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if err = normalizeMeta(opts.Meta); err != nil {
+		return 0, 0, nil, err
+	}
+	slog.Info("service.transfer.start", "from_user_id", fromUserID, "to_user_id", toUserID, "amount", amount, "currency", currency)
+	if opts.Limit == nil {
+		if opts.Limit, err = ResolveLimits(fromUserID, currency); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	if opts.Fee == nil {
+		if opts.Fee, err = QuoteFee(fromUserID, "transfer", amount, currency); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	fromNew, toNew, tx, err = database.TransactionRepo().TransferAtomic(fromUserID, toUserID, amount, currency, opts)
 	if err != nil {
+		if auditLimitViolation(fromUserID, "transfer", amount, err) {
+			return 0, 0, nil, err
+		}
 		switch err.Error() {
 		case "insufficient funds":
 			slog.Warn("service.transfer.insufficient_funds", "from_user_id", fromUserID, "amount", amount)
@@ -56,14 +132,20 @@ func Transfer(fromUserID, toUserID int, amount float64) (fromNew float64, toNew
 			slog.Error("service.transfer.sender_balance_not_found", "from_user_id", fromUserID, "err", err)
 		case "recipient balance not found":
 			slog.Error("service.transfer.recipient_balance_not_found", "to_user_id", toUserID, "err", err)
+		case "currency mismatch":
+			slog.Warn("service.transfer.currency_mismatch", "to_user_id", toUserID, "currency", currency)
+		case database.ErrDuplicateOrigin.Error():
+			slog.Warn("service.transfer.duplicate_origin", "from_user_id", fromUserID, "origin", opts.Origin)
+		case database.ErrDuplicateExternalRef.Error():
+			slog.Warn("service.transfer.duplicate_external_ref", "from_user_id", fromUserID)
 		default:
 			slog.Error("service.transfer.failed", "from_user_id", fromUserID, "to_user_id", toUserID, "err", err)
 		}
-		return 0, 0, err
+		return 0, 0, nil, err
 	}
-	_ = LogAction("transaction", tx.ID, "transfer", fmt.Sprintf("Transferred amount: %.2f from user %d to user %d", amount, fromUserID, toUserID))
+	_ = LogAction("transaction", tx.ID, "transfer", fmt.Sprintf("Transferred amount: %s %s from user %d to user %d%s", amount, currency, fromUserID, toUserID, feeAuditSuffix(opts.Fee)))
 	slog.Info("service.transfer.success", "from_user_id", fromUserID, "to_user_id", toUserID, "from_new", fromNew, "to_new", toNew)
-	return fromNew, toNew, nil
+	return fromNew, toNew, tx, nil
 }
 
 // Sorgular
@@ -76,3 +158,84 @@ func GetTransactionByID(id int) (*models.Transaction, error) {
 	slog.Info("service.transactions.get_by_id", "id", id)
 	return database.TransactionRepo().GetTransactionByID(id)
 }
+
+// ListTransactionHistory: kullanıcının işlem geçmişinin bir sayfası; sonraki sayfa için NextCursor döner.
+// Gönderenin bölünmüş ödemelerinin payları ebeveynin Legs alanına yüklenir.
+func ListTransactionHistory(userID int, f models.TxFilter) (*models.TxPage, error) {
+	if err := f.Normalize(); err != nil {
+		return nil, err
+	}
+	slog.Info("service.transactions.history", "user_id", userID, "limit", f.Limit, "cursor", f.Cursor != nil)
+	txs, err := database.TransactionRepo().ListTransactionsByUser(userID, f)
+	if err != nil {
+		slog.Error("service.transactions.history_failed", "user_id", userID, "err", err)
+		return nil, err
+	}
+	page := &models.TxPage{Transactions: txs}
+	if len(txs) > f.Limit {
+		page.Transactions = txs[:f.Limit]
+		last := page.Transactions[f.Limit-1]
+		page.NextCursor = models.TxCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	var splits []int
+	for _, t := range page.Transactions {
+		if t.Type == models.TxTypeSplit {
+			splits = append(splits, t.ID)
+		}
+	}
+	parts, err := database.TransactionRepo().GetChildTransactions(splits, models.TxTypeSplitPart)
+	if err != nil {
+		return nil, err
+	}
+	page.Transactions = GroupTransactions(append(page.Transactions, parts...))
+	return page, nil
+}
+
+// UpdateTransactionMeta: işlemi başlatan kullanıcı memo, kategori ve etiketleri düzenleyebilir; external_ref değişmez
+func UpdateTransactionMeta(actorID, txID int, patch models.TxMetaPatch) (*models.Transaction, error) {
+	tx, err := database.TransactionRepo().GetTransactionByID(txID)
+	if err != nil {
+		return nil, errors.New("transaction not found")
+	}
+	if tx.FromUser != actorID {
+		return nil, ErrTransactionForbidden
+	}
+	if err := patch.Apply(tx); err != nil {
+		return nil, err
+	}
+	if err := database.TransactionRepo().UpdateTransactionMeta(tx); err != nil {
+		slog.Error("service.transactions.update_meta_failed", "id", txID, "err", err)
+		return nil, err
+	}
+	_ = LogAction("transaction", tx.ID, "update_meta", fmt.Sprintf("Metadata updated by user %d: category=%q tags=%v", actorID, tx.Category, []string(tx.Tags)))
+	slog.Info("service.transactions.update_meta", "id", txID, "user_id", actorID)
+	return tx, nil
+}
+
+// normalizeMeta: istekle gelen metadata'yı normalize eder ve doğrular (nil ise dokunmaz)
+func normalizeMeta(m *models.TxMeta) error {
+	if m == nil {
+		return nil
+	}
+	return m.Normalize()
+}
+
+// resolveCurrency: para birimi kodunu doğrular; boşsa varsayılan para birimini kullanır
+func resolveCurrency(currency string) (models.Currency, error) {
+	if currency == "" {
+		currency = config.DefaultCurrency()
+	}
+	return models.LookupCurrency(currency)
+}
+
+// resolveAmount: para birimini çözer ve tutarın minor unit kuralına uyduğunu doğrular
+func resolveAmount(currency string, amount models.Money) (string, error) {
+	cur, err := resolveCurrency(currency)
+	if err != nil {
+		return "", err
+	}
+	if err := cur.CheckPrecision(amount); err != nil {
+		return "", err
+	}
+	return cur.Code, nil
+}
@@ -220,17 +220,22 @@ func CheckUserRole(user *models.User, role string) bool {
 	return user.Role == role
 }
 
-// kullanıcı için bakiye oluştur
-func CreateBalanceForUser(userID int, initialAmount float64) error {
-	slog.Info("service.user.create_balance", "user_id", userID, "initial_amount", initialAmount)
+// kullanıcı için bakiye (para birimi cüzdanı) oluştur
+func CreateBalanceForUser(userID int, currency string, initialAmount models.Money) error {
+	currency, err := resolveAmount(currency, initialAmount)
+	if err != nil {
+		return err
+	}
+	slog.Info("service.user.create_balance", "user_id", userID, "currency", currency, "initial_amount", initialAmount)
 	balance := &models.Balance{
 		UserID:      userID,
+		Currency:    currency,
 		Amount:      initialAmount,
 		LastUpdated: time.Now(),
 	}
 
 	// bakiye oluşturma için denetim (audit) kaydı
-	_ = LogAction("balance", userID, "create", "initial balance created for user")
+	_ = LogAction("balance", userID, "create", "initial "+currency+" balance created for user")
 
 	if err := database.BalanceRepo().CreateBalance(balance); err != nil {
 		slog.Error("service.user.create_balance_failed", "user_id", userID, "err", err)